@@ -3,7 +3,14 @@
 
 package chain
 
-import "github.com/ava-labs/avalanchego/ids"
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type Activity struct {
 	Tmstmp int64  `serialize:"true" json:"timestamp"`
@@ -15,3 +22,126 @@ type Activity struct {
 	To     string `serialize:"true" json:"to,omitempty"` // common.Address will be 0x000 when not populated
 	Units  uint64 `serialize:"true" json:"units,omitempty"`
 }
+
+// addresses returns the distinct, non-empty addresses involved in [a].
+func (a *Activity) addresses() []common.Address {
+	addrs := []common.Address{}
+	for _, s := range []string{a.Sender, a.To} {
+		if len(s) == 0 {
+			continue
+		}
+		addr := common.HexToAddress(s)
+		if addr == (common.Address{}) || (len(addrs) > 0 && addrs[0] == addr) {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// BlockActivity returns the activity of the transactions in [blk], each
+// followed by the lottery reward it distributed (if any).
+func BlockActivity(blk *StatelessBlock) []*Activity {
+	activity := make([]*Activity, 0, len(blk.Txs))
+	for _, tx := range blk.Txs {
+		a := tx.Activity()
+		a.Tmstmp = blk.Tmstmp
+		activity = append(activity, a)
+		if reward, ok := blk.Winners[tx.ID()]; ok {
+			activity = append(activity, reward)
+		}
+	}
+	return activity
+}
+
+// PutActivity indexes [a] by time, by the addresses involved, and by its
+// space. [index] must be unique within the block at [height].
+func PutActivity(db database.KeyValueWriter, a *Activity, height uint64, index uint64) error {
+	b, err := Marshal(a)
+	if err != nil {
+		return err
+	}
+	t := uint64(a.Tmstmp)
+	if err := db.Put(activityKey(activityBaseKey(activityPrefix, nil), t, height, index), b); err != nil {
+		return err
+	}
+	for _, addr := range a.addresses() {
+		k := activityKey(activityBaseKey(addrActivityPrefix, addr[:]), t, height, index)
+		if err := db.Put(k, b); err != nil {
+			return err
+		}
+	}
+	if len(a.Space) > 0 {
+		k := activityKey(activityBaseKey(spaceActivityPrefix, []byte(a.Space)), t, height, index)
+		if err := db.Put(k, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetActivityRange returns up to [limit] activities in [since, until] (oldest
+// first), starting after [cursor] if provided. If [until] is 0, there is no
+// upper bound. [next] is non-nil if there are more results and should be
+// passed as [cursor] to fetch the next page.
+func GetActivityRange(
+	db database.Database,
+	since uint64, until uint64, cursor []byte, limit int,
+) (activity []*Activity, next []byte, err error) {
+	return getActivity(db, activityBaseKey(activityPrefix, nil), since, until, cursor, limit)
+}
+
+// GetAddressActivity behaves like [GetActivityRange] but only returns
+// activities sent or received by [addr].
+func GetAddressActivity(
+	db database.Database, addr common.Address,
+	since uint64, until uint64, cursor []byte, limit int,
+) (activity []*Activity, next []byte, err error) {
+	return getActivity(db, activityBaseKey(addrActivityPrefix, addr[:]), since, until, cursor, limit)
+}
+
+// GetSpaceActivity behaves like [GetActivityRange] but only returns
+// activities in [space].
+func GetSpaceActivity(
+	db database.Database, space []byte,
+	since uint64, until uint64, cursor []byte, limit int,
+) (activity []*Activity, next []byte, err error) {
+	return getActivity(db, activityBaseKey(spaceActivityPrefix, space), since, until, cursor, limit)
+}
+
+func getActivity(
+	db database.Database, baseKey []byte,
+	since uint64, until uint64, cursor []byte, limit int,
+) (activity []*Activity, next []byte, err error) {
+	start := activityKey(baseKey, since, 0, 0)
+	if len(cursor) > 0 {
+		if len(cursor) != len(baseKey)+activitySuffixLen || !bytes.HasPrefix(cursor, baseKey) {
+			return nil, nil, ErrInvalidCursor
+		}
+		// Keys have a fixed length, so appending a byte yields the smallest key
+		// after [cursor].
+		start = append(append([]byte{}, cursor...), 0x0)
+	}
+
+	iter := db.NewIteratorWithStartAndPrefix(start, baseKey)
+	defer iter.Release()
+	activity = []*Activity{}
+	var last []byte
+	for iter.Next() {
+		k := iter.Key()
+		if t := binary.BigEndian.Uint64(k[len(baseKey):]); until > 0 && t > until {
+			break
+		}
+		if len(activity) == limit {
+			next = last
+			break
+		}
+		a := new(Activity)
+		if _, err := Unmarshal(iter.Value(), a); err != nil {
+			return nil, nil, err
+		}
+		activity = append(activity, a)
+		last = append([]byte{}, k...)
+	}
+	return activity, next, iter.Error()
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestActivityIndex(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+
+	alice, bob := common.Address{0x1}, common.Address{0x2}
+	activity := []*Activity{
+		{Tmstmp: 10, Typ: Claim, Sender: alice.Hex(), Space: "foo"},
+		{Tmstmp: 10, Typ: Reward, To: bob.Hex()},
+		{Tmstmp: 20, Typ: Set, Sender: alice.Hex(), Space: "foo", Key: "a"},
+		{Tmstmp: 30, Typ: Transfer, Sender: bob.Hex(), To: alice.Hex(), Units: 5},
+		{Tmstmp: 40, Typ: Claim, Sender: bob.Hex(), Space: "fo"},
+	}
+	for i, a := range activity {
+		if err := PutActivity(db, a, uint64(a.Tmstmp), uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tt := []struct {
+		fetch    func(cursor []byte) ([]*Activity, []byte, error)
+		expected []int
+	}{
+		{ // all activity, 2 per page
+			fetch: func(cursor []byte) ([]*Activity, []byte, error) {
+				return GetActivityRange(db, 0, 0, cursor, 2)
+			},
+			expected: []int{0, 1, 2, 3, 4},
+		},
+		{ // time range is inclusive
+			fetch: func(cursor []byte) ([]*Activity, []byte, error) {
+				return GetActivityRange(db, 20, 30, cursor, 10)
+			},
+			expected: []int{2, 3},
+		},
+		{ // sent and received by address
+			fetch: func(cursor []byte) ([]*Activity, []byte, error) {
+				return GetAddressActivity(db, alice, 0, 0, cursor, 1)
+			},
+			expected: []int{0, 2, 3},
+		},
+		{
+			fetch: func(cursor []byte) ([]*Activity, []byte, error) {
+				return GetAddressActivity(db, bob, 15, 0, cursor, 10)
+			},
+			expected: []int{3, 4},
+		},
+		{ // space must match exactly
+			fetch: func(cursor []byte) ([]*Activity, []byte, error) {
+				return GetSpaceActivity(db, []byte("foo"), 0, 0, cursor, 10)
+			},
+			expected: []int{0, 2},
+		},
+	}
+	for i, tv := range tt {
+		var (
+			cursor []byte
+			found  []*Activity
+		)
+		for {
+			page, next, err := tv.fetch(cursor)
+			if err != nil {
+				t.Fatalf("#%d: unexpected error %v", i, err)
+			}
+			found = append(found, page...)
+			if len(next) == 0 {
+				break
+			}
+			cursor = next
+		}
+		if len(found) != len(tv.expected) {
+			t.Fatalf("#%d: expected %d activities, got %d", i, len(tv.expected), len(found))
+		}
+		for j, k := range tv.expected {
+			if *found[j] != *activity[k] {
+				t.Fatalf("#%d: activity %d expected %+v, got %+v", i, j, activity[k], found[j])
+			}
+		}
+	}
+
+	if _, _, err := GetSpaceActivity(db, []byte("foo"), 0, 0, []byte("bad"), 10); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidCursor)
+	}
+}
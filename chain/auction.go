@@ -0,0 +1,226 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// Auction tracks the bidding for an unclaimed space. It is opened when the
+// space expires (or, for a space that was never claimed or whose last
+// auction had no bids, by the first [BidTx]) and settled once [End] has
+// passed.
+type Auction struct {
+	Space string `serialize:"true" json:"space"`
+	Start uint64 `serialize:"true" json:"start"`
+	End   uint64 `serialize:"true" json:"end"`
+
+	HighBidder common.Address `serialize:"true" json:"highBidder"`
+	HighBid    uint64         `serialize:"true" json:"highBid"`
+}
+
+// SpaceBid is the amount a bidder has escrowed for an [Auction].
+type SpaceBid struct {
+	Bidder  common.Address `serialize:"true" json:"bidder"`
+	Units   uint64         `serialize:"true" json:"units"`
+	Updated uint64         `serialize:"true" json:"updated"`
+}
+
+func GetAuction(db database.KeyValueReader, space []byte) (*Auction, bool, error) {
+	// [auctionPrefix] + [delimiter] + [space]
+	v, err := db.Get(PrefixAuctionKey(space))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	a := new(Auction)
+	if _, err := Unmarshal(v, a); err != nil {
+		return nil, false, err
+	}
+	return a, true, nil
+}
+
+func PutAuction(db database.KeyValueWriter, a *Auction) error {
+	b, err := Marshal(a)
+	if err != nil {
+		return err
+	}
+	return db.Put(PrefixAuctionKey([]byte(a.Space)), b)
+}
+
+// openAuction starts an auction for [space] that lasts [Genesis.AuctionWindow]
+// seconds from [start].
+func openAuction(g *Genesis, db database.KeyValueWriter, space []byte, start uint64) (*Auction, error) {
+	a := &Auction{
+		Space: string(space),
+		Start: start,
+		End:   start + g.AuctionWindow,
+	}
+	// [settlePrefix] + [delimiter] + [timestamp] + [delimiter] + [space]
+	if err := db.Put(PrefixSettleKey(a.End, space), nil); err != nil {
+		return nil, err
+	}
+	return a, PutAuction(db, a)
+}
+
+func GetBid(db database.KeyValueReader, space []byte, bidder common.Address) (*SpaceBid, bool, error) {
+	// [bidPrefix] + [delimiter] + [space] + [delimiter] + [bidder]
+	v, err := db.Get(PrefixBidKey(space, bidder[:]))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	b := new(SpaceBid)
+	if _, err := Unmarshal(v, b); err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+func PutBid(db database.KeyValueWriter, space []byte, b *SpaceBid) error {
+	v, err := Marshal(b)
+	if err != nil {
+		return err
+	}
+	return db.Put(PrefixBidKey(space, b.Bidder[:]), v)
+}
+
+func GetAllBids(db database.Database, space []byte) (bids []*SpaceBid, err error) {
+	baseKey := PrefixBidKey(space, nil)
+	cursor := db.NewIteratorWithStart(baseKey)
+	defer cursor.Release()
+	bids = []*SpaceBid{}
+	for cursor.Next() {
+		curKey := cursor.Key()
+		if bytes.Compare(baseKey, curKey) < -1 { // startKey < curKey; continue search
+			continue
+		}
+		if !bytes.HasPrefix(curKey, baseKey) { // curKey does not contain base key; end search
+			break
+		}
+
+		b := new(SpaceBid)
+		if _, err := Unmarshal(cursor.Value(), b); err != nil {
+			return nil, err
+		}
+		bids = append(bids, b)
+	}
+	return bids, cursor.Error()
+}
+
+// GetOpenAuctions returns all auctions that have not been settled, ordered by
+// their end time.
+func GetOpenAuctions(db database.Database) (auctions []*Auction, err error) {
+	baseKey := []byte{settlePrefix, parser.ByteDelimiter}
+	cursor := db.NewIteratorWithStart(baseKey)
+	defer cursor.Release()
+	auctions = []*Auction{}
+	for cursor.Next() {
+		curKey := cursor.Key()
+		if !bytes.HasPrefix(curKey, baseKey) {
+			break
+		}
+		// [settlePrefix] + [delimiter] + [timestamp] + [delimiter] + [space]
+		if len(curKey) <= 2+8+1 {
+			return nil, ErrInvalidKeyFormat
+		}
+		a, exists, err := GetAuction(db, curKey[2+8+1:])
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		auctions = append(auctions, a)
+	}
+	return auctions, cursor.Error()
+}
+
+// SettleAuctions gives each space whose auction ended in [parent, current) to
+// its highest bidder and refunds all other bidders. The winning bid is
+// burned.
+func SettleAuctions(g *Genesis, db database.Database, rparent int64, rcurrent int64) error {
+	parent, current := uint64(rparent), uint64(rcurrent)
+	startKey := RangeTimeKey(settlePrefix, parent)
+	endKey := RangeTimeKey(settlePrefix, current)
+	cursor := db.NewIteratorWithStart(startKey)
+	defer cursor.Release()
+	for cursor.Next() {
+		// [settlePrefix] + [delimiter] + [timestamp] + [delimiter] + [space]
+		curKey := cursor.Key()
+		if bytes.Compare(startKey, curKey) < -1 { // startKey < curKey; continue search
+			continue
+		}
+		if bytes.Compare(curKey, endKey) > 0 { // curKey > endKey; end search
+			break
+		}
+		if err := db.Delete(curKey); err != nil {
+			return err
+		}
+		if len(curKey) <= 2+8+1 {
+			return ErrInvalidKeyFormat
+		}
+		space := curKey[2+8+1:]
+		a, exists, err := GetAuction(db, space)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		// This should never happen as spaces cannot be claimed while auctions
+		// are enabled.
+		claimed, err := HasSpace(db, space)
+		if err != nil {
+			return err
+		}
+		bids, err := GetAllBids(db, space)
+		if err != nil {
+			return err
+		}
+		for _, b := range bids {
+			if err := db.Delete(PrefixBidKey(space, b.Bidder[:])); err != nil {
+				return err
+			}
+			if !claimed && b.Bidder == a.HighBidder {
+				continue
+			}
+			if _, err := ModifyBalance(db, b.Bidder, true, b.Units); err != nil {
+				return err
+			}
+		}
+		if err := db.Delete(PrefixAuctionKey(space)); err != nil {
+			return err
+		}
+		if claimed {
+			continue
+		}
+		if len(bids) == 0 {
+			// The next [BidTx] opens a new auction
+			log.Debug("auction ended without bids", "space", a.Space)
+			continue
+		}
+		if err := claimSpace(g, db, a.Space, a.HighBidder, current); err != nil {
+			return err
+		}
+		log.Debug("auction settled",
+			"space", a.Space,
+			"winner", a.HighBidder,
+			"bid", a.HighBid,
+			"bids", len(bids),
+		)
+	}
+	return cursor.Error()
+}
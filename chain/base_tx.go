@@ -4,7 +4,11 @@
 package chain
 
 import (
+	"strconv"
+
 	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/tdata"
 )
 
 type BaseTx struct {
@@ -17,6 +21,16 @@ type BaseTx struct {
 
 	// Price is the value per unit to spend on this transaction.
 	Price uint64 `serialize:"true" json:"price"`
+
+	// MaxPrice is the most this transaction is willing to pay per unit and
+	// PriorityTip is the value per unit offered on top of the block price. They
+	// are only set if [Genesis.FeeMarketEnabled] (in which case [Price] must
+	// be 0). See [EffectivePrice].
+	//
+	// Both fields are always serialized, which changed the encoding of every
+	// transaction (a breaking network upgrade, see the README).
+	MaxPrice    uint64 `serialize:"true" json:"maxPrice,omitempty"`
+	PriorityTip uint64 `serialize:"true" json:"priorityTip,omitempty"`
 }
 
 func (b *BaseTx) GetBlockID() ids.ID {
@@ -43,6 +57,22 @@ func (b *BaseTx) SetPrice(price uint64) {
 	b.Price = price
 }
 
+func (b *BaseTx) GetMaxPrice() uint64 {
+	return b.MaxPrice
+}
+
+func (b *BaseTx) SetMaxPrice(maxPrice uint64) {
+	b.MaxPrice = maxPrice
+}
+
+func (b *BaseTx) GetPriorityTip() uint64 {
+	return b.PriorityTip
+}
+
+func (b *BaseTx) SetPriorityTip(tip uint64) {
+	b.PriorityTip = tip
+}
+
 func (b *BaseTx) ExecuteBase(g *Genesis) error {
 	if b.BlockID == ids.Empty {
 		return ErrInvalidBlockID
@@ -50,6 +80,15 @@ func (b *BaseTx) ExecuteBase(g *Genesis) error {
 	if b.Magic != g.Magic {
 		return ErrInvalidMagic
 	}
+	if g.FeeMarketEnabled {
+		if b.Price != 0 || b.MaxPrice < g.MinPrice || b.PriorityTip > b.MaxPrice {
+			return ErrInvalidPrice
+		}
+		return nil
+	}
+	if b.MaxPrice != 0 || b.PriorityTip != 0 {
+		return ErrInvalidPrice
+	}
 	if b.Price < g.MinPrice {
 		return ErrInvalidPrice
 	}
@@ -71,5 +110,34 @@ func (b *BaseTx) Copy() *BaseTx {
 		BlockID: blockID,
 		Magic:   b.Magic,
 		Price:   b.Price,
+
+		MaxPrice:    b.MaxPrice,
+		PriorityTip: b.PriorityTip,
+	}
+}
+
+// typedData creates the typed data of a transaction with the fields in
+// [types] and [message], followed by the fee fields and [BlockID].
+//
+// [MaxPrice] and [PriorityTip] are only included if set so that the typed
+// data of transactions using [Price] is unchanged.
+func (b *BaseTx) typedData(
+	primaryType string,
+	types []tdata.Type,
+	message tdata.TypedDataMessage,
+) *tdata.TypedData {
+	if b.MaxPrice > 0 {
+		types = append(types,
+			tdata.Type{Name: tdMaxPrice, Type: tdUint64},
+			tdata.Type{Name: tdPriorityTip, Type: tdUint64},
+		)
+		message[tdMaxPrice] = strconv.FormatUint(b.MaxPrice, 10)
+		message[tdPriorityTip] = strconv.FormatUint(b.PriorityTip, 10)
+	} else {
+		types = append(types, tdata.Type{Name: tdPrice, Type: tdUint64})
+		message[tdPrice] = strconv.FormatUint(b.Price, 10)
 	}
+	types = append(types, tdata.Type{Name: tdBlockID, Type: tdString})
+	message[tdBlockID] = b.BlockID.String()
+	return tdata.CreateTypedData(b.Magic, primaryType, types, message)
 }
@@ -0,0 +1,202 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &BatchTx{}
+
+// BatchOp is a single operation in a [BatchTx]. [Typ] must be one of [Set],
+// [Delete], or [Lifeline] and only the fields used by that operation should be
+// populated.
+type BatchOp struct {
+	Typ   string `serialize:"true" json:"type"`
+	Space string `serialize:"true" json:"space"`
+	Key   string `serialize:"true" json:"key"`
+	Value []byte `serialize:"true" json:"value"`
+	Units uint64 `serialize:"true" json:"units"`
+}
+
+func (o *BatchOp) unsignedTx(b *BaseTx) (UnsignedTransaction, error) {
+	switch o.Typ {
+	case Set:
+		return &SetTx{BaseTx: b, Space: o.Space, Key: o.Key, Value: o.Value}, nil
+	case Delete:
+		return &DeleteTx{BaseTx: b, Space: o.Space, Key: o.Key}, nil
+	case Lifeline:
+		return &LifelineTx{BaseTx: b, Space: o.Space, Units: o.Units}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBatchOp, o.Typ)
+	}
+}
+
+func (o *BatchOp) Copy() *BatchOp {
+	value := make([]byte, len(o.Value))
+	copy(value, o.Value)
+	return &BatchOp{
+		Typ:   o.Typ,
+		Space: o.Space,
+		Key:   o.Key,
+		Value: value,
+		Units: o.Units,
+	}
+}
+
+type BatchTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Ops are executed in order and either all succeed or none are applied.
+	Ops []*BatchOp `serialize:"true" json:"ops"`
+}
+
+func (b *BatchTx) ExecuteBase(g *Genesis) error {
+	if err := b.BaseTx.ExecuteBase(g); err != nil {
+		return err
+	}
+	if g.MaxBatchOps > 0 && uint64(len(b.Ops)) > g.MaxBatchOps {
+		return ErrTooManyBatchOps
+	}
+	return nil
+}
+
+func (b *BatchTx) Execute(t *TransactionContext) error {
+	if len(b.Ops) == 0 {
+		return ErrNonActionable
+	}
+
+	// Apply all operations to an isolated database so that a failure in any
+	// operation leaves [t.Database] untouched.
+	vdb := versiondb.New(t.Database)
+	defer vdb.Abort()
+	bt := &TransactionContext{
+		Genesis:   t.Genesis,
+		Database:  vdb,
+		BlockTime: t.BlockTime,
+		TxID:      t.TxID,
+		Sender:    t.Sender,
+	}
+	for i, op := range b.Ops {
+		utx, err := op.unsignedTx(b.BaseTx)
+		if err != nil {
+			return err
+		}
+		if s, ok := utx.(*SetTx); ok {
+			err = s.execute(bt, BatchValueID(t.TxID, i))
+		} else {
+			err = utx.Execute(bt)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: op=%d", err, i)
+		}
+	}
+	return vdb.Commit()
+}
+
+// BatchValueID is the ID the value of the [i]th [BatchOp] in [txID] is
+// linked under.
+func BatchValueID(txID ids.ID, i int) ids.ID {
+	b := make([]byte, len(txID)+8)
+	copy(b, txID[:])
+	binary.BigEndian.PutUint64(b[len(txID):], uint64(i))
+	return ids.ID(crypto.Keccak256Hash(b))
+}
+
+func (b *BatchTx) FeeUnits(g *Genesis) uint64 {
+	units := uint64(0)
+	for _, op := range b.Ops {
+		utx, err := op.unsignedTx(b.BaseTx)
+		if err != nil {
+			// Invalid ops are rejected during execution
+			continue
+		}
+		units += utx.FeeUnits(g)
+	}
+	if units == 0 {
+		return b.BaseTx.FeeUnits(g)
+	}
+	return units
+}
+
+func (b *BatchTx) LoadUnits(g *Genesis) uint64 {
+	units := uint64(0)
+	for _, op := range b.Ops {
+		utx, err := op.unsignedTx(b.BaseTx)
+		if err != nil {
+			continue
+		}
+		units += utx.LoadUnits(g)
+	}
+	if units == 0 {
+		return b.BaseTx.LoadUnits(g)
+	}
+	return units
+}
+
+func (b *BatchTx) Copy() UnsignedTransaction {
+	ops := make([]*BatchOp, len(b.Ops))
+	for i, op := range b.Ops {
+		ops[i] = op.Copy()
+	}
+	return &BatchTx{
+		BaseTx: b.BaseTx.Copy(),
+		Ops:    ops,
+	}
+}
+
+func (b *BatchTx) TypedData() *tdata.TypedData {
+	ops := make([]interface{}, len(b.Ops))
+	for i, op := range b.Ops {
+		ops[i] = tdata.TypedDataMessage{
+			tdType:  op.Typ,
+			tdSpace: op.Space,
+			tdKey:   op.Key,
+			tdValue: hexutil.Encode(op.Value),
+			tdUnits: strconv.FormatUint(op.Units, 10),
+		}
+	}
+	td := b.typedData(
+		Batch,
+		[]tdata.Type{
+			{Name: tdOps, Type: tdBatchOp + "[]"},
+		},
+		tdata.TypedDataMessage{
+			tdOps: ops,
+		},
+	)
+	td.Types[tdBatchOp] = []tdata.Type{
+		{Name: tdType, Type: tdString},
+		{Name: tdSpace, Type: tdString},
+		{Name: tdKey, Type: tdString},
+		{Name: tdValue, Type: tdBytes},
+		{Name: tdUnits, Type: tdUint64},
+	}
+	return td
+}
+
+func (b *BatchTx) Activity() *Activity {
+	activity := &Activity{
+		Typ:   Batch,
+		Units: uint64(len(b.Ops)),
+	}
+	// Only attribute the batch to a space if all ops target the same one
+	for i, op := range b.Ops {
+		if i > 0 && op.Space != activity.Space {
+			activity.Space = ""
+			break
+		}
+		activity.Space = op.Space
+	}
+	return activity
+}
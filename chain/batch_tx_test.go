@@ -0,0 +1,216 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBatchTx(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+
+	priv2, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender2 := crypto.PubkeyToAddress(priv2.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{ // successful claim
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    sender,
+		},
+		{ // empty batch
+			utx:       &BatchTx{BaseTx: &BaseTx{}},
+			blockTime: 1,
+			sender:    sender,
+			err:       ErrNonActionable,
+		},
+		{ // unsupported op
+			utx: &BatchTx{BaseTx: &BaseTx{}, Ops: []*BatchOp{
+				{Typ: Claim, Space: "bar"},
+			}},
+			blockTime: 1,
+			sender:    sender,
+			err:       ErrInvalidBatchOp,
+		},
+		{ // write multiple keys
+			utx: &BatchTx{BaseTx: &BaseTx{}, Ops: []*BatchOp{
+				{Typ: Set, Space: "foo", Key: "a", Value: []byte("1")},
+				{Typ: Set, Space: "foo", Key: "b", Value: []byte("2")},
+				{Typ: Lifeline, Space: "foo", Units: 1},
+			}},
+			blockTime: 1,
+			sender:    sender,
+		},
+		{ // write by non-owner
+			utx: &BatchTx{BaseTx: &BaseTx{}, Ops: []*BatchOp{
+				{Typ: Set, Space: "foo", Key: "c", Value: []byte("3")},
+			}},
+			blockTime: 1,
+			sender:    sender2,
+			err:       ErrUnauthorized,
+		},
+		{ // failing op reverts earlier ops
+			utx: &BatchTx{BaseTx: &BaseTx{}, Ops: []*BatchOp{
+				{Typ: Set, Space: "foo", Key: "c", Value: []byte("3")},
+				{Typ: Delete, Space: "foo", Key: "a"},
+				{Typ: Delete, Space: "foo", Key: "missing"},
+			}},
+			blockTime: 1,
+			sender:    sender,
+			err:       ErrKeyMissing,
+		},
+		{ // delete and overwrite
+			utx: &BatchTx{BaseTx: &BaseTx{}, Ops: []*BatchOp{
+				{Typ: Delete, Space: "foo", Key: "a"},
+				{Typ: Set, Space: "foo", Key: "b", Value: []byte("22")},
+			}},
+			blockTime: 1,
+			sender:    sender,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: tv.blockTime,
+			TxID:      ids.GenerateTestID(),
+			Sender:    tv.sender,
+		}
+		err := tv.utx.Execute(tc)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+		if i == 3 {
+			vmeta, exists, err := GetValueMeta(db, []byte("foo"), []byte("b"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !exists {
+				t.Fatal("key b should exist")
+			}
+			if vmeta.TxID != tc.TxID {
+				t.Fatalf("#%d: unexpected txID %s, expected %s", i, vmeta.TxID, tc.TxID)
+			}
+			if expected := BatchValueID(tc.TxID, 1); vmeta.ValueID != expected {
+				t.Fatalf("#%d: unexpected valueID %s, expected %s", i, vmeta.ValueID, expected)
+			}
+		}
+	}
+
+	// Reverted batch must not leave any writes behind
+	if has, err := HasSpaceKey(db, []byte("foo"), []byte("c")); has || err != nil {
+		t.Fatalf("unexpected has %v, err %v", has, err)
+	}
+	if has, err := HasSpaceKey(db, []byte("foo"), []byte("a")); has || err != nil {
+		t.Fatalf("unexpected has %v, err %v", has, err)
+	}
+	vmeta, exists, err := GetValueMeta(db, []byte("foo"), []byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || vmeta.Size != 2 {
+		t.Fatalf("unexpected value meta %+v", vmeta)
+	}
+}
+
+func TestBatchTxFeeUnits(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	base := &BaseTx{}
+	set := &SetTx{BaseTx: base, Space: "foo", Key: "a", Value: bytes.Repeat([]byte{1}, 2048)}
+	del := &DeleteTx{BaseTx: base, Space: "foo", Key: "b"}
+	life := &LifelineTx{BaseTx: base, Space: "foo", Units: 3}
+	b := &BatchTx{BaseTx: base, Ops: []*BatchOp{
+		{Typ: Set, Space: "foo", Key: "a", Value: set.Value},
+		{Typ: Delete, Space: "foo", Key: "b"},
+		{Typ: Lifeline, Space: "foo", Units: 3},
+	}}
+	expected := set.FeeUnits(g) + del.FeeUnits(g) + life.FeeUnits(g)
+	if fu := b.FeeUnits(g); fu != expected {
+		t.Fatalf("fee units expected %d, got %d", expected, fu)
+	}
+	expected = set.LoadUnits(g) + del.LoadUnits(g) + life.LoadUnits(g)
+	if lu := b.LoadUnits(g); lu != expected {
+		t.Fatalf("load units expected %d, got %d", expected, lu)
+	}
+}
+
+func TestBatchTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	b := &BatchTx{
+		BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Magic: 5, Price: 10},
+		Ops: []*BatchOp{
+			{Typ: Set, Space: "foo", Key: "a", Value: []byte("1")},
+			{Typ: Delete, Space: "foo", Key: "b", Value: []byte{}},
+			{Typ: Lifeline, Space: "foo", Units: 3, Value: []byte{}},
+		},
+	}
+	td := b.TypedData()
+	if _, err := DigestHash(b); err != nil {
+		t.Fatal(err)
+	}
+	utx, err := ParseTypedData(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b, utx) {
+		t.Fatalf("parsed tx expected %+v, got %+v", b, utx)
+	}
+}
+
+func TestBatchTxMaxOps(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	g.MaxBatchOps = 2
+	b := &BatchTx{
+		BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Price: g.MinPrice},
+		Ops: []*BatchOp{
+			{Typ: Delete, Space: "foo", Key: "a"},
+			{Typ: Delete, Space: "foo", Key: "b"},
+		},
+	}
+	if err := b.ExecuteBase(g); err != nil {
+		t.Fatal(err)
+	}
+	b.Ops = append(b.Ops, &BatchOp{Typ: Delete, Space: "foo", Key: "c"})
+	if err := b.ExecuteBase(g); !errors.Is(err, ErrTooManyBatchOps) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// The base tx is still checked
+	b.Ops = b.Ops[:1]
+	b.Price = 0
+	if err := b.ExecuteBase(g); !errors.Is(err, ErrInvalidPrice) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &BidTx{}
+
+// BidTx escrows [Units] of the sender's balance as a bid for an unclaimed
+// space. The auction of a space opens when it expires; if it has no open
+// auction (because it was never claimed or nobody bid on it in time), the bid
+// opens one. Auctions last [Genesis.AuctionWindow] seconds. Bidding again replaces the previous bid of the sender (only the
+// difference is escrowed).
+type BidTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// Units is the total bid of the sender. It must be at least
+	// [Genesis.MinBid] and greater than the current highest bid.
+	Units uint64 `serialize:"true" json:"units"`
+}
+
+func (b *BidTx) Execute(t *TransactionContext) error {
+	g := t.Genesis
+	if g.AuctionWindow == 0 {
+		return ErrAuctionDisabled
+	}
+	if err := parser.CheckContents(b.Space); err != nil {
+		return err
+	}
+
+	// Address spaces can only be claimed by their address
+	if len(b.Space) == hexAddressLen {
+		return ErrAddressMismatch
+	}
+
+	// Space keys only exist if they are still valid
+	exists, err := HasSpace(t.Database, []byte(b.Space))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrSpaceNotExpired
+	}
+
+	a, exists, err := GetAuction(t.Database, []byte(b.Space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		a, err = openAuction(g, t.Database, []byte(b.Space), t.BlockTime)
+		if err != nil {
+			return err
+		}
+	}
+	if t.BlockTime >= a.End {
+		return ErrAuctionClosed
+	}
+	if b.Units < g.MinBid || b.Units <= a.HighBid {
+		return fmt.Errorf("%w: bid=%d min=%d high=%d", ErrBidTooLow, b.Units, g.MinBid, a.HighBid)
+	}
+
+	// Escrow the bid (less any previous bid)
+	escrow := b.Units
+	prev, exists, err := GetBid(t.Database, []byte(b.Space), t.Sender)
+	if err != nil {
+		return err
+	}
+	if exists {
+		escrow -= prev.Units
+	}
+	if _, err := ModifyBalance(t.Database, t.Sender, false, escrow); err != nil {
+		return err
+	}
+	if err := PutBid(t.Database, []byte(b.Space), &SpaceBid{
+		Bidder:  t.Sender,
+		Units:   b.Units,
+		Updated: t.BlockTime,
+	}); err != nil {
+		return err
+	}
+	a.HighBidder = t.Sender
+	a.HighBid = b.Units
+	return PutAuction(t.Database, a)
+}
+
+func (b *BidTx) FeeUnits(g *Genesis) uint64 {
+	// The bid itself is the price signal for the space
+	return b.LoadUnits(g)
+}
+
+func (b *BidTx) LoadUnits(g *Genesis) uint64 {
+	return b.BaseTx.LoadUnits(g) * g.ClaimLoadMultiplier
+}
+
+func (b *BidTx) Copy() UnsignedTransaction {
+	return &BidTx{
+		BaseTx: b.BaseTx.Copy(),
+		Space:  b.Space,
+		Units:  b.Units,
+	}
+}
+
+func (b *BidTx) TypedData() *tdata.TypedData {
+	return b.typedData(
+		Bid,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdUnits, Type: tdUint64},
+		},
+		tdata.TypedDataMessage{
+			tdSpace: b.Space,
+			tdUnits: strconv.FormatUint(b.Units, 10),
+		},
+	)
+}
+
+func (b *BidTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Bid,
+		Space: b.Space,
+		Units: b.Units,
+	}
+}
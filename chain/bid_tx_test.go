@@ -0,0 +1,223 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBidTx(t *testing.T) {
+	t.Parallel()
+
+	addrs := make([]common.Address, 3)
+	for i := range addrs {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[i] = crypto.PubkeyToAddress(priv.PublicKey)
+	}
+	alice, bob, carol := addrs[0], addrs[1], addrs[2]
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.AuctionWindow = 10
+	g.MinBid = 100
+	g.CustomAllocation = []*CustomAllocation{
+		{Address: alice, Balance: 1000},
+		{Address: bob, Balance: 1000},
+		{Address: carol, Balance: 50},
+	}
+	if err := g.Load(db, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{ // claims are disabled
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    alice,
+			err:       ErrAuctionRequired,
+		},
+		{ // address spaces can still be claimed
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "0x" + common.Bytes2Hex(alice[:])},
+			blockTime: 1,
+			sender:    alice,
+		},
+		{ // bid below minimum
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 99},
+			blockTime: 1,
+			sender:    alice,
+			err:       ErrBidTooLow,
+		},
+		{ // opening bid
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 100},
+			blockTime: 1,
+			sender:    alice,
+		},
+		{ // bid not above highest
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 100},
+			blockTime: 2,
+			sender:    bob,
+			err:       ErrBidTooLow,
+		},
+		{ // bid without balance
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 200},
+			blockTime: 2,
+			sender:    carol,
+			err:       ErrInvalidBalance,
+		},
+		{ // outbid
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 300},
+			blockTime: 2,
+			sender:    bob,
+		},
+		{ // raise previous bid
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 400},
+			blockTime: 3,
+			sender:    alice,
+		},
+		{ // bid after window
+			utx:       &BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 900},
+			blockTime: 11,
+			sender:    bob,
+			err:       ErrAuctionClosed,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: tv.blockTime,
+			TxID:      ids.GenerateTestID(),
+			Sender:    tv.sender,
+		}
+		err := tv.utx.Execute(tc)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+
+	for _, tv := range []struct {
+		addr    common.Address
+		balance uint64
+	}{{alice, 600}, {bob, 700}, {carol, 50}} {
+		b, err := GetBalance(db, tv.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b != tv.balance {
+			t.Fatalf("%s: expected balance %d, got %d", tv.addr, tv.balance, b)
+		}
+	}
+	auctions, err := GetOpenAuctions(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(auctions) != 1 || auctions[0].HighBidder != alice || auctions[0].HighBid != 400 {
+		t.Fatalf("unexpected auctions %+v", auctions)
+	}
+
+	// Nothing is settled before the window ends
+	if err := SettleAuctions(g, db, 3, 11); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := HasSpace(db, []byte("foo")); has || err != nil {
+		t.Fatalf("unexpected has %v, err %v", has, err)
+	}
+
+	if err := SettleAuctions(g, db, 11, 12); err != nil {
+		t.Fatal(err)
+	}
+	info, exists, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	if info.Owner != alice || info.Created != 12 {
+		t.Fatalf("unexpected space info %+v", info)
+	}
+	for _, tv := range []struct {
+		addr    common.Address
+		balance uint64
+	}{{alice, 600}, {bob, 1000}} {
+		b, err := GetBalance(db, tv.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b != tv.balance {
+			t.Fatalf("%s: expected balance %d, got %d", tv.addr, tv.balance, b)
+		}
+	}
+	if _, exists, err := GetAuction(db, []byte("foo")); exists || err != nil {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	if bids, err := GetAllBids(db, []byte("foo")); len(bids) != 0 || err != nil {
+		t.Fatalf("unexpected bids %v, err %v", bids, err)
+	}
+
+	// Claimed spaces cannot be bid on
+	err = (&BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 500}).Execute(&TransactionContext{
+		Genesis:   g,
+		Database:  db,
+		BlockTime: 13,
+		TxID:      ids.GenerateTestID(),
+		Sender:    bob,
+	})
+	if !errors.Is(err, ErrSpaceNotExpired) {
+		t.Fatalf("expected %v, got %v", ErrSpaceNotExpired, err)
+	}
+
+	// The auction opens once the space expires
+	if err := ExpireNext(g, db, 13, int64(info.Expiry+1), true); err != nil {
+		t.Fatal(err)
+	}
+	a, exists, err := GetAuction(db, []byte("foo"))
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	if a.Start != info.Expiry || a.End != info.Expiry+g.AuctionWindow {
+		t.Fatalf("unexpected auction %+v", a)
+	}
+
+	// Auctions without bids don't give the space away
+	if err := SettleAuctions(g, db, int64(info.Expiry), int64(a.End+1)); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := HasSpace(db, []byte("foo")); has || err != nil {
+		t.Fatalf("unexpected has %v, err %v", has, err)
+	}
+	if _, exists, err := GetAuction(db, []byte("foo")); exists || err != nil {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+}
+
+func TestBidTxDisabled(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+
+	err := (&BidTx{BaseTx: &BaseTx{}, Space: "foo", Units: 500}).Execute(&TransactionContext{
+		Genesis:   DefaultGenesis(),
+		Database:  db,
+		BlockTime: 1,
+		TxID:      ids.GenerateTestID(),
+	})
+	if !errors.Is(err, ErrAuctionDisabled) {
+		t.Fatalf("expected %v, got %v", ErrAuctionDisabled, err)
+	}
+}
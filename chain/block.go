@@ -27,6 +27,10 @@ type StatefulBlock struct {
 	Price  uint64         `serialize:"true" json:"price"`
 	Cost   uint64         `serialize:"true" json:"cost"`
 	Txs    []*Transaction `serialize:"true" json:"txs"`
+
+	// StateRoot is the root of the state trie after the block is applied
+	// (see [CommitState]).
+	StateRoot ids.ID `serialize:"true" json:"stateRoot"`
 }
 
 // Stateless is defined separately from "Block"
@@ -41,6 +45,8 @@ type StatelessBlock struct {
 	bytes []byte
 
 	Winners map[ids.ID]*Activity
+	// Expired are the spaces removed when the block was verified
+	Expired []*ExpiredSpace
 
 	vm         VM
 	children   []*StatelessBlock
@@ -108,6 +114,49 @@ func ParseStatefulBlock(
 	return b, nil
 }
 
+// TxRoot commits to the IDs of all transactions in [b].
+func (b *StatefulBlock) TxRoot() ids.ID {
+	txIDs := make([][]byte, len(b.Txs))
+	for i, tx := range b.Txs {
+		txID := tx.ID()
+		txIDs[i] = txID[:]
+	}
+	return ids.ID(crypto.Keccak256Hash(txIDs...))
+}
+
+// applyRewards distributes the lottery reward of each transaction in [b] once
+// all of them have been executed.
+//
+// The reward recipients are selected with the [TxRoot] of [b] (instead of its
+// ID, which commits to the state produced by the rewards) so that senders
+// can't grind their tx ID to choose the recipient. If there is no space after
+// the selected iterator, no reward will be distributed.
+func (b *StatelessBlock) applyRewards(g *Genesis, db database.Database) error {
+	txRoot := b.TxRoot()
+	for _, tx := range b.Txs {
+		rewardAmount := tx.rewardAmount(g, b.Price)
+		if rewardAmount == 0 {
+			// For transactions (like transfers) where the [FeeUnits] are equal to the [BaseTxFee], it
+			// is possible that the reward could be 0.
+			continue
+		}
+		recipient, distributed, err := ApplyReward(db, txRoot, tx.ID(), tx.Sender(), rewardAmount)
+		if err != nil {
+			return err
+		}
+		if distributed {
+			b.Winners[tx.ID()] = &Activity{
+				Tmstmp: b.Tmstmp,
+				Typ:    Reward,
+				TxID:   tx.ID(),
+				To:     recipient.Hex(),
+				Units:  rewardAmount,
+			}
+		}
+	}
+	return nil
+}
+
 func (b *StatelessBlock) init() error {
 	b.Winners = map[ids.ID]*Activity{}
 	bytes, err := Marshal(b.StatefulBlock)
@@ -180,9 +229,17 @@ func (b *StatelessBlock) verify() (*StatelessBlock, *versiondb.Database, error)
 		return nil, nil, err
 	}
 	onAcceptDB := versiondb.New(parentState)
+	sdb := NewStateDB(onAcceptDB)
 
 	// Remove all expired spaces
-	if err := ExpireNext(onAcceptDB, parent.Tmstmp, b.Tmstmp, b.vm.IsBootstrapped()); err != nil {
+	expired, err := expireNext(g, sdb, parent.Tmstmp, b.Tmstmp, b.vm.IsBootstrapped())
+	if err != nil {
+		return nil, nil, err
+	}
+	b.Expired = expired
+
+	// Give spaces to the winners of auctions that have ended
+	if err := SettleAuctions(g, sdb, parent.Tmstmp, b.Tmstmp); err != nil {
 		return nil, nil, err
 	}
 
@@ -190,21 +247,38 @@ func (b *StatelessBlock) verify() (*StatelessBlock, *versiondb.Database, error)
 	log.Debug("build context", "height", b.Hght, "price", b.Price, "cost", b.Cost)
 	surplusFee := uint64(0)
 	for _, tx := range b.Txs {
-		if err := tx.Execute(g, onAcceptDB, b, context); err != nil {
+		if err := tx.Execute(g, sdb, b, context); err != nil {
 			return nil, nil, err
 		}
-		surplusFee += (tx.GetPrice() - b.Price) * tx.FeeUnits(g)
+		// The same action can't be included twice in a block either
+		context.RecentReplayIDs.Add(tx.ReplayID())
+		surplusFee += EffectiveTip(tx.UnsignedTransaction, b.Price) * tx.FeeUnits(g)
+	}
+	if err := b.applyRewards(g, sdb); err != nil {
+		return nil, nil, err
 	}
 	// Ensure enough fee is paid to compensate for block production speed
 	requiredSurplus := b.Price * b.Cost
 	if surplusFee < requiredSurplus {
 		return nil, nil, fmt.Errorf("%w: required=%d found=%d", ErrInsufficientSurplus, requiredSurplus, surplusFee)
 	}
+
+	// Ensure the block commits to the resulting state
+	root, err := CommitState(sdb)
+	if err != nil {
+		return nil, nil, err
+	}
+	if root != b.StateRoot {
+		return nil, nil, fmt.Errorf("%w: expected=%s found=%s", ErrInvalidStateRoot, root, b.StateRoot)
+	}
 	return parent, onAcceptDB, nil
 }
 
 // implements "snowman.Block"
 func (b *StatelessBlock) Verify() error {
+	start := time.Now()
+	defer func() { b.vm.Metrics().verifyLatency.Observe(time.Since(start).Seconds()) }()
+
 	parent, onAcceptDB, err := b.verify()
 	if err != nil {
 		log.Debug("block verification failed", "blkID", b.ID(), "error", err)
@@ -224,6 +298,9 @@ func (b *StatelessBlock) Verify() error {
 
 // implements "snowman.Block.choices.Decidable"
 func (b *StatelessBlock) Accept() error {
+	start := time.Now()
+	defer func() { b.vm.Metrics().acceptLatency.Observe(time.Since(start).Seconds()) }()
+
 	if err := b.onAcceptDB.Commit(); err != nil {
 		return err
 	}
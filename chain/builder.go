@@ -14,6 +14,8 @@ import (
 
 func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 	g := vm.Genesis()
+	metrics := vm.Metrics()
+	metrics.buildAttempts.Inc()
 
 	log.Debug("attempting block building")
 	nextTime := time.Now().Unix()
@@ -32,6 +34,7 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 	// Clean out invalid txs
 	mempool := vm.Mempool()
 	mempool.Prune(context.RecentBlockIDs)
+	mempool.SetBasePrice(b.Price)
 
 	parentDB, err := parent.onAccept()
 	if err != nil {
@@ -39,9 +42,15 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 		return nil, err
 	}
 	vdb := versiondb.New(parentDB)
+	sdb := NewStateDB(vdb)
 
 	// Remove all expired spaces
-	if err := ExpireNext(vdb, parent.Tmstmp, b.Tmstmp, true); err != nil {
+	if err := ExpireNext(g, sdb, parent.Tmstmp, b.Tmstmp, true); err != nil {
+		return nil, err
+	}
+
+	// Give spaces to the winners of auctions that have ended
+	if err := SettleAuctions(g, sdb, parent.Tmstmp, b.Tmstmp); err != nil {
 		return nil, err
 	}
 
@@ -58,31 +67,45 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 	}()
 
 	for mempool.Len() > 0 {
-		next, price := mempool.PopMax()
-		if price < b.Price {
+		// The mempool is ordered by [Priority], so no remaining tx can afford
+		// the block price if [next] can't
+		next, _ := mempool.PopMax()
+		if price := EffectivePrice(next.UnsignedTransaction, b.Price); price < b.Price {
 			mempool.Add(next)
+			metrics.skippedTxs.WithLabelValues(skipPrice).Inc()
 			log.Debug("skipping tx: too low price", "block price", b.Price, "tx price", price)
 			break
 		}
 		nextLoad := next.LoadUnits(g)
 		if units+nextLoad > g.MaxBlockSize {
 			unusableTxs = append(unusableTxs, next)
+			metrics.skippedTxs.WithLabelValues(skipSize).Inc()
 			log.Debug("skipping tx: too large", "block size", units, "tx load", nextLoad)
 			continue // could be txs that fit that are smaller
 		}
 		// Verify that changes pass
-		tvdb := versiondb.New(vdb)
+		tvdb := versiondb.New(sdb)
 		if err := next.Execute(g, tvdb, b, context); err != nil {
+			metrics.skippedTxs.WithLabelValues(skipInvalid).Inc()
 			log.Debug("skipping tx: failed verification", "err", err)
 			continue
 		}
 		if err := tvdb.Commit(); err != nil {
 			return nil, err
 		}
+		context.RecentReplayIDs.Add(next.ReplayID())
 		// Wait to add spaces until after verification
 		b.Txs = append(b.Txs, next)
 		units += nextLoad
 	}
+	if err := b.applyRewards(g, sdb); err != nil {
+		return nil, err
+	}
+	root, err := CommitState(sdb)
+	if err != nil {
+		return nil, err
+	}
+	b.StateRoot = root
 	vdb.Abort()
 
 	// Compute block hash and marshaled representation
@@ -96,5 +119,6 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 		log.Debug("block building failed: failed verification", "err", err)
 		return nil, err
 	}
+	metrics.blockFill.Observe(float64(units) / float64(g.MaxBlockSize))
 	return b, nil
 }
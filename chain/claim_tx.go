@@ -4,9 +4,9 @@
 package chain
 
 import (
-	"strconv"
 	"strings"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ava-labs/spacesvm/parser"
@@ -36,10 +36,16 @@ func (c *ClaimTx) Execute(t *TransactionContext) error {
 	}
 
 	// Restrict address space to be owned by address
-	if len(c.Space) == hexAddressLen && strings.ToLower(t.Sender.Hex()) != c.Space {
+	isAddress := len(c.Space) == hexAddressLen
+	if isAddress && strings.ToLower(t.Sender.Hex()) != c.Space {
 		return ErrAddressMismatch
 	}
 
+	// When auctions are enabled, all other spaces must be won with a [BidTx]
+	if t.Genesis.AuctionWindow > 0 && !isAddress {
+		return ErrAuctionRequired
+	}
+
 	// Space keys only exist if they are still valid
 	exists, err := HasSpace(t.Database, []byte(c.Space))
 	if err != nil {
@@ -50,17 +56,19 @@ func (c *ClaimTx) Execute(t *TransactionContext) error {
 	}
 
 	// Anything previously at the space was previously removed...
+	return claimSpace(t.Genesis, t.Database, c.Space, t.Sender, t.BlockTime)
+}
+
+// claimSpace gives [space] to [owner] with the lifetime of a new claim.
+func claimSpace(g *Genesis, db database.KeyValueWriterDeleter, space string, owner common.Address, blockTime uint64) error {
 	newInfo := &SpaceInfo{
-		Owner:   t.Sender,
-		Created: t.BlockTime,
-		Updated: t.BlockTime,
-		Expiry:  t.BlockTime + t.Genesis.ClaimReward/t.Genesis.ClaimExpiryUnits,
-		Units:   t.Genesis.ClaimExpiryUnits,
-	}
-	if err := PutSpaceInfo(t.Database, []byte(c.Space), newInfo, 0); err != nil {
-		return err
+		Owner:   owner,
+		Created: blockTime,
+		Updated: blockTime,
+		Expiry:  blockTime + g.ClaimReward/g.ClaimExpiryUnits,
+		Units:   g.ClaimExpiryUnits,
 	}
-	return nil
+	return PutSpaceInfo(db, []byte(space), newInfo, 0)
 }
 
 // [spaceNameUnits] requires the caller to pay more to get spaces of
@@ -94,17 +102,13 @@ func (c *ClaimTx) Copy() UnsignedTransaction {
 }
 
 func (c *ClaimTx) TypedData() *tdata.TypedData {
-	return tdata.CreateTypedData(
-		c.Magic, Claim,
+	return c.typedData(
+		Claim,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
-			{Name: tdPrice, Type: tdUint64},
-			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   c.Space,
-			tdPrice:   strconv.FormatUint(c.Price, 10),
-			tdBlockID: c.BlockID.String(),
+			tdSpace: c.Space,
 		},
 	)
 }
@@ -87,7 +87,7 @@ func TestClaimTx(t *testing.T) {
 	for i, tv := range tt {
 		if i > 0 {
 			// Expire old spaces between txs
-			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
+			if err := ExpireNext(DefaultGenesis(), db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
 				t.Fatalf("#%d: ExpireNext errored %v", i, err)
 			}
 		}
@@ -132,7 +132,7 @@ func TestClaimTx(t *testing.T) {
 	if len(sender2Spaces) != 1 {
 		t.Fatalf("sender2 owned spaces should = 1, found %d", len(sender2Spaces))
 	}
-	if err := ExpireNext(db, 0, ClaimReward*10, true); err != nil {
+	if err := ExpireNext(DefaultGenesis(), db, 0, ClaimReward*10, true); err != nil {
 		t.Fatal(err)
 	}
 	pruned, err := PruneNext(db, 100)
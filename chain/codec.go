@@ -13,6 +13,10 @@ import (
 const (
 	// codecVersion is the current default codec version
 	codecVersion = 0
+	// valueMetaVersion is the codec version [ValueMeta] is encoded with since
+	// [ValueMeta.ValueID], [ValueMeta.ValueHash], and [ValueMeta.Expiry] were
+	// added (see [UnmarshalValueMeta])
+	valueMetaVersion = 1
 
 	// maxSize is 4MB to support large values
 	maxSize = 4 * units.MiB
@@ -32,6 +36,12 @@ func init() {
 		c.RegisterType(&DeleteTx{}),
 		c.RegisterType(&TransferTx{}),
 		c.RegisterType(&MoveTx{}),
+		c.RegisterType(&BatchTx{}),
+		c.RegisterType(&ConditionalSetTx{}),
+		c.RegisterType(&GrantTx{}),
+		c.RegisterType(&RevokeTx{}),
+		c.RegisterType(&SetOwnersTx{}),
+		c.RegisterType(&BidTx{}),
 		c.RegisterType(&Transaction{}),
 		c.RegisterType(&StatefulBlock{}),
 		c.RegisterType(&SpaceInfo{}),
@@ -39,6 +49,7 @@ func init() {
 		c.RegisterType(&Airdrop{}),
 		c.RegisterType(&Genesis{}),
 		codecManager.RegisterCodec(codecVersion, c),
+		codecManager.RegisterCodec(valueMetaVersion, c),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -5,6 +5,8 @@ package chain
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -17,6 +19,34 @@ func (t *TransactionContext) authorized(owner common.Address) bool {
 	return bytes.Equal(owner[:], t.Sender[:])
 }
 
+// signed returns true if [addr] is the sender or one of the cosigners.
+func (t *TransactionContext) signed(addr common.Address) bool {
+	if t.authorized(addr) {
+		return true
+	}
+	for _, c := range t.Cosigners {
+		if bytes.Equal(addr[:], c[:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// controls returns true if the transaction is signed by the owner of [i] or,
+// if [i] has an owner set, by at least [i.Threshold] of its owners.
+func (t *TransactionContext) controls(i *SpaceInfo) bool {
+	if len(i.Owners) == 0 {
+		return t.authorized(i.Owner)
+	}
+	signed := uint64(0)
+	for _, owner := range i.Owners {
+		if t.signed(owner) {
+			signed++
+		}
+	}
+	return signed >= i.Threshold
+}
+
 func verifySpace(s string, t *TransactionContext) (*SpaceInfo, error) {
 	i, has, err := GetSpaceInfo(t.Database, []byte(s))
 	if err != nil {
@@ -27,7 +57,7 @@ func verifySpace(s string, t *TransactionContext) (*SpaceInfo, error) {
 		return nil, ErrSpaceMissing
 	}
 	// Space cannot be updated if not owned by modifier
-	if !t.authorized(i.Owner) {
+	if !t.controls(i) {
 		return nil, ErrUnauthorized
 	}
 	// Space cannot be updated if expired
@@ -40,6 +70,37 @@ func verifySpace(s string, t *TransactionContext) (*SpaceInfo, error) {
 	return i, nil
 }
 
+// verifySpaceAccess is like [verifySpace] but also allows senders that have
+// been granted [write] or [delete] rights to [key] by the space owner.
+func verifySpaceAccess(s string, key string, write bool, del bool, t *TransactionContext) (*SpaceInfo, error) {
+	i, err := verifySpace(s, t)
+	if !errors.Is(err, ErrUnauthorized) {
+		return i, err
+	}
+	i, _, err = GetSpaceInfo(t.Database, []byte(s))
+	if err != nil {
+		return nil, err
+	}
+	p, has, err := GetPermission(t.Database, i.RawSpace, t.Sender)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !has:
+		return nil, ErrUnauthorized
+	case write && !p.Write, del && !p.Delete:
+		return nil, ErrUnauthorized
+	case !strings.HasPrefix(key, p.Prefix):
+		return nil, fmt.Errorf("%w: key outside of prefix %s", ErrUnauthorized, p.Prefix)
+	case p.Expiry != 0 && p.Expiry < t.BlockTime:
+		return nil, fmt.Errorf("%w: permission expired", ErrUnauthorized)
+	}
+	if i.Expiry < t.BlockTime {
+		return nil, ErrSpaceExpired
+	}
+	return i, nil
+}
+
 func updateSpace(s string, t *TransactionContext, timeRemaining uint64, i *SpaceInfo) error {
 	newTimeRemaining := timeRemaining / i.Units
 	i.Updated = t.BlockTime
@@ -52,6 +113,16 @@ func valueUnits(g *Genesis, size uint64) uint64 {
 	return size/g.ValueUnitSize + 1
 }
 
+// ttlValueUnits discounts [valueUnits] proportionally for values that live
+// for less than [FullValueTTL].
+func ttlValueUnits(g *Genesis, size uint64, ttl uint64) uint64 {
+	units := valueUnits(g, size)
+	if ttl == 0 || ttl >= g.FullValueTTL {
+		return units
+	}
+	return units*ttl/g.FullValueTTL + 1
+}
+
 func valueHash(v []byte) string {
 	h := common.BytesToHash(crypto.Keccak256(v)).Hex()
 	return strings.ToLower(h)
@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &ConditionalSetTx{}
+
+// ConditionalSetTx is a [SetTx] that is only applied if the current value at
+// [Space]/[Key] matches the provided condition.
+type ConditionalSetTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// Key is parsed from the given input, with its space removed.
+	Key string `serialize:"true" json:"key"`
+
+	// Value is written as the key-value pair to the storage if the condition
+	// holds.
+	Value []byte `serialize:"true" json:"value"`
+
+	// IfMatch is the [ValueMeta.TxID] that must currently be stored at
+	// [Space]/[Key]. It must be empty if [IfAbsent] is set.
+	IfMatch ids.ID `serialize:"true" json:"ifMatch"`
+
+	// IfAbsent requires that there is currently no value stored at
+	// [Space]/[Key].
+	IfAbsent bool `serialize:"true" json:"ifAbsent"`
+}
+
+func (c *ConditionalSetTx) Execute(t *TransactionContext) error {
+	// Exactly one condition must be provided
+	if c.IfAbsent == (c.IfMatch != ids.Empty) {
+		return ErrInvalidCondition
+	}
+
+	if err := parser.CheckContents(c.Space); err != nil {
+		return err
+	}
+	if err := parser.CheckContents(c.Key); err != nil {
+		return err
+	}
+	v, exists, err := GetValueMeta(t.Database, []byte(c.Space), []byte(c.Key))
+	if err != nil {
+		return err
+	}
+	switch {
+	case c.IfAbsent && exists:
+		return fmt.Errorf("%w: key exists", ErrConditionFailed)
+	case !c.IfAbsent && !exists:
+		return fmt.Errorf("%w: key missing", ErrConditionFailed)
+	case !c.IfAbsent && v.TxID != c.IfMatch:
+		return fmt.Errorf("%w: expected %s got %s", ErrConditionFailed, c.IfMatch, v.TxID)
+	}
+	return c.setTx().Execute(t)
+}
+
+func (c *ConditionalSetTx) setTx() *SetTx {
+	return &SetTx{
+		BaseTx: c.BaseTx,
+		Space:  c.Space,
+		Key:    c.Key,
+		Value:  c.Value,
+	}
+}
+
+func (c *ConditionalSetTx) FeeUnits(g *Genesis) uint64 {
+	return c.setTx().FeeUnits(g)
+}
+
+func (c *ConditionalSetTx) LoadUnits(g *Genesis) uint64 {
+	return c.FeeUnits(g)
+}
+
+func (c *ConditionalSetTx) Copy() UnsignedTransaction {
+	value := make([]byte, len(c.Value))
+	copy(value, c.Value)
+	ifMatch := ids.ID{}
+	copy(ifMatch[:], c.IfMatch[:])
+	return &ConditionalSetTx{
+		BaseTx:   c.BaseTx.Copy(),
+		Space:    c.Space,
+		Key:      c.Key,
+		Value:    value,
+		IfMatch:  ifMatch,
+		IfAbsent: c.IfAbsent,
+	}
+}
+
+func (c *ConditionalSetTx) TypedData() *tdata.TypedData {
+	return c.typedData(
+		ConditionalSet,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdKey, Type: tdString},
+			{Name: tdValue, Type: tdBytes},
+			{Name: tdIfMatch, Type: tdString},
+			{Name: tdIfAbsent, Type: tdBool},
+		},
+		tdata.TypedDataMessage{
+			tdSpace:    c.Space,
+			tdKey:      c.Key,
+			tdValue:    hexutil.Encode(c.Value),
+			tdIfMatch:  c.IfMatch.String(),
+			tdIfAbsent: c.IfAbsent,
+		},
+	)
+}
+
+func (c *ConditionalSetTx) Activity() *Activity {
+	return &Activity{
+		Typ:   ConditionalSet,
+		Space: c.Space,
+		Key:   c.Key,
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestConditionalSetTx(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	txID1, txID2, txID3 := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	tt := []struct {
+		utx  UnsignedTransaction
+		txID ids.ID
+		err  error
+	}{
+		{ // successful claim
+			utx:  &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			txID: ids.GenerateTestID(),
+		},
+		{ // no condition
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value"),
+			},
+			txID: ids.GenerateTestID(),
+			err:  ErrInvalidCondition,
+		},
+		{ // both conditions
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value"),
+				IfMatch: txID1, IfAbsent: true,
+			},
+			txID: ids.GenerateTestID(),
+			err:  ErrInvalidCondition,
+		},
+		{ // match missing key
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value"),
+				IfMatch: txID1,
+			},
+			txID: ids.GenerateTestID(),
+			err:  ErrConditionFailed,
+		},
+		{ // write absent key
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value"),
+				IfAbsent: true,
+			},
+			txID: txID1,
+		},
+		{ // write existing key with if absent
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value2"),
+				IfAbsent: true,
+			},
+			txID: ids.GenerateTestID(),
+			err:  ErrConditionFailed,
+		},
+		{ // write with stale match
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value2"),
+				IfMatch: txID3,
+			},
+			txID: ids.GenerateTestID(),
+			err:  ErrConditionFailed,
+		},
+		{ // write with current match
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value2"),
+				IfMatch: txID1,
+			},
+			txID: txID2,
+		},
+		{ // previous match is no longer current
+			utx: &ConditionalSetTx{
+				BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value3"),
+				IfMatch: txID1,
+			},
+			txID: ids.GenerateTestID(),
+			err:  ErrConditionFailed,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: 1,
+			TxID:      tv.txID,
+			Sender:    sender,
+		}
+		err := tv.utx.Execute(tc)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+
+	vmeta, exists, err := GetValueMeta(db, []byte("foo"), []byte("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || vmeta.TxID != txID2 || vmeta.ValueID != txID2 {
+		t.Fatalf("unexpected value meta %+v", vmeta)
+	}
+}
+
+func TestConditionalSetTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	c := &ConditionalSetTx{
+		BaseTx:  &BaseTx{BlockID: ids.GenerateTestID(), Magic: 5, Price: 10},
+		Space:   "foo",
+		Key:     "bar",
+		Value:   []byte("value"),
+		IfMatch: ids.GenerateTestID(),
+	}
+	if _, err := DigestHash(c); err != nil {
+		t.Fatal(err)
+	}
+	utx, err := ParseTypedData(c.TypedData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, utx) {
+		t.Fatalf("parsed tx expected %+v, got %+v", c, utx)
+	}
+}
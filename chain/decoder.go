@@ -21,6 +21,14 @@ const (
 	Delete   = "delete"
 	Move     = "move"
 	Transfer = "transfer"
+	Batch    = "batch"
+	Grant    = "grant"
+	Revoke   = "revoke"
+	Bid      = "bid"
+
+	SetOwners = "setOwners"
+
+	ConditionalSet = "conditionalSet"
 
 	// Non-user created event
 	Reward = "reward"
@@ -33,6 +41,22 @@ type Input struct {
 	Value []byte         `json:"value"`
 	To    common.Address `json:"to"`
 	Units uint64         `json:"units"`
+	TTL   uint64         `json:"ttl"`
+	Ops   []*BatchOp     `json:"ops"`
+
+	// Conditions for [ConditionalSet]
+	IfMatch  ids.ID `json:"ifMatch"`
+	IfAbsent bool   `json:"ifAbsent"`
+
+	// Permission fields for [Grant]
+	Write  bool   `json:"write"`
+	Delete bool   `json:"delete"`
+	Prefix string `json:"prefix"`
+	Expiry uint64 `json:"expiry"`
+
+	// Owner set for [SetOwners]
+	Owners    []common.Address `json:"owners"`
+	Threshold uint64           `json:"threshold"`
 }
 
 func (i *Input) Decode() (UnsignedTransaction, error) {
@@ -54,6 +78,7 @@ func (i *Input) Decode() (UnsignedTransaction, error) {
 			Space:  i.Space,
 			Key:    i.Key,
 			Value:  i.Value,
+			TTL:    i.TTL,
 		}, nil
 	case Delete:
 		return &DeleteTx{
@@ -73,6 +98,49 @@ func (i *Input) Decode() (UnsignedTransaction, error) {
 			To:     i.To,
 			Units:  i.Units,
 		}, nil
+	case Batch:
+		return &BatchTx{
+			BaseTx: &BaseTx{},
+			Ops:    i.Ops,
+		}, nil
+	case ConditionalSet:
+		return &ConditionalSetTx{
+			BaseTx:   &BaseTx{},
+			Space:    i.Space,
+			Key:      i.Key,
+			Value:    i.Value,
+			IfMatch:  i.IfMatch,
+			IfAbsent: i.IfAbsent,
+		}, nil
+	case Grant:
+		return &GrantTx{
+			BaseTx: &BaseTx{},
+			Space:  i.Space,
+			To:     i.To,
+			Write:  i.Write,
+			Delete: i.Delete,
+			Prefix: i.Prefix,
+			Expiry: i.Expiry,
+		}, nil
+	case Revoke:
+		return &RevokeTx{
+			BaseTx: &BaseTx{},
+			Space:  i.Space,
+			To:     i.To,
+		}, nil
+	case SetOwners:
+		return &SetOwnersTx{
+			BaseTx:    &BaseTx{},
+			Space:     i.Space,
+			Owners:    i.Owners,
+			Threshold: i.Threshold,
+		}, nil
+	case Bid:
+		return &BidTx{
+			BaseTx: &BaseTx{},
+			Space:  i.Space,
+			Units:  i.Units,
+		}, nil
 	default:
 		return nil, ErrInvalidType
 	}
@@ -83,15 +151,34 @@ const (
 	tdUint64  = "uint64"
 	tdBytes   = "bytes"
 	tdAddress = "address"
+	tdBool    = "bool"
 
-	tdBlockID = "blockID"
-	tdPrice   = "price"
+	tdBlockID     = "blockID"
+	tdPrice       = "price"
+	tdMaxPrice    = "maxPrice"
+	tdPriorityTip = "priorityTip"
 
 	tdSpace = "space"
 	tdKey   = "key"
 	tdValue = "value"
 	tdUnits = "units"
 	tdTo    = "to"
+	tdType  = "type"
+	tdOps   = "ops"
+	tdTTL   = "ttl"
+
+	tdIfMatch  = "ifMatch"
+	tdIfAbsent = "ifAbsent"
+
+	tdWrite  = "write"
+	tdDelete = "delete"
+	tdPrefix = "prefix"
+	tdExpiry = "expiry"
+
+	tdOwners    = "owners"
+	tdThreshold = "threshold"
+
+	tdBatchOp = "BatchOp"
 )
 
 func parseUint64Message(td *tdata.TypedData, k string) (uint64, error) {
@@ -115,6 +202,17 @@ func parseBaseTx(td *tdata.TypedData) (*BaseTx, error) {
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := td.Message[tdMaxPrice]; ok {
+		maxPrice, err := parseUint64Message(td, tdMaxPrice)
+		if err != nil {
+			return nil, err
+		}
+		tip, err := parseUint64Message(td, tdPriorityTip)
+		if err != nil {
+			return nil, err
+		}
+		return &BaseTx{BlockID: blockID, Magic: magic, MaxPrice: maxPrice, PriorityTip: tip}, nil
+	}
 	price, err := parseUint64Message(td, tdPrice)
 	if err != nil {
 		return nil, err
@@ -122,6 +220,50 @@ func parseBaseTx(td *tdata.TypedData) (*BaseTx, error) {
 	return &BaseTx{BlockID: blockID, Magic: magic, Price: price}, nil
 }
 
+func parseBatchOps(td *tdata.TypedData) ([]*BatchOp, error) {
+	rops, ok := td.Message[tdOps].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdOps)
+	}
+	ops := make([]*BatchOp, len(rops))
+	for i, rop := range rops {
+		m, ok := rop.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s[%d]", ErrTypedDataKeyMissing, tdOps, i)
+		}
+		typ, ok := m[tdType].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdType)
+		}
+		space, ok := m[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		key, ok := m[tdKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdKey)
+		}
+		rvalue, ok := m[tdValue].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdValue)
+		}
+		value, err := hexutil.Decode(rvalue)
+		if err != nil {
+			return nil, err
+		}
+		runits, ok := m[tdUnits].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdUnits)
+		}
+		units, err := strconv.ParseUint(runits, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ops[i] = &BatchOp{Typ: typ, Space: space, Key: key, Value: value, Units: units}
+	}
+	return ops, nil
+}
+
 func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 	bTx, err := parseBaseTx(td)
 	if err != nil {
@@ -162,7 +304,14 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &SetTx{BaseTx: bTx, Space: space, Key: key, Value: value}, nil
+		var ttl uint64
+		if _, ok := td.Message[tdTTL]; ok {
+			ttl, err = parseUint64Message(td, tdTTL)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &SetTx{BaseTx: bTx, Space: space, Key: key, Value: value, TTL: ttl}, nil
 	case Delete:
 		space, ok := td.Message[tdSpace].(string)
 		if !ok {
@@ -193,6 +342,116 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 			return nil, err
 		}
 		return &TransferTx{BaseTx: bTx, To: common.HexToAddress(to), Units: units}, nil
+	case Batch:
+		ops, err := parseBatchOps(td)
+		if err != nil {
+			return nil, err
+		}
+		return &BatchTx{BaseTx: bTx, Ops: ops}, nil
+	case ConditionalSet:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		key, ok := td.Message[tdKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdKey)
+		}
+		rvalue, ok := td.Message[tdValue].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdValue)
+		}
+		value, err := hexutil.Decode(rvalue)
+		if err != nil {
+			return nil, err
+		}
+		rifMatch, ok := td.Message[tdIfMatch].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdIfMatch)
+		}
+		ifMatch, err := ids.FromString(rifMatch)
+		if err != nil {
+			return nil, err
+		}
+		ifAbsent, ok := td.Message[tdIfAbsent].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdIfAbsent)
+		}
+		return &ConditionalSetTx{
+			BaseTx: bTx, Space: space, Key: key, Value: value,
+			IfMatch: ifMatch, IfAbsent: ifAbsent,
+		}, nil
+	case Grant:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		to, ok := td.Message[tdTo].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdTo)
+		}
+		write, ok := td.Message[tdWrite].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdWrite)
+		}
+		del, ok := td.Message[tdDelete].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdDelete)
+		}
+		prefix, ok := td.Message[tdPrefix].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdPrefix)
+		}
+		expiry, err := parseUint64Message(td, tdExpiry)
+		if err != nil {
+			return nil, err
+		}
+		return &GrantTx{
+			BaseTx: bTx, Space: space, To: common.HexToAddress(to),
+			Write: write, Delete: del, Prefix: prefix, Expiry: expiry,
+		}, nil
+	case Revoke:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		to, ok := td.Message[tdTo].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdTo)
+		}
+		return &RevokeTx{BaseTx: bTx, Space: space, To: common.HexToAddress(to)}, nil
+	case SetOwners:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		rowners, ok := td.Message[tdOwners].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdOwners)
+		}
+		var owners []common.Address
+		for i, rowner := range rowners {
+			owner, ok := rowner.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s[%d]", ErrTypedDataKeyMissing, tdOwners, i)
+			}
+			owners = append(owners, common.HexToAddress(owner))
+		}
+		threshold, err := parseUint64Message(td, tdThreshold)
+		if err != nil {
+			return nil, err
+		}
+		return &SetOwnersTx{BaseTx: bTx, Space: space, Owners: owners, Threshold: threshold}, nil
+	case Bid:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		units, err := parseUint64Message(td, tdUnits)
+		if err != nil {
+			return nil, err
+		}
+		return &BidTx{BaseTx: bTx, Space: space, Units: units}, nil
 	default:
 		return nil, ErrInvalidType
 	}
@@ -4,8 +4,6 @@
 package chain
 
 import (
-	"strconv"
-
 	"github.com/ava-labs/spacesvm/parser"
 	"github.com/ava-labs/spacesvm/tdata"
 )
@@ -34,8 +32,8 @@ func (d *DeleteTx) Execute(t *TransactionContext) error {
 		return err
 	}
 
-	// Verify space is owned by sender
-	i, err := verifySpace(d.Space, t)
+	// Verify sender is the space owner or allowed to delete
+	i, err := verifySpaceAccess(d.Space, d.Key, false, true, t)
 	if err != nil {
 		return err
 	}
@@ -53,6 +51,11 @@ func (d *DeleteTx) Execute(t *TransactionContext) error {
 	if err := DeleteSpaceKey(t.Database, []byte(d.Space), []byte(d.Key)); err != nil {
 		return err
 	}
+	if v.Expiry > 0 {
+		if err := DeleteKeyExpiry(t.Database, i.RawSpace, []byte(d.Key), v.Expiry); err != nil {
+			return err
+		}
+	}
 	return updateSpace(d.Space, t, timeRemaining, i)
 }
 
@@ -65,19 +68,15 @@ func (d *DeleteTx) Copy() UnsignedTransaction {
 }
 
 func (d *DeleteTx) TypedData() *tdata.TypedData {
-	return tdata.CreateTypedData(
-		d.Magic, Delete,
+	return d.typedData(
+		Delete,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdKey, Type: tdString},
-			{Name: tdPrice, Type: tdUint64},
-			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   d.Space,
-			tdKey:     d.Key,
-			tdPrice:   strconv.FormatUint(d.Price, 10),
-			tdBlockID: d.BlockID.String(),
+			tdSpace: d.Space,
+			tdKey:   d.Key,
 		},
 	)
 }
@@ -12,15 +12,23 @@ var (
 	ErrInvalidMagic     = errors.New("invalid magic")
 	ErrInvalidBlockRate = errors.New("invalid block rate")
 
+	ErrInvalidBaseFeeChangeDenominator = errors.New("invalid base fee change denominator")
+	ErrInvalidBaseFeeBurnPercent       = errors.New("invalid base fee burn percent")
+
 	// Block Correctness
 	ErrTimestampTooEarly      = errors.New("block timestamp too early")
 	ErrTimestampTooLate       = errors.New("block timestamp too late")
 	ErrNoTxs                  = errors.New("no transactions")
 	ErrInvalidCost            = errors.New("invalid block cost")
 	ErrInvalidPrice           = errors.New("invalid price")
+	ErrInvalidStateRoot       = errors.New("invalid state root")
 	ErrInsufficientSurplus    = errors.New("insufficient surplus fee")
 	ErrParentBlockNotVerified = errors.New("parent block not verified or accepted")
 
+	// State Sync Correctness
+	ErrInvalidStateChunk   = errors.New("invalid state chunk")
+	ErrStateSummaryMissing = errors.New("state summary is not retained")
+
 	// Tx Correctness
 	ErrInvalidBlockID      = errors.New("invalid blockID")
 	ErrInvalidSignature    = errors.New("invalid signature")
@@ -28,6 +36,8 @@ var (
 	ErrInsufficientPrice   = errors.New("insufficient price")
 	ErrInvalidType         = errors.New("invalid tx type")
 	ErrTypedDataKeyMissing = errors.New("typed data key missing")
+	ErrTooManySignatures   = errors.New("too many signatures")
+	ErrDuplicateSignature  = errors.New("duplicate signature")
 
 	// Execution Correctness
 	ErrValueEmpty      = errors.New("value empty")
@@ -42,4 +52,21 @@ var (
 	ErrInvalidBalance  = errors.New("invalid balance")
 	ErrNonActionable   = errors.New("transaction doesn't do anything")
 	ErrBlockTooBig     = errors.New("block too big")
+	ErrInvalidBatchOp  = errors.New("invalid batch operation")
+	ErrTooManyBatchOps = errors.New("too many batch operations")
+
+	ErrPermissionMissing  = errors.New("permission missing")
+	ErrTooManyPermissions = errors.New("too many permissions")
+	ErrInvalidExpiry      = errors.New("invalid expiry")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrInvalidOwners      = errors.New("invalid owner set")
+
+	ErrAuctionRequired = errors.New("space must be won in an auction")
+	ErrAuctionDisabled = errors.New("auctions are disabled")
+	ErrAuctionClosed   = errors.New("auction closed")
+	ErrAuctionMissing  = errors.New("auction missing")
+	ErrBidTooLow       = errors.New("bid too low")
+
+	ErrInvalidCondition = errors.New("exactly one of ifMatch or ifAbsent must be set")
+	ErrConditionFailed  = errors.New("condition not satisfied")
 )
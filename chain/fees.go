@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+// DefaultPriorityTip is the tip per unit suggested for transactions when
+// [Genesis.FeeMarketEnabled].
+const DefaultPriorityTip = 1
+
+// EffectivePrice returns the price per fee unit [utx] pays in a block with
+// price [base]. Fee market transactions (those with a [MaxPrice]) pay
+// min([MaxPrice], [base]+[PriorityTip]) while all others pay their [Price].
+//
+// A fee market transaction can only be included if its effective price is at
+// least [base].
+func EffectivePrice(utx UnsignedTransaction, base uint64) uint64 {
+	maxPrice := utx.GetMaxPrice()
+	if maxPrice == 0 {
+		return utx.GetPrice()
+	}
+	if base >= maxPrice {
+		return maxPrice
+	}
+	if tip := utx.GetPriorityTip(); tip < maxPrice-base {
+		return base + tip
+	}
+	return maxPrice
+}
+
+// EffectiveTip returns the amount per fee unit [utx] pays over [base] (0 if
+// it can't afford [base]).
+func EffectiveTip(utx UnsignedTransaction, base uint64) uint64 {
+	price := EffectivePrice(utx, base)
+	if price < base {
+		return 0
+	}
+	return price - base
+}
+
+// Priority returns the value transactions are ordered by when building a block
+// with price [base].
+//
+// Fee market transactions are ordered by their effective tip. Those that can't
+// afford [base] have a priority of 0 and all others are offset by 1 so that a
+// priority lower than [base] is never returned for an includable transaction
+// in a block where [base] is the minimum price.
+func Priority(utx UnsignedTransaction, base uint64) uint64 {
+	if utx.GetMaxPrice() == 0 {
+		return utx.GetPrice()
+	}
+	if EffectivePrice(utx, base) < base {
+		return 0
+	}
+	return EffectiveTip(utx, base) + 1
+}
+
+// MaxFee is the most [utx] could be charged for [feeUnits].
+func MaxFee(utx UnsignedTransaction, feeUnits uint64) uint64 {
+	if maxPrice := utx.GetMaxPrice(); maxPrice > 0 {
+		return feeUnits * maxPrice
+	}
+	return feeUnits * utx.GetPrice()
+}
+
+// SetFee populates the fee fields of [utx] to pay for a block [price] and
+// block [cost] or to offer [tip].
+//
+// When [Genesis.FeeMarketEnabled], [utx] offers [tip] and can be included
+// until the block price doubles. Otherwise, the block cost is spread over the
+// [FeeUnits] of [utx] and [tip] is ignored.
+func SetFee(g *Genesis, utx UnsignedTransaction, price uint64, cost uint64, tip uint64) {
+	if g.FeeMarketEnabled {
+		utx.SetPrice(0)
+		utx.SetPriorityTip(tip)
+		utx.SetMaxPrice(2*price + tip)
+		return
+	}
+	utx.SetMaxPrice(0)
+	utx.SetPriorityTip(0)
+	utx.SetPrice(price + cost/utx.FeeUnits(g))
+}
+
+// NextBasePrice returns the base price of the block after a block with price
+// [base] when [Genesis.FeeMarketEnabled].
+//
+// The base price moves towards the price where [recentUnits] equals
+// [targetUnits] by at most 1/[BaseFeeChangeDenominator] of [base] per block
+// (and by at least 1 if usage is not on target). It never drops below
+// [MinPrice].
+func NextBasePrice(g *Genesis, base uint64, recentUnits uint64, targetUnits uint64) uint64 {
+	if targetUnits == 0 || recentUnits == targetUnits {
+		return base
+	}
+	var diff uint64
+	if recentUnits > targetUnits {
+		diff = recentUnits - targetUnits
+	} else {
+		diff = targetUnits - recentUnits
+	}
+	if diff > targetUnits {
+		diff = targetUnits
+	}
+	delta := base * diff / targetUnits / g.BaseFeeChangeDenominator
+	if delta == 0 {
+		delta = 1
+	}
+	if recentUnits > targetUnits {
+		return base + delta
+	}
+	if base < g.MinPrice || base-g.MinPrice <= delta {
+		return g.MinPrice
+	}
+	return base - delta
+}
+
+// burnedPrice is the part of [base] that is burned per fee unit when
+// [Genesis.FeeMarketEnabled].
+func burnedPrice(g *Genesis, base uint64) uint64 {
+	return base * g.BaseFeeBurnPercent / 100
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestFeeMarketBaseTx(t *testing.T) {
+	t.Parallel()
+
+	blkID := ids.GenerateTestID()
+	tt := []struct {
+		tx        *BaseTx
+		feeMarket bool
+		err       error
+	}{
+		{
+			tx:        &BaseTx{BlockID: blkID, MaxPrice: 10, PriorityTip: 2},
+			feeMarket: true,
+		},
+		{
+			tx:        &BaseTx{BlockID: blkID, Price: 1, MaxPrice: 10},
+			feeMarket: true,
+			err:       ErrInvalidPrice,
+		},
+		{
+			tx:        &BaseTx{BlockID: blkID, PriorityTip: 2},
+			feeMarket: true,
+			err:       ErrInvalidPrice,
+		},
+		{
+			tx:        &BaseTx{BlockID: blkID, MaxPrice: 10, PriorityTip: 11},
+			feeMarket: true,
+			err:       ErrInvalidPrice,
+		},
+		{
+			tx:  &BaseTx{BlockID: blkID, Price: 1, PriorityTip: 2},
+			err: ErrInvalidPrice,
+		},
+		{
+			tx:  &BaseTx{BlockID: blkID, MaxPrice: 10},
+			err: ErrInvalidPrice,
+		},
+	}
+	for i, tv := range tt {
+		g := DefaultGenesis()
+		g.FeeMarketEnabled = tv.feeMarket
+		err := tv.tx.ExecuteBase(g)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.ExecuteBase err expected %v, got %v", i, tv.err, err)
+		}
+	}
+}
+
+func TestEffectivePrice(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		tx       *BaseTx
+		base     uint64
+		price    uint64
+		tip      uint64
+		priority uint64
+	}{
+		{ // legacy txs always pay [Price]
+			tx:       &BaseTx{Price: 5},
+			base:     10,
+			price:    5,
+			tip:      0,
+			priority: 5,
+		},
+		{
+			tx:       &BaseTx{MaxPrice: 20, PriorityTip: 3},
+			base:     10,
+			price:    13,
+			tip:      3,
+			priority: 4,
+		},
+		{ // tip is capped by [MaxPrice]
+			tx:       &BaseTx{MaxPrice: 12, PriorityTip: 3},
+			base:     10,
+			price:    12,
+			tip:      2,
+			priority: 3,
+		},
+		{
+			tx:       &BaseTx{MaxPrice: 10, PriorityTip: 3},
+			base:     10,
+			price:    10,
+			tip:      0,
+			priority: 1,
+		},
+		{ // can't afford base
+			tx:       &BaseTx{MaxPrice: 8, PriorityTip: 3},
+			base:     10,
+			price:    8,
+			tip:      0,
+			priority: 0,
+		},
+	}
+	for i, tv := range tt {
+		utx := &TransferTx{BaseTx: tv.tx}
+		if price := EffectivePrice(utx, tv.base); price != tv.price {
+			t.Fatalf("#%d: price expected %d, got %d", i, tv.price, price)
+		}
+		if tip := EffectiveTip(utx, tv.base); tip != tv.tip {
+			t.Fatalf("#%d: tip expected %d, got %d", i, tv.tip, tip)
+		}
+		if priority := Priority(utx, tv.base); priority != tv.priority {
+			t.Fatalf("#%d: priority expected %d, got %d", i, tv.priority, priority)
+		}
+	}
+}
+
+func TestNextBasePrice(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	g.FeeMarketEnabled = true
+	g.MinPrice = 2
+	tt := []struct {
+		base   uint64
+		recent uint64
+		next   uint64
+	}{
+		{base: 800, recent: 100, next: 800},
+		{base: 800, recent: 150, next: 850},
+		{base: 800, recent: 50, next: 750},
+		// Change is bounded by 1/[BaseFeeChangeDenominator]
+		{base: 800, recent: 10000, next: 900},
+		{base: 800, recent: 0, next: 700},
+		// Change is at least 1
+		{base: 2, recent: 101, next: 3},
+		// Never below [MinPrice]
+		{base: 3, recent: 0, next: 2},
+		{base: 2, recent: 0, next: 2},
+	}
+	for i, tv := range tt {
+		if next := NextBasePrice(g, tv.base, tv.recent, 100); next != tv.next {
+			t.Fatalf("#%d: next base price expected %d, got %d", i, tv.next, next)
+		}
+	}
+}
+
+func TestFeeMarketTypedData(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	for _, feeMarket := range []bool{false, true} {
+		g.FeeMarketEnabled = feeMarket
+		utx := &TransferTx{
+			BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Magic: g.Magic},
+			Units:  10,
+		}
+		SetFee(g, utx, 5, 20, DefaultPriorityTip)
+		td := utx.TypedData()
+		_, hasPrice := td.Message[tdPrice]
+		_, hasMaxPrice := td.Message[tdMaxPrice]
+		if hasPrice == feeMarket || hasMaxPrice != feeMarket {
+			t.Fatalf("unexpected fee fields in typed data (fee market=%t): %v", feeMarket, td.Message)
+		}
+		parsed, err := ParseTypedData(td)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.GetPrice() != utx.Price || parsed.GetMaxPrice() != utx.MaxPrice ||
+			parsed.GetPriorityTip() != utx.PriorityTip {
+			t.Fatalf("fee fields expected %+v, got %+v", utx.BaseTx, parsed)
+		}
+		if feeMarket && (utx.MaxPrice != 2*5+DefaultPriorityTip || utx.PriorityTip != DefaultPriorityTip) {
+			t.Fatalf("unexpected fee market fields %+v", utx.BaseTx)
+		}
+		if !feeMarket && utx.Price != 5+20/utx.FeeUnits(g) {
+			t.Fatalf("unexpected price %d", utx.Price)
+		}
+	}
+}
+
+func TestFeeMarketReceipt(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	g.FeeMarketEnabled = true
+	g.BaseFeeBurnPercent = 50
+	tx := &Transaction{
+		UnsignedTransaction: &TransferTx{
+			BaseTx: &BaseTx{MaxPrice: 30, PriorityTip: 2},
+			Units:  10,
+		},
+	}
+	blk := &StatelessBlock{StatefulBlock: &StatefulBlock{Price: 10}, Winners: map[ids.ID]*Activity{}}
+	units := tx.FeeUnits(g)
+
+	// Without a lottery reward, the whole fee is burned
+	r := newReceipt(g, blk, 0, tx)
+	if r.Price != 12 || r.Fee != units*12 || r.Burned != r.Fee || r.Reward != 0 {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+
+	// Otherwise, only the burned part of the base price is
+	blk.Winners[tx.ID()] = &Activity{Units: tx.rewardAmount(g, blk.Price)}
+	r = newReceipt(g, blk, 0, tx)
+	if r.Burned != units*burnedPrice(g, blk.Price) || r.Burned+r.Reward != r.Fee {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+}
@@ -44,11 +44,15 @@ type Genesis struct {
 
 	// Tx params
 	BaseTxUnits uint64 `serialize:"true" json:"baseTxUnits"`
+	// MaxBatchOps is the number of operations a [BatchTx] can have (no limit
+	// if 0).
+	MaxBatchOps uint64 `serialize:"true" json:"maxBatchOps"`
 
 	// SetTx params
 	ValueUnitSize       uint64 `serialize:"true" json:"valueUnitSize"`
 	MaxValueSize        uint64 `serialize:"true" json:"maxValueSize"`
 	ValueExpiryDiscount uint64 `serialize:"true" json:"valueExpiryDiscount"`
+	FullValueTTL        uint64 `serialize:"true" json:"fullValueTTL"` // seconds
 
 	// Claim Params
 	ClaimLoadMultiplier         uint64 `serialize:"true" json:"claimLoadMultiplier"`
@@ -58,6 +62,22 @@ type Genesis struct {
 	// Lifeline Params
 	SpaceRenewalDiscount uint64 `serialize:"true" json:"spaceRenewalDiscount"`
 
+	// Permission Params
+	//
+	// [MaxPermissions] is the number of permissions a space can have at once
+	// (no limit if 0), which bounds the work of clearing them when the space
+	// is moved or expires.
+	MaxPermissions uint64 `serialize:"true" json:"maxPermissions"`
+
+	// Auction Params
+	//
+	// If [AuctionWindow] is not 0, spaces (other than address spaces) can no
+	// longer be claimed with a [ClaimTx] and are instead given to the highest
+	// bidder [AuctionWindow] seconds after they expire (or after the first
+	// [BidTx] if they have no open auction).
+	AuctionWindow uint64 `serialize:"true" json:"auctionWindow"` // seconds
+	MinBid        uint64 `serialize:"true" json:"minBid"`
+
 	// Reward Params
 	ClaimReward      uint64 `serialize:"true" json:"claimReward"`
 	ClaimExpiryUnits uint64 `serialize:"true" json:"claimExpiryUnits"`
@@ -73,6 +93,19 @@ type Genesis struct {
 	MaxBlockSize     uint64 `serialize:"true" json:"maxBlockSize"`    // units
 	BlockCostEnabled bool   `serialize:"true" json:"blockCostEnabled"`
 
+	// Fee Market Params
+	//
+	// If [FeeMarketEnabled] is true, the block price is a protocol base price
+	// that moves by at most 1/[BaseFeeChangeDenominator] of itself per block
+	// (proportionally to how far usage is from the target) and transactions
+	// specify a [MaxPrice] and [PriorityTip] instead of a [Price]. There is no
+	// block cost in this mode. [BaseFeeBurnPercent] of the base price paid by
+	// each transaction is burned and the rest, along with the tip, is
+	// distributed by the lottery.
+	FeeMarketEnabled         bool   `serialize:"true" json:"feeMarketEnabled"`
+	BaseFeeChangeDenominator uint64 `serialize:"true" json:"baseFeeChangeDenominator"`
+	BaseFeeBurnPercent       uint64 `serialize:"true" json:"baseFeeBurnPercent"`
+
 	// Allocations
 	CustomAllocation []*CustomAllocation `serialize:"true" json:"customAllocation"`
 	AirdropHash      string              `serialize:"true" json:"airdropHash"`
@@ -83,11 +116,13 @@ func DefaultGenesis() *Genesis {
 	return &Genesis{
 		// Tx params
 		BaseTxUnits: 1,
+		MaxBatchOps: 64,
 
 		// SetTx params
 		ValueUnitSize:       DefaultValueUnitSize,
 		MaxValueSize:        200 * units.KiB,
 		ValueExpiryDiscount: 10,
+		FullValueTTL:        DefaultFreeClaimDuration,
 
 		// Claim Params
 		ClaimLoadMultiplier:         5,
@@ -98,6 +133,9 @@ func DefaultGenesis() *Genesis {
 		// Lifeline Params
 		SpaceRenewalDiscount: 10,
 
+		// Permission Params
+		MaxPermissions: 64,
+
 		// Reward Params
 		ClaimReward: DefaultFreeClaimUnits * DefaultFreeClaimDuration,
 
@@ -111,6 +149,10 @@ func DefaultGenesis() *Genesis {
 		MaxBlockSize:     246,                   // ~246KB -> Limited to 256KB by AvalancheGo (as of v1.7.3)
 		MinPrice:         1,
 		BlockCostEnabled: true,
+
+		// Fee Market Params
+		BaseFeeChangeDenominator: 8,
+		BaseFeeBurnPercent:       100,
 	}
 }
 
@@ -128,6 +170,14 @@ func (g *Genesis) Verify() error {
 	if g.TargetBlockRate == 0 {
 		return ErrInvalidBlockRate
 	}
+	if g.FeeMarketEnabled {
+		if g.BaseFeeChangeDenominator == 0 {
+			return ErrInvalidBaseFeeChangeDenominator
+		}
+		if g.BaseFeeBurnPercent > 100 {
+			return ErrInvalidBaseFeeBurnPercent
+		}
+	}
 	return nil
 }
 
@@ -138,6 +188,7 @@ func (g *Genesis) Load(db database.Database, airdropData []byte) error {
 	}()
 
 	vdb := versiondb.New(db)
+	sdb := NewStateDB(vdb)
 	if len(g.AirdropHash) > 0 {
 		h := common.BytesToHash(crypto.Keccak256(airdropData)).Hex()
 		if g.AirdropHash != h {
@@ -150,7 +201,7 @@ func (g *Genesis) Load(db database.Database, airdropData []byte) error {
 		}
 
 		for _, alloc := range airdrop {
-			if err := SetBalance(vdb, alloc.Address, g.AirdropUnits); err != nil {
+			if err := SetBalance(sdb, alloc.Address, g.AirdropUnits); err != nil {
 				return fmt.Errorf("%w: addr=%s, bal=%d", err, alloc.Address, g.AirdropUnits)
 			}
 		}
@@ -163,12 +214,17 @@ func (g *Genesis) Load(db database.Database, airdropData []byte) error {
 	// Do custom allocation last in case an address shows up in standard
 	// allocation
 	for _, alloc := range g.CustomAllocation {
-		if err := SetBalance(vdb, alloc.Address, alloc.Balance); err != nil {
+		if err := SetBalance(sdb, alloc.Address, alloc.Balance); err != nil {
 			return fmt.Errorf("%w: addr=%s, bal=%d", err, alloc.Address, alloc.Balance)
 		}
 		log.Debug("applied custom allocation", "addr", alloc.Address, "balance", alloc.Balance)
 	}
 
+	// Add allocations to the state trie (see [GetStateRoot])
+	if _, err := CommitState(sdb); err != nil {
+		return err
+	}
+
 	// Commit as a batch to improve speed
 	return vdb.Commit()
 }
@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &GrantTx{}
+
+// GrantTx allows the owner of [Space] to delegate write and/or delete rights
+// to another address. Issuing a [GrantTx] for an address that already has
+// a permission replaces it.
+type GrantTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// To is the grantee of the permission.
+	To common.Address `serialize:"true" json:"to"`
+
+	// Write allows [To] to issue [SetTx] in [Space].
+	Write bool `serialize:"true" json:"write"`
+
+	// Delete allows [To] to issue [DeleteTx] in [Space].
+	Delete bool `serialize:"true" json:"delete"`
+
+	// Prefix (if not empty) restricts [To] to keys that start with it.
+	Prefix string `serialize:"true" json:"prefix"`
+
+	// Expiry (if not 0) is the unix time after which the permission no longer
+	// applies.
+	Expiry uint64 `serialize:"true" json:"expiry"`
+}
+
+func (g *GrantTx) Execute(t *TransactionContext) error {
+	if err := parser.CheckContents(g.Space); err != nil {
+		return err
+	}
+	if len(g.Prefix) > 0 {
+		if err := parser.CheckContents(g.Prefix); err != nil {
+			return err
+		}
+	}
+
+	// Must grant something to someone else
+	if !g.Write && !g.Delete {
+		return ErrNonActionable
+	}
+	if bytes.Equal(g.To[:], zeroAddress[:]) {
+		return ErrNonActionable
+	}
+	if bytes.Equal(g.To[:], t.Sender[:]) {
+		return ErrNonActionable
+	}
+	if g.Expiry != 0 && g.Expiry <= t.BlockTime {
+		return ErrInvalidExpiry
+	}
+
+	// Verify space is owned by sender
+	i, err := verifySpace(g.Space, t)
+	if err != nil {
+		return err
+	}
+
+	// Replacing a permission doesn't add one
+	if max := t.Genesis.MaxPermissions; max > 0 {
+		_, exists, err := GetPermission(t.Database, i.RawSpace, g.To)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			n, err := countPermissions(t.Database, i.RawSpace, max)
+			if err != nil {
+				return err
+			}
+			if n >= max {
+				return ErrTooManyPermissions
+			}
+		}
+	}
+	if g.Expiry != 0 {
+		if err := PutPermissionExpiry(t.Database, []byte(g.Space), i.RawSpace, g.To, g.Expiry); err != nil {
+			return err
+		}
+	}
+	return PutPermission(t.Database, i.RawSpace, &Permission{
+		Grantee: g.To,
+		Write:   g.Write,
+		Delete:  g.Delete,
+		Prefix:  g.Prefix,
+		Expiry:  g.Expiry,
+	})
+}
+
+func (g *GrantTx) Copy() UnsignedTransaction {
+	to := make([]byte, common.AddressLength)
+	copy(to, g.To[:])
+	return &GrantTx{
+		BaseTx: g.BaseTx.Copy(),
+		Space:  g.Space,
+		To:     common.BytesToAddress(to),
+		Write:  g.Write,
+		Delete: g.Delete,
+		Prefix: g.Prefix,
+		Expiry: g.Expiry,
+	}
+}
+
+func (g *GrantTx) TypedData() *tdata.TypedData {
+	return g.typedData(
+		Grant,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdTo, Type: tdAddress},
+			{Name: tdWrite, Type: tdBool},
+			{Name: tdDelete, Type: tdBool},
+			{Name: tdPrefix, Type: tdString},
+			{Name: tdExpiry, Type: tdUint64},
+		},
+		tdata.TypedDataMessage{
+			tdSpace:  g.Space,
+			tdTo:     g.To.Hex(),
+			tdWrite:  g.Write,
+			tdDelete: g.Delete,
+			tdPrefix: g.Prefix,
+			tdExpiry: strconv.FormatUint(g.Expiry, 10),
+		},
+	)
+}
+
+func (g *GrantTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Grant,
+		Space: g.Space,
+		Key:   g.Prefix,
+		To:    g.To.Hex(),
+	}
+}
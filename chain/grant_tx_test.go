@@ -0,0 +1,347 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestGrantTx(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := crypto.PubkeyToAddress(priv.PublicKey)
+
+	priv2, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := crypto.PubkeyToAddress(priv2.PublicKey)
+
+	priv3, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := crypto.PubkeyToAddress(priv3.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{ // successful claim
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{ // write before grant
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "blog1", Value: []byte("a")},
+			blockTime: 1,
+			sender:    writer,
+			err:       ErrUnauthorized,
+		},
+		{ // grant nothing
+			utx:       &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: writer},
+			blockTime: 1,
+			sender:    owner,
+			err:       ErrNonActionable,
+		},
+		{ // grant by non-owner
+			utx:       &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: other, Write: true},
+			blockTime: 1,
+			sender:    writer,
+			err:       ErrUnauthorized,
+		},
+		{ // grant already expired
+			utx:       &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: writer, Write: true, Expiry: 1},
+			blockTime: 1,
+			sender:    owner,
+			err:       ErrInvalidExpiry,
+		},
+		{ // grant write under prefix
+			utx: &GrantTx{
+				BaseTx: &BaseTx{}, Space: "foo", To: writer,
+				Write: true, Prefix: "blog", Expiry: 10,
+			},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{ // write under prefix
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "blog1", Value: []byte("a")},
+			blockTime: 2,
+			sender:    writer,
+		},
+		{ // write outside of prefix
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "other", Value: []byte("a")},
+			blockTime: 2,
+			sender:    writer,
+			err:       ErrUnauthorized,
+		},
+		{ // delete without delete right
+			utx:       &DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "blog1"},
+			blockTime: 2,
+			sender:    writer,
+			err:       ErrUnauthorized,
+		},
+		{ // write by address without permission
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "blog2", Value: []byte("a")},
+			blockTime: 2,
+			sender:    other,
+			err:       ErrUnauthorized,
+		},
+		{ // write after permission expired
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "blog2", Value: []byte("a")},
+			blockTime: 11,
+			sender:    writer,
+			err:       ErrUnauthorized,
+		},
+		{ // replace with delete-only grant
+			utx:       &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: writer, Delete: true},
+			blockTime: 12,
+			sender:    owner,
+		},
+		{ // delete with delete right
+			utx:       &DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "blog1"},
+			blockTime: 13,
+			sender:    writer,
+		},
+		{ // revoke by non-owner
+			utx:       &RevokeTx{BaseTx: &BaseTx{}, Space: "foo", To: writer},
+			blockTime: 13,
+			sender:    writer,
+			err:       ErrUnauthorized,
+		},
+		{ // revoke
+			utx:       &RevokeTx{BaseTx: &BaseTx{}, Space: "foo", To: writer},
+			blockTime: 13,
+			sender:    owner,
+		},
+		{ // revoke missing permission
+			utx:       &RevokeTx{BaseTx: &BaseTx{}, Space: "foo", To: writer},
+			blockTime: 13,
+			sender:    owner,
+			err:       ErrPermissionMissing,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: tv.blockTime,
+			TxID:      ids.GenerateTestID(),
+			Sender:    tv.sender,
+		}
+		err := tv.utx.Execute(tc)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+
+	info, exists, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	perms, err := GetAllPermissions(db, info.RawSpace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms) != 0 {
+		t.Fatalf("unexpected permissions %+v", perms)
+	}
+}
+
+func TestGrantTxExpiry(t *testing.T) {
+	t.Parallel()
+
+	owner, writer, other, renewed := common.Address{0x1}, common.Address{0x2}, common.Address{0x3}, common.Address{0x4}
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	for i, tv := range []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+	}{
+		{utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}, blockTime: 1},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: writer, Write: true, Expiry: 10}, blockTime: 1},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: other, Write: true}, blockTime: 1},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: renewed, Write: true, Expiry: 10}, blockTime: 1},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: renewed, Write: true, Expiry: 20}, blockTime: 2},
+	} {
+		if err := tv.utx.Execute(&TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: tv.blockTime,
+			TxID:      ids.GenerateTestID(),
+			Sender:    owner,
+		}); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+	}
+
+	// Only the permission that is still set to expire at 10 is removed
+	if err := ExpireNext(g, db, 2, 11, true); err != nil {
+		t.Fatal(err)
+	}
+	info, exists, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	perms, err := GetAllPermissions(db, info.RawSpace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms) != 2 || perms[0].Grantee != other || perms[1].Grantee != renewed {
+		t.Fatalf("unexpected permissions %+v", perms)
+	}
+
+	if err := ExpireNext(g, db, 11, 21, true); err != nil {
+		t.Fatal(err)
+	}
+	perms, err = GetAllPermissions(db, info.RawSpace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms) != 1 || perms[0].Grantee != other {
+		t.Fatalf("unexpected permissions %+v", perms)
+	}
+}
+
+func TestGrantTxMaxPermissions(t *testing.T) {
+	t.Parallel()
+
+	owner, a, b, c := common.Address{0x1}, common.Address{0x2}, common.Address{0x3}, common.Address{0x4}
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.MaxPermissions = 2
+	for i, tv := range []struct {
+		utx UnsignedTransaction
+		err error
+	}{
+		{utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: a, Write: true}},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: b, Write: true}},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: c, Write: true}, err: ErrTooManyPermissions},
+		// Permissions can still be replaced at the limit
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: a, Delete: true}},
+		{utx: &RevokeTx{BaseTx: &BaseTx{}, Space: "foo", To: b}},
+		{utx: &GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: c, Write: true}},
+	} {
+		err := tv.utx.Execute(&TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: 1,
+			TxID:      ids.GenerateTestID(),
+			Sender:    owner,
+		})
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+	info, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	perms, err := GetAllPermissions(db, info.RawSpace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms) != 2 || perms[0].Grantee != a || !perms[0].Delete || perms[1].Grantee != c {
+		t.Fatalf("unexpected permissions %+v", perms)
+	}
+}
+
+func TestMoveTxClearsPermissions(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := crypto.PubkeyToAddress(priv.PublicKey)
+
+	priv2, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := crypto.PubkeyToAddress(priv2.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	for i, utx := range []UnsignedTransaction{
+		&ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+		&GrantTx{BaseTx: &BaseTx{}, Space: "foo", To: writer, Write: true},
+		&MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: common.HexToAddress("0x1")},
+	} {
+		tc := &TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: 1,
+			TxID:      ids.GenerateTestID(),
+			Sender:    owner,
+		}
+		if err := utx.Execute(tc); err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+	}
+
+	info, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, has, err := GetPermission(db, info.RawSpace, writer); has || err != nil {
+		t.Fatalf("unexpected has %v, err %v", has, err)
+	}
+}
+
+func TestGrantTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	gtx := &GrantTx{
+		BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Magic: 5, Price: 10},
+		Space:  "foo",
+		To:     common.HexToAddress("0x1"),
+		Write:  true,
+		Prefix: "blog",
+		Expiry: 100,
+	}
+	rtx := &RevokeTx{
+		BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Magic: 5, Price: 10},
+		Space:  "foo",
+		To:     common.HexToAddress("0x1"),
+	}
+	for _, utx := range []UnsignedTransaction{gtx, rtx} {
+		if _, err := DigestHash(utx); err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseTypedData(utx.TypedData())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(utx, parsed) {
+			t.Fatalf("parsed tx expected %+v, got %+v", utx, parsed)
+		}
+	}
+}
@@ -45,6 +45,10 @@ func (l *LifelineTx) Execute(t *TransactionContext) error {
 	if !has {
 		return ErrSpaceMissing
 	}
+	// Spaces with an owner set can only be extended by their owners
+	if len(i.Owners) > 0 && !t.controls(i) {
+		return ErrUnauthorized
+	}
 	// Lifeline spread across all units
 	lastExpiry := i.Expiry
 	i.Expiry += (g.ClaimReward * l.Units) / i.Units
@@ -76,19 +80,15 @@ func (l *LifelineTx) Copy() UnsignedTransaction {
 }
 
 func (l *LifelineTx) TypedData() *tdata.TypedData {
-	return tdata.CreateTypedData(
-		l.Magic, Lifeline,
+	return l.typedData(
+		Lifeline,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdUnits, Type: tdUint64},
-			{Name: tdPrice, Type: tdUint64},
-			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   l.Space,
-			tdUnits:   strconv.FormatUint(l.Units, 10),
-			tdPrice:   strconv.FormatUint(l.Price, 10),
-			tdBlockID: l.BlockID.String(),
+			tdSpace: l.Space,
+			tdUnits: strconv.FormatUint(l.Units, 10),
 		},
 	)
 }
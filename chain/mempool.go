@@ -13,4 +13,5 @@ type Mempool interface {
 	PopMax() (*Transaction, uint64)
 	Add(*Transaction) bool
 	NewTxs(uint64) []*Transaction
+	SetBasePrice(uint64)
 }
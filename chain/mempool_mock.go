@@ -105,3 +105,15 @@ func (mr *MockMempoolMockRecorder) Prune(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockMempool)(nil).Prune), arg0)
 }
+
+// SetBasePrice mocks base method.
+func (m *MockMempool) SetBasePrice(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBasePrice", arg0)
+}
+
+// SetBasePrice indicates an expected call of SetBasePrice.
+func (mr *MockMempoolMockRecorder) SetBasePrice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBasePrice", reflect.TypeOf((*MockMempool)(nil).SetBasePrice), arg0)
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons a tx is skipped while building a block
+const (
+	skipPrice   = "price"
+	skipSize    = "size"
+	skipInvalid = "invalid"
+)
+
+// Metrics are the metrics of building, verifying, and accepting blocks.
+type Metrics struct {
+	buildAttempts prometheus.Counter
+	skippedTxs    *prometheus.CounterVec
+	blockFill     prometheus.Histogram
+	verifyLatency prometheus.Histogram
+	acceptLatency prometheus.Histogram
+}
+
+// NewMetrics creates the block metrics and registers them with [reg].
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		buildAttempts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "block",
+			Name:      "build_attempts",
+			Help:      "number of attempts to build a block",
+		}),
+		skippedTxs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "block",
+			Name:      "skipped_txs",
+			Help:      "number of mempool transactions left out of built blocks",
+		}, []string{"reason"}),
+		blockFill: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "block",
+			Name:      "fill_ratio",
+			Help:      "units of built blocks over the max block size",
+			Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+		}),
+		verifyLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "block",
+			Name:      "verify_seconds",
+			Help:      "time spent verifying blocks",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		acceptLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "block",
+			Name:      "accept_seconds",
+			Help:      "time spent accepting blocks",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.buildAttempts),
+		reg.Register(m.skippedTxs),
+		reg.Register(m.blockFill),
+		reg.Register(m.verifyLatency),
+		reg.Register(m.acceptLatency),
+	)
+	return m, errs.Err
+}
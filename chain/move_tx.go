@@ -5,8 +5,8 @@ package chain
 
 import (
 	"bytes"
-	"strconv"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ava-labs/spacesvm/parser"
@@ -48,10 +48,18 @@ func (m *MoveTx) Execute(c *TransactionContext) error {
 	if err != nil {
 		return err
 	}
+	oldOwner := i.Owner
 	i.Owner = m.To
+	i.Owners = nil
+	i.Threshold = 0
+
+	// Permissions granted by the previous owner do not carry over
+	if err := database.ClearPrefix(c.Database, c.Database, SpacePermissionKey(i.RawSpace, nil)); err != nil {
+		return err
+	}
 
 	// Update space
-	if err := MoveSpaceInfo(c.Database, c.Sender, []byte(m.Space), i); err != nil {
+	if err := MoveSpaceInfo(c.Database, oldOwner, []byte(m.Space), i); err != nil {
 		return err
 	}
 	return nil
@@ -68,19 +76,15 @@ func (m *MoveTx) Copy() UnsignedTransaction {
 }
 
 func (m *MoveTx) TypedData() *tdata.TypedData {
-	return tdata.CreateTypedData(
-		m.Magic, Move,
+	return m.typedData(
+		Move,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdTo, Type: tdAddress},
-			{Name: tdPrice, Type: tdUint64},
-			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   m.Space,
-			tdTo:      m.To.Hex(),
-			tdPrice:   strconv.FormatUint(m.Price, 10),
-			tdBlockID: m.BlockID.String(),
+			tdSpace: m.Space,
+			tdTo:    m.To.Hex(),
 		},
 	)
 }
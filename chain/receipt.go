@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Receipt records how an accepted transaction was included and what it paid.
+type Receipt struct {
+	TxID    ids.ID `serialize:"true" json:"txId"`
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+	Height  uint64 `serialize:"true" json:"height"`
+	// Index is the position of the transaction in the block.
+	Index uint64 `serialize:"true" json:"index"`
+
+	// Price is the effective price paid per unit (see [EffectivePrice]).
+	Price    uint64 `serialize:"true" json:"price"`
+	FeeUnits uint64 `serialize:"true" json:"feeUnits"`
+	// Fee is the total amount deducted from the sender ([FeeUnits] * [Price]),
+	// which is split between [Burned] and [Reward].
+	Fee uint64 `serialize:"true" json:"fee"`
+	// Burned is the part of [Fee] that was not paid out to anyone. When
+	// [Genesis.FeeMarketEnabled], it is the burned part of the block price
+	// ([FeeUnits] * [burnedPrice]) unless no lottery reward was distributed,
+	// in which case all of [Fee] is burned.
+	Burned uint64 `serialize:"true" json:"burned"`
+
+	// RewardRecipient and Reward (the part of [Fee] paid out) are only
+	// populated if the transaction distributed a lottery reward (see
+	// [ApplyReward]).
+	RewardRecipient common.Address `serialize:"true" json:"rewardRecipient"`
+	Reward          uint64         `serialize:"true" json:"reward"`
+}
+
+func newReceipt(g *Genesis, blk *StatelessBlock, i int, tx *Transaction) *Receipt {
+	feeUnits := tx.FeeUnits(g)
+	price := EffectivePrice(tx.UnsignedTransaction, blk.Price)
+	r := &Receipt{
+		TxID:     tx.ID(),
+		BlockID:  blk.ID(),
+		Height:   blk.Hght,
+		Index:    uint64(i),
+		Price:    price,
+		FeeUnits: feeUnits,
+		Fee:      feeUnits * price,
+	}
+	if reward, ok := blk.Winners[tx.ID()]; ok {
+		r.RewardRecipient = common.HexToAddress(reward.To)
+		r.Reward = reward.Units
+	}
+	if r.Reward < r.Fee {
+		r.Burned = r.Fee - r.Reward
+	}
+	return r
+}
+
+func GetReceipt(db database.KeyValueReader, txID ids.ID) (*Receipt, bool, error) {
+	// [receiptPrefix] + [delimiter] + [txID]
+	v, err := db.Get(PrefixReceiptKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	r := new(Receipt)
+	if _, err := Unmarshal(v, r); err != nil {
+		return nil, false, err
+	}
+	return r, true, nil
+}
+
+func PutReceipt(db database.KeyValueWriter, r *Receipt) error {
+	b, err := Marshal(r)
+	if err != nil {
+		return err
+	}
+	return db.Put(PrefixReceiptKey(r.TxID), b)
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &RevokeTx{}
+
+// RevokeTx removes any permission previously granted to [To] in [Space].
+type RevokeTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// To is the address whose permission is revoked.
+	To common.Address `serialize:"true" json:"to"`
+}
+
+func (r *RevokeTx) Execute(t *TransactionContext) error {
+	if err := parser.CheckContents(r.Space); err != nil {
+		return err
+	}
+
+	// Verify space is owned by sender
+	i, err := verifySpace(r.Space, t)
+	if err != nil {
+		return err
+	}
+	_, has, err := GetPermission(t.Database, i.RawSpace, r.To)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return ErrPermissionMissing
+	}
+	return DeletePermission(t.Database, i.RawSpace, r.To)
+}
+
+func (r *RevokeTx) Copy() UnsignedTransaction {
+	to := make([]byte, common.AddressLength)
+	copy(to, r.To[:])
+	return &RevokeTx{
+		BaseTx: r.BaseTx.Copy(),
+		Space:  r.Space,
+		To:     common.BytesToAddress(to),
+	}
+}
+
+func (r *RevokeTx) TypedData() *tdata.TypedData {
+	return r.typedData(
+		Revoke,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdTo, Type: tdAddress},
+		},
+		tdata.TypedDataMessage{
+			tdSpace: r.Space,
+			tdTo:    r.To.Hex(),
+		},
+	)
+}
+
+func (r *RevokeTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Revoke,
+		Space: r.Space,
+		To:    r.To.Hex(),
+	}
+}
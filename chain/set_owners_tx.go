@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &SetOwnersTx{}
+
+// SetOwnersTx places [Space] under the control of an M-of-N owner set. Once
+// set, [MoveTx], [LifelineTx], [SetTx], [DeleteTx] and further [SetOwnersTx]
+// against the space must be signed by at least [Threshold] of [Owners].
+type SetOwnersTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// Owners is the new owner set. If empty, control of the space returns to
+	// [SpaceInfo.Owner] alone.
+	Owners []common.Address `serialize:"true" json:"owners"`
+
+	// Threshold is the number of [Owners] that must sign a transaction.
+	Threshold uint64 `serialize:"true" json:"threshold"`
+}
+
+func (s *SetOwnersTx) Execute(t *TransactionContext) error {
+	if err := parser.CheckContents(s.Space); err != nil {
+		return err
+	}
+	if err := verifyOwners(s.Owners, s.Threshold); err != nil {
+		return err
+	}
+
+	// Verify space is controlled by signers
+	i, err := verifySpace(s.Space, t)
+	if err != nil {
+		return err
+	}
+	if len(s.Owners) == 0 && len(i.Owners) == 0 {
+		return ErrNonActionable
+	}
+	i.Owners = s.Owners
+	i.Threshold = s.Threshold
+	return PutSpaceInfo(t.Database, []byte(s.Space), i, i.Expiry)
+}
+
+func verifyOwners(owners []common.Address, threshold uint64) error {
+	if len(owners) == 0 {
+		if threshold != 0 {
+			return ErrInvalidOwners
+		}
+		return nil
+	}
+	if len(owners) > MaxOwners {
+		return ErrInvalidOwners
+	}
+	if threshold == 0 || threshold > uint64(len(owners)) {
+		return ErrInvalidOwners
+	}
+	seen := make(map[common.Address]struct{}, len(owners))
+	for _, owner := range owners {
+		if bytes.Equal(owner[:], zeroAddress[:]) {
+			return ErrInvalidOwners
+		}
+		if _, ok := seen[owner]; ok {
+			return ErrInvalidOwners
+		}
+		seen[owner] = struct{}{}
+	}
+	return nil
+}
+
+func (s *SetOwnersTx) Copy() UnsignedTransaction {
+	var owners []common.Address
+	if len(s.Owners) > 0 {
+		owners = make([]common.Address, len(s.Owners))
+		copy(owners, s.Owners)
+	}
+	return &SetOwnersTx{
+		BaseTx:    s.BaseTx.Copy(),
+		Space:     s.Space,
+		Owners:    owners,
+		Threshold: s.Threshold,
+	}
+}
+
+func (s *SetOwnersTx) TypedData() *tdata.TypedData {
+	owners := make([]interface{}, len(s.Owners))
+	for i, owner := range s.Owners {
+		owners[i] = owner.Hex()
+	}
+	return s.typedData(
+		SetOwners,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdOwners, Type: tdAddress + "[]"},
+			{Name: tdThreshold, Type: tdUint64},
+		},
+		tdata.TypedDataMessage{
+			tdSpace:     s.Space,
+			tdOwners:    owners,
+			tdThreshold: strconv.FormatUint(s.Threshold, 10),
+		},
+	)
+}
+
+func (s *SetOwnersTx) Activity() *Activity {
+	return &Activity{
+		Typ:   SetOwners,
+		Space: s.Space,
+		Units: s.Threshold,
+	}
+}
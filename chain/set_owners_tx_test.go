@@ -0,0 +1,256 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSetOwnersTx(t *testing.T) {
+	t.Parallel()
+
+	addrs := make([]common.Address, 4)
+	for i := range addrs {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[i] = crypto.PubkeyToAddress(priv.PublicKey)
+	}
+	owner, a, b, c := addrs[0], addrs[1], addrs[2], addrs[3]
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tt := []struct {
+		utx       UnsignedTransaction
+		sender    common.Address
+		cosigners []common.Address
+		err       error
+	}{
+		{ // successful claim
+			utx:    &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			sender: owner,
+		},
+		{ // threshold too high
+			utx:    &SetOwnersTx{BaseTx: &BaseTx{}, Space: "foo", Owners: []common.Address{a, b}, Threshold: 3},
+			sender: owner,
+			err:    ErrInvalidOwners,
+		},
+		{ // duplicate owner
+			utx:    &SetOwnersTx{BaseTx: &BaseTx{}, Space: "foo", Owners: []common.Address{a, a}, Threshold: 1},
+			sender: owner,
+			err:    ErrInvalidOwners,
+		},
+		{ // reset when not multi-sig
+			utx:    &SetOwnersTx{BaseTx: &BaseTx{}, Space: "foo"},
+			sender: owner,
+			err:    ErrNonActionable,
+		},
+		{ // set by non-owner
+			utx:    &SetOwnersTx{BaseTx: &BaseTx{}, Space: "foo", Owners: []common.Address{a, b, c}, Threshold: 2},
+			sender: a,
+			err:    ErrUnauthorized,
+		},
+		{ // set 2-of-3
+			utx:    &SetOwnersTx{BaseTx: &BaseTx{}, Space: "foo", Owners: []common.Address{a, b, c}, Threshold: 2},
+			sender: owner,
+		},
+		{ // single owner can no longer write
+			utx:    &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("1")},
+			sender: owner,
+			err:    ErrUnauthorized,
+		},
+		{ // single signature is not enough
+			utx:    &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("1")},
+			sender: a,
+			err:    ErrUnauthorized,
+		},
+		{ // non-owner cosigner does not count
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("1")},
+			sender:    a,
+			cosigners: []common.Address{owner},
+			err:       ErrUnauthorized,
+		},
+		{ // 2-of-3 write
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("1")},
+			sender:    a,
+			cosigners: []common.Address{c},
+		},
+		{ // 2-of-3 write with non-owner fee payer
+			utx:       &DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar"},
+			sender:    owner,
+			cosigners: []common.Address{b, c},
+		},
+		{ // lifeline by non-owner
+			utx:    &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1},
+			sender: owner,
+			err:    ErrUnauthorized,
+		},
+		{ // lifeline by owners
+			utx:       &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1},
+			sender:    a,
+			cosigners: []common.Address{b},
+		},
+		{ // move by single owner
+			utx:    &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: owner},
+			sender: a,
+			err:    ErrUnauthorized,
+		},
+		{ // move by owners
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: owner},
+			sender:    a,
+			cosigners: []common.Address{b},
+		},
+		{ // new owner controls space alone
+			utx:    &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("2")},
+			sender: owner,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: 1,
+			TxID:      ids.GenerateTestID(),
+			Sender:    tv.sender,
+			Cosigners: tv.cosigners,
+		}
+		err := tv.utx.Execute(tc)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+
+	info, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Owner != owner || len(info.Owners) != 0 || info.Threshold != 0 {
+		t.Fatalf("unexpected space info %+v", info)
+	}
+}
+
+func TestTransactionCosigners(t *testing.T) {
+	t.Parallel()
+
+	privs := make([]*ecdsa.PrivateKey, 3)
+	for i := range privs {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		privs[i] = priv
+	}
+
+	g := DefaultGenesis()
+	utx := &SetOwnersTx{
+		BaseTx:    &BaseTx{BlockID: ids.GenerateTestID(), Magic: g.Magic, Price: g.MinPrice},
+		Space:     "foo",
+		Owners:    []common.Address{common.HexToAddress("0x1")},
+		Threshold: 1,
+	}
+	dh, err := DigestHash(utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := make([][]byte, len(privs))
+	for i, priv := range privs {
+		sigs[i], err = Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx := NewMultiSigTx(utx, sigs[0], sigs[1:])
+	if err := tx.Init(g); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Sender() != crypto.PubkeyToAddress(privs[0].PublicKey) {
+		t.Fatalf("unexpected sender %s", tx.Sender())
+	}
+	expected := []common.Address{
+		crypto.PubkeyToAddress(privs[1].PublicKey),
+		crypto.PubkeyToAddress(privs[2].PublicKey),
+	}
+	if !reflect.DeepEqual(tx.Cosigners(), expected) {
+		t.Fatalf("unexpected cosigners %v", tx.Cosigners())
+	}
+	if fu := tx.FeeUnits(g); fu != utx.FeeUnits(g)+2*g.BaseTxUnits {
+		t.Fatalf("unexpected fee units %d", fu)
+	}
+
+	// Signatures survive serialization
+	rtx := new(Transaction)
+	if _, err := Unmarshal(tx.Bytes(), rtx); err != nil {
+		t.Fatal(err)
+	}
+	if err := rtx.Init(g); err != nil {
+		t.Fatal(err)
+	}
+	if rtx.ID() != tx.ID() || !reflect.DeepEqual(rtx.Cosigners(), expected) {
+		t.Fatalf("unexpected parsed tx %+v", rtx)
+	}
+
+	// Duplicate signers are rejected
+	dup := NewMultiSigTx(utx, sigs[0], [][]byte{sigs[1], sigs[1]})
+	if err := dup.Init(g); !errors.Is(err, ErrDuplicateSignature) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateSignature, err)
+	}
+	self := NewMultiSigTx(utx, sigs[0], [][]byte{sigs[0]})
+	if err := self.Init(g); !errors.Is(err, ErrDuplicateSignature) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateSignature, err)
+	}
+
+	// Re-encoding the cosignatures changes the ID but not the replay ID, so
+	// an executed tx can't be replayed that way
+	ctx := &Context{
+		RecentBlockIDs:  ids.Set{utx.BlockID: struct{}{}},
+		RecentReplayIDs: ids.Set{tx.ReplayID(): struct{}{}},
+	}
+	for _, sigs := range [][][]byte{{sigs[2], sigs[1]}, {sigs[1]}, nil} {
+		replay := NewMultiSigTx(utx, tx.Signature, sigs)
+		if err := replay.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		if replay.ID() == tx.ID() || replay.ReplayID() != tx.ReplayID() {
+			t.Fatalf("unexpected replay IDs %s %s", replay.ID(), replay.ReplayID())
+		}
+		db := memdb.New()
+		if err := replay.Execute(g, db, DummyBlock(1, replay), ctx); !errors.Is(err, ErrDuplicateTx) {
+			t.Fatalf("expected %v, got %v", ErrDuplicateTx, err)
+		}
+		db.Close()
+	}
+}
+
+func TestSetOwnersTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	utx := &SetOwnersTx{
+		BaseTx:    &BaseTx{BlockID: ids.GenerateTestID(), Magic: 5, Price: 10},
+		Space:     "foo",
+		Owners:    []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2")},
+		Threshold: 2,
+	}
+	if _, err := DigestHash(utx); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseTypedData(utx.TypedData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(utx, parsed) {
+		t.Fatalf("parsed tx expected %+v, got %+v", utx, parsed)
+	}
+}
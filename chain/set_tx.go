@@ -5,11 +5,15 @@ package chain
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
 	"github.com/ava-labs/spacesvm/parser"
 	"github.com/ava-labs/spacesvm/tdata"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 const (
@@ -34,9 +38,19 @@ type SetTx struct {
 	// Value is written as the key-value pair to the storage. If a previous value
 	// exists, it is overwritten.
 	Value []byte `serialize:"true" json:"value"`
+
+	// TTL (if not 0) is the number of seconds after which the value is
+	// removed. Values with a shorter TTL are cheaper to write.
+	TTL uint64 `serialize:"true" json:"ttl"`
 }
 
 func (s *SetTx) Execute(t *TransactionContext) error {
+	return s.execute(t, t.TxID)
+}
+
+// execute writes [Value] to the space and records that it will be linked
+// under [valueID] once the containing block is accepted.
+func (s *SetTx) execute(t *TransactionContext, valueID ids.ID) error {
 	g := t.Genesis
 	if err := parser.CheckContents(s.Space); err != nil {
 		return err
@@ -49,10 +63,12 @@ func (s *SetTx) Execute(t *TransactionContext) error {
 		return ErrValueEmpty
 	case uint64(len(s.Value)) > g.MaxValueSize:
 		return ErrValueTooBig
+	case s.TTL > math.MaxUint64-t.BlockTime:
+		return ErrInvalidExpiry
 	}
 
-	// Verify space is owned by sender
-	i, err := verifySpace(s.Space, t)
+	// Verify sender is the space owner or allowed to write
+	i, err := verifySpaceAccess(s.Space, s.Key, true, false, t)
 	if err != nil {
 		return err
 	}
@@ -69,9 +85,14 @@ func (s *SetTx) Execute(t *TransactionContext) error {
 	// Update value
 	valueSize := uint64(len(s.Value))
 	nvmeta := &ValueMeta{
-		Size:    valueSize,
-		TxID:    t.TxID,
-		Updated: t.BlockTime,
+		Size:      valueSize,
+		TxID:      t.TxID,
+		ValueID:   valueID,
+		ValueHash: crypto.Keccak256Hash(s.Value),
+		Updated:   t.BlockTime,
+	}
+	if s.TTL > 0 {
+		nvmeta.Expiry = t.BlockTime + s.TTL
 	}
 	v, exists, err := GetValueMeta(t.Database, []byte(s.Space), []byte(s.Key))
 	if err != nil {
@@ -81,24 +102,38 @@ func (s *SetTx) Execute(t *TransactionContext) error {
 	if exists {
 		i.Units -= valueUnits(g, v.Size) / g.ValueExpiryDiscount
 		nvmeta.Created = v.Created
+		if v.Expiry > 0 {
+			if err := DeleteKeyExpiry(t.Database, i.RawSpace, []byte(s.Key), v.Expiry); err != nil {
+				return err
+			}
+		}
 	} else {
 		nvmeta.Created = t.BlockTime
 	}
-	i.Units += valueUnits(g, valueSize) / g.ValueExpiryDiscount
+	units := valueUnits(g, valueSize) / g.ValueExpiryDiscount
+	i.Units += units
 	if err := PutSpaceKey(t.Database, []byte(s.Space), []byte(s.Key), nvmeta); err != nil {
 		return err
 	}
+	if nvmeta.Expiry > 0 {
+		if err := PutKeyExpiry(
+			t.Database, []byte(s.Space), i.RawSpace, []byte(s.Key), nvmeta.Expiry, units,
+		); err != nil {
+			return err
+		}
+	}
 	return updateSpace(s.Space, t, timeRemaining, i)
 }
 
 func (s *SetTx) FeeUnits(g *Genesis) uint64 {
 	// We don't subtract by 1 here because we want to charge extra for any
 	// value-based interaction (even if it is small or a delete).
-	return s.BaseTx.FeeUnits(g) + valueUnits(g, uint64(len(s.Value)))
+	return s.BaseTx.FeeUnits(g) + ttlValueUnits(g, uint64(len(s.Value)), s.TTL)
 }
 
 func (s *SetTx) LoadUnits(g *Genesis) uint64 {
-	return s.FeeUnits(g)
+	// Values are just as expensive to process regardless of their TTL
+	return s.BaseTx.FeeUnits(g) + valueUnits(g, uint64(len(s.Value)))
 }
 
 func (s *SetTx) Copy() UnsignedTransaction {
@@ -109,27 +144,28 @@ func (s *SetTx) Copy() UnsignedTransaction {
 		Space:  s.Space,
 		Key:    s.Key,
 		Value:  value,
+		TTL:    s.TTL,
 	}
 }
 
+// TypedData only includes [TTL] if set so that the typed data of values
+// without one is unchanged.
 func (s *SetTx) TypedData() *tdata.TypedData {
-	return tdata.CreateTypedData(
-		s.Magic, Set,
-		[]tdata.Type{
-			{Name: tdSpace, Type: tdString},
-			{Name: tdKey, Type: tdString},
-			{Name: tdValue, Type: tdBytes},
-			{Name: tdPrice, Type: tdUint64},
-			{Name: tdBlockID, Type: tdString},
-		},
-		tdata.TypedDataMessage{
-			tdSpace:   s.Space,
-			tdKey:     s.Key,
-			tdValue:   hexutil.Encode(s.Value),
-			tdPrice:   strconv.FormatUint(s.Price, 10),
-			tdBlockID: s.BlockID.String(),
-		},
-	)
+	types := []tdata.Type{
+		{Name: tdSpace, Type: tdString},
+		{Name: tdKey, Type: tdString},
+		{Name: tdValue, Type: tdBytes},
+	}
+	message := tdata.TypedDataMessage{
+		tdSpace: s.Space,
+		tdKey:   s.Key,
+		tdValue: hexutil.Encode(s.Value),
+	}
+	if s.TTL > 0 {
+		types = append(types, tdata.Type{Name: tdTTL, Type: tdUint64})
+		message[tdTTL] = strconv.FormatUint(s.TTL, 10)
+	}
+	return s.typedData(Set, types, message)
 }
 
 func (s *SetTx) Activity() *Activity {
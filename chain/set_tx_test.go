@@ -6,6 +6,7 @@ package chain
 import (
 	"bytes"
 	"errors"
+	"math"
 	"strings"
 	"testing"
 
@@ -228,7 +229,7 @@ func TestSetTx(t *testing.T) {
 	for i, tv := range tt {
 		if i > 0 {
 			// Expire old spaces between txs
-			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
+			if err := ExpireNext(DefaultGenesis(), db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
 				t.Fatalf("#%d: ExpireNext errored %v", i, err)
 			}
 		}
@@ -306,3 +307,185 @@ func TestSetTx(t *testing.T) {
 		}
 	}
 }
+
+func TestSetTxTTL(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	value := bytes.Repeat([]byte{1}, 20*int(g.ValueUnitSize))
+	for i, tv := range []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+	}{
+		{utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}, blockTime: 1},
+		{utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "keep", Value: value}, blockTime: 1},
+		{utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "short", Value: value, TTL: 10}, blockTime: 1},
+		// Overwriting replaces the previous expiry
+		{utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "long", Value: value, TTL: 5}, blockTime: 1},
+		{utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "long", Value: value, TTL: 100}, blockTime: 2},
+	} {
+		if err := tv.utx.Execute(&TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: tv.blockTime,
+			TxID:      ids.GenerateTestID(),
+			Sender:    sender,
+		}); err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+	}
+
+	vmeta, exists, err := GetValueMeta(db, []byte("foo"), []byte("short"))
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	if vmeta.Expiry != 11 {
+		t.Fatalf("unexpected expiry %d", vmeta.Expiry)
+	}
+	if vmeta.Expired(11) || !vmeta.Expired(12) {
+		t.Fatal("unexpected expired")
+	}
+	before, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys are removed once their expiry has passed
+	if err := ExpireNext(DefaultGenesis(), db, 2, 12, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, tv := range []struct {
+		key    string
+		exists bool
+	}{
+		{"keep", true},
+		{"short", false},
+		{"long", true},
+	} {
+		has, err := HasSpaceKey(db, []byte("foo"), []byte(tv.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if has != tv.exists {
+			t.Fatalf("%s: expected exists %v, got %v", tv.key, tv.exists, has)
+		}
+	}
+	after, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := before.Units - valueUnits(g, uint64(len(value)))/g.ValueExpiryDiscount; after.Units != expected {
+		t.Fatalf("expected units %d, got %d", expected, after.Units)
+	}
+	if after.Expiry <= before.Expiry {
+		t.Fatalf("expected space expiry to increase from %d, got %d", before.Expiry, after.Expiry)
+	}
+
+	if err := ExpireNext(DefaultGenesis(), db, 12, 103, true); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := HasSpaceKey(db, []byte("foo"), []byte("long")); has || err != nil {
+		t.Fatalf("unexpected has %v, err %v", has, err)
+	}
+}
+
+func TestSetTxTTLOverflow(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	blockTime := uint64(10)
+	for i, tv := range []struct {
+		utx UnsignedTransaction
+		err error
+	}{
+		{utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}},
+		{
+			utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("a"), TTL: math.MaxUint64 - blockTime + 1},
+			err: ErrInvalidExpiry,
+		},
+		{utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("a"), TTL: math.MaxUint64 - blockTime}},
+	} {
+		err := tv.utx.Execute(&TransactionContext{
+			Genesis:   g,
+			Database:  db,
+			BlockTime: blockTime,
+			TxID:      ids.GenerateTestID(),
+			Sender:    sender,
+		})
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+	vmeta, exists, err := GetValueMeta(db, []byte("foo"), []byte("bar"))
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	if vmeta.Expiry != math.MaxUint64 {
+		t.Fatalf("unexpected expiry %d", vmeta.Expiry)
+	}
+}
+
+func TestSetTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	for _, ttl := range []uint64{0, 10} {
+		utx := &SetTx{
+			BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Magic: 1, Price: 2},
+			Space:  "foo",
+			Key:    "bar",
+			Value:  []byte("baz"),
+			TTL:    ttl,
+		}
+		td := utx.TypedData()
+		// Values without a TTL keep the typed data they had before TTLs were
+		// added so existing signers keep working
+		if _, ok := td.Message[tdTTL]; ok != (ttl > 0) {
+			t.Fatalf("unexpected ttl in typed data (ttl=%d): %v", ttl, td.Message)
+		}
+		parsed, err := ParseTypedData(td)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.(*SetTx).TTL != ttl {
+			t.Fatalf("ttl expected %d, got %d", ttl, parsed.(*SetTx).TTL)
+		}
+	}
+}
+
+func TestSetTxTTLFeeUnits(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	value := bytes.Repeat([]byte{1}, 100*int(g.ValueUnitSize))
+	full := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: value}
+	long := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: value, TTL: g.FullValueTTL * 2}
+	half := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: value, TTL: g.FullValueTTL / 2}
+	hour := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: value, TTL: 60 * 60}
+	if full.FeeUnits(g) != long.FeeUnits(g) {
+		t.Fatalf("expected long TTL to cost %d, got %d", full.FeeUnits(g), long.FeeUnits(g))
+	}
+	if !(full.FeeUnits(g) > half.FeeUnits(g) && half.FeeUnits(g) > hour.FeeUnits(g)) {
+		t.Fatalf("unexpected fee units full=%d half=%d hour=%d", full.FeeUnits(g), half.FeeUnits(g), hour.FeeUnits(g))
+	}
+	if hour.LoadUnits(g) != full.LoadUnits(g) {
+		t.Fatalf("expected load units %d, got %d", full.LoadUnits(g), hour.LoadUnits(g))
+	}
+}
@@ -16,4 +16,15 @@ type SpaceInfo struct {
 	Units   uint64         `serialize:"true" json:"units"` // decays faster the more units you have
 
 	RawSpace ids.ShortID `serialize:"true" json:"rawSpace"`
+
+	// Owners (if not empty) replaces [Owner] as the set of addresses that
+	// control the space. At least [Threshold] of them must sign any
+	// transaction that modifies the space. [Owner] is still used to index the
+	// space and to receive rewards.
+	Owners    []common.Address `serialize:"true" json:"owners,omitempty"`
+	Threshold uint64           `serialize:"true" json:"threshold,omitempty"`
 }
+
+// MaxOwners is the maximum size of a space owner set and, by extension, the
+// maximum number of co-signatures a transaction may carry.
+const MaxOwners = 16
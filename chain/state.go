@@ -0,0 +1,409 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/smt"
+)
+
+// The state root authenticates all state modified by blocks: space info,
+// values, permissions, balances, and the indexes and queues kept alongside
+// them (owned spaces, space and key expiry, and auctions). This is what
+// allows state sync to verify the ranges it fetches from peers.
+//
+// The state trie contains a leaf for every space info and every other entry
+// at keccak256([db key]). Info leaves commit to keccak256([space info] +
+// [space root]), where the space root is the root of a separate trie over
+// the values in the space (at keccak256([key]) => keccak256([value meta]))
+// and its permissions (at keccak256([db key]) => keccak256([permission])).
+// All other leaves commit to keccak256([value]).
+//
+// Keeping values in a trie per space means that an expired space is removed
+// from the state by removing a single leaf, and its values, permissions (and
+// trie) can be pruned asynchronously like before.
+
+var stateTrieKey = []byte{stateTriePrefix, parser.ByteDelimiter}
+
+// StateDB tracks all modifications to authenticated state so that they can
+// be committed to the state trie with [CommitState].
+type StateDB struct {
+	database.Database
+
+	dirty map[string]struct{}
+}
+
+func NewStateDB(db database.Database) *StateDB {
+	return &StateDB{Database: db, dirty: map[string]struct{}{}}
+}
+
+func (s *StateDB) track(key []byte) {
+	if len(key) < 2 || key[1] != parser.ByteDelimiter {
+		return
+	}
+	if authenticated(key[0]) {
+		s.dirty[string(key)] = struct{}{}
+	}
+}
+
+// authenticated returns true if keys with [prefix] are committed to by the
+// state root.
+func authenticated(prefix byte) bool {
+	switch prefix {
+	case infoPrefix, keyPrefix, permPrefix, balancePrefix, ownedPrefix,
+		expiryPrefix, keyTTLPrefix, auctionPrefix, bidPrefix, settlePrefix,
+		permExpiryPrefix:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *StateDB) Put(key []byte, value []byte) error {
+	s.track(key)
+	return s.Database.Put(key, value)
+}
+
+func (s *StateDB) Delete(key []byte) error {
+	s.track(key)
+	return s.Database.Delete(key)
+}
+
+func (s *StateDB) NewBatch() database.Batch {
+	return &stateBatch{Batch: s.Database.NewBatch(), s: s}
+}
+
+type stateBatch struct {
+	database.Batch
+
+	s *StateDB
+}
+
+func (b *stateBatch) Put(key []byte, value []byte) error {
+	b.s.track(key)
+	return b.Batch.Put(key, value)
+}
+
+func (b *stateBatch) Delete(key []byte) error {
+	b.s.track(key)
+	return b.Batch.Delete(key)
+}
+
+// StateChange is a modification of an authenticated key (see [Diff]).
+type StateChange struct {
+	// Type describes the kind of state stored at [Key] (e.g. "balance")
+	Type string        `serialize:"true" json:"type"`
+	Key  hexutil.Bytes `serialize:"true" json:"key"`
+	// Before is nil if [Key] didn't exist and After is nil if it was deleted
+	Before *hexutil.Bytes `serialize:"true" json:"before"`
+	After  *hexutil.Bytes `serialize:"true" json:"after"`
+}
+
+var stateTypes = map[byte]string{
+	infoPrefix:    "info",
+	keyPrefix:     "value",
+	expiryPrefix:  "expiry",
+	balancePrefix: "balance",
+	ownedPrefix:   "owned",
+	permPrefix:    "permission",
+	keyTTLPrefix:  "keyTTL",
+	auctionPrefix: "auction",
+	bidPrefix:     "bid",
+	settlePrefix:  "settle",
+
+	permExpiryPrefix: "permissionExpiry",
+}
+
+// Diff returns the changes to authenticated state tracked by [s] (sorted by
+// key) compared to [base], the database [s] was created on top of.
+func Diff(s *StateDB, base database.KeyValueReader) ([]*StateChange, error) {
+	dirty := make([]string, 0, len(s.dirty))
+	for k := range s.dirty {
+		dirty = append(dirty, k)
+	}
+	sort.Strings(dirty)
+
+	changes := []*StateChange{}
+	for _, k := range dirty {
+		key := []byte(k)
+		before, err := getOptional(base, key)
+		if err != nil {
+			return nil, err
+		}
+		after, err := getOptional(s, key)
+		if err != nil {
+			return nil, err
+		}
+		if before == nil && after == nil || before != nil && after != nil && bytes.Equal(*before, *after) {
+			continue
+		}
+		changes = append(changes, &StateChange{
+			Type:   stateTypes[key[0]],
+			Key:    key,
+			Before: before,
+			After:  after,
+		})
+	}
+	return changes, nil
+}
+
+// getOptional returns nil if [key] doesn't exist in [db].
+func getOptional(db database.KeyValueReader, key []byte) (*hexutil.Bytes, error) {
+	v, err := db.Get(key)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := hexutil.Bytes(v)
+	return &b, nil
+}
+
+// CommitState applies all modifications tracked by [s] to the state trie and
+// returns the new state root.
+func CommitState(s *StateDB) (ids.ID, error) {
+	dirty := make([]string, 0, len(s.dirty))
+	for k := range s.dirty {
+		dirty = append(dirty, k)
+	}
+	sort.Strings(dirty)
+	s.dirty = map[string]struct{}{}
+
+	// Update space tries first because their roots are committed to by the
+	// info leaves
+	spaces := map[string]struct{}{}
+	updatedSpaces := map[ids.ShortID]struct{}{}
+	for _, k := range dirty {
+		key := []byte(k)
+		switch key[0] {
+		case infoPrefix:
+			spaces[string(key[2:])] = struct{}{}
+		case keyPrefix, permPrefix:
+			// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
+			// [permPrefix] + [delimiter] + [rawSpace] + [delimiter] + [address]
+			if len(key) < 2+shortIDLen+1 {
+				return ids.Empty, ErrInvalidKeyFormat
+			}
+			rspace, err := ids.ToShortID(key[2 : 2+shortIDLen])
+			if err != nil {
+				return ids.Empty, err
+			}
+			path := crypto.Keccak256Hash(key)
+			if key[0] == keyPrefix {
+				path = ValuePath(key[2+shortIDLen+1:])
+			}
+			if err := updateLeaf(s, smt.New(s, spaceTrieKey(rspace)), path, key); err != nil {
+				return ids.Empty, err
+			}
+			updatedSpaces[rspace] = struct{}{}
+		}
+	}
+	for rspace := range updatedSpaces {
+		space, err := s.Get(spaceNameKey(rspace))
+		if errors.Is(err, database.ErrNotFound) {
+			// Space was created in this block (and will be added when its info
+			// is) or no longer exists
+			continue
+		}
+		if err != nil {
+			return ids.Empty, err
+		}
+		spaces[string(space)] = struct{}{}
+	}
+
+	state := smt.New(s, stateTrieKey)
+	for _, k := range dirty {
+		key := []byte(k)
+		switch key[0] {
+		case infoPrefix, keyPrefix, permPrefix:
+		default:
+			// Equal to [BalancePath] for balances
+			if err := updateLeaf(s, state, crypto.Keccak256Hash(key), key); err != nil {
+				return ids.Empty, err
+			}
+		}
+	}
+	names := make([]string, 0, len(spaces))
+	for space := range spaces {
+		names = append(names, space)
+	}
+	sort.Strings(names)
+	for _, space := range names {
+		if err := updateInfoLeaf(s, state, []byte(space)); err != nil {
+			return ids.Empty, err
+		}
+	}
+	root, err := state.Root()
+	return ids.ID(root), err
+}
+
+// updateLeaf sets the leaf at [path] to the hash of the value stored at [key]
+// (or removes it if there is no value).
+func updateLeaf(db database.KeyValueReader, t *smt.Tree, path common.Hash, key []byte) error {
+	v, err := db.Get(key)
+	if errors.Is(err, database.ErrNotFound) {
+		return t.Remove(path)
+	}
+	if err != nil {
+		return err
+	}
+	return t.Update(path, crypto.Keccak256Hash(v))
+}
+
+func updateInfoLeaf(db database.KeyValueReaderWriter, t *smt.Tree, space []byte) error {
+	v, err := db.Get(SpaceInfoKey(space))
+	if errors.Is(err, database.ErrNotFound) {
+		return t.Remove(InfoPath(space))
+	}
+	if err != nil {
+		return err
+	}
+	i := new(SpaceInfo)
+	if _, err := Unmarshal(v, i); err != nil {
+		return err
+	}
+	if err := db.Put(spaceNameKey(i.RawSpace), space); err != nil {
+		return err
+	}
+	spaceRoot, err := GetSpaceRoot(db, i.RawSpace)
+	if err != nil {
+		return err
+	}
+	return t.Update(InfoPath(space), InfoValueHash(v, spaceRoot))
+}
+
+// GetStateRoot returns the root of the state trie.
+func GetStateRoot(db database.KeyValueReader) (ids.ID, error) {
+	root, err := smt.Root(db, stateTrieKey)
+	return ids.ID(root), err
+}
+
+// GetSpaceRoot returns the root of the trie over the values in [rspace].
+func GetSpaceRoot(db database.KeyValueReader, rspace ids.ShortID) (common.Hash, error) {
+	return smt.Root(db, spaceTrieKey(rspace))
+}
+
+// BalancePath is the path of the balance of [address] in the state trie.
+func BalancePath(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(PrefixBalanceKey(address))
+}
+
+// BalanceValueHash is the value hash committed to by the leaf of a balance.
+func BalanceValueHash(bal uint64) common.Hash {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, bal)
+	return crypto.Keccak256Hash(b)
+}
+
+// InfoPath is the path of the info of [space] in the state trie.
+func InfoPath(space []byte) common.Hash {
+	return crypto.Keccak256Hash(SpaceInfoKey(space))
+}
+
+// InfoValueHash is the value hash committed to by the leaf of a space info.
+func InfoValueHash(info []byte, spaceRoot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(info, spaceRoot[:])
+}
+
+// ValuePath is the path of [key] in the trie of its space.
+func ValuePath(key []byte) common.Hash {
+	return crypto.Keccak256Hash(key)
+}
+
+// ValueMetaHash is the value hash committed to by the leaf of a key.
+func ValueMetaHash(vmeta []byte) common.Hash {
+	return crypto.Keccak256Hash(vmeta)
+}
+
+// StateProof proves some state (or its absence) against the state root of
+// [BlockID].
+type StateProof struct {
+	BlockID ids.ID `json:"blockId"`
+	Root    ids.ID `json:"root"`
+
+	// State proves the leaf of the space info or balance in the state trie.
+	State *smt.Proof `json:"state"`
+
+	// Info is the space info committed to by [State] and [SpaceRoot] is the
+	// root of the trie over its values. They are empty if the space does not
+	// exist.
+	Info      []byte      `json:"info,omitempty"`
+	SpaceRoot common.Hash `json:"spaceRoot"`
+
+	// Value proves the leaf of a key in the trie of its space.
+	Value *smt.Proof `json:"value,omitempty"`
+}
+
+// ProveBalance returns a proof of the balance of [address].
+func ProveBalance(db database.KeyValueReader, address common.Address) (*StateProof, error) {
+	return proveState(db, BalancePath(address))
+}
+
+// ProveSpace returns a proof of the info of [space].
+func ProveSpace(db database.KeyValueReader, space []byte) (*StateProof, error) {
+	p, err := proveState(db, InfoPath(space))
+	if err != nil {
+		return nil, err
+	}
+	info, err := db.Get(SpaceInfoKey(space))
+	if errors.Is(err, database.ErrNotFound) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	i := new(SpaceInfo)
+	if _, err := Unmarshal(info, i); err != nil {
+		return nil, err
+	}
+	spaceRoot, err := GetSpaceRoot(db, i.RawSpace)
+	if err != nil {
+		return nil, err
+	}
+	p.Info = info
+	p.SpaceRoot = spaceRoot
+	return p, nil
+}
+
+// ProveValue returns a proof of the info of [space] and the value of [key] in
+// it.
+func ProveValue(db database.KeyValueReader, space []byte, key []byte) (*StateProof, error) {
+	p, err := ProveSpace(db, space)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Info) == 0 {
+		return p, nil
+	}
+	i := new(SpaceInfo)
+	if _, err := Unmarshal(p.Info, i); err != nil {
+		return nil, err
+	}
+	p.Value, err = smt.Prove(db, spaceTrieKey(i.RawSpace), ValuePath(key))
+	return p, err
+}
+
+func proveState(db database.KeyValueReader, path common.Hash) (*StateProof, error) {
+	root, err := GetStateRoot(db)
+	if err != nil {
+		return nil, err
+	}
+	p, err := smt.Prove(db, stateTrieKey, path)
+	if err != nil {
+		return nil, err
+	}
+	return &StateProof{Root: root, State: p}, nil
+}
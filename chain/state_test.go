@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCommitState(t *testing.T) {
+	t.Parallel()
+
+	spc, k := []byte("foo"), []byte("k")
+	addr := common.Address{0x1}
+	putInfo := func(s *StateDB) {
+		if err := PutSpaceInfo(s, spc, &SpaceInfo{RawSpace: ids.ShortID{0x1}, Expiry: 10}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	putKey := func(s *StateDB, txID ids.ID) {
+		if err := PutSpaceKey(s, spc, k, &ValueMeta{TxID: txID, Expiry: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setBalance := func(s *StateDB) {
+		if err := SetBalance(s, addr, 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(s *StateDB) ids.ID {
+		root, err := CommitState(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := GetStateRoot(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored != root {
+			t.Fatalf("stored root expected %s, got %s", root, stored)
+		}
+		return root
+	}
+
+	// Only balances
+	s := NewStateDB(memdb.New())
+	setBalance(s)
+	balanceRoot := commit(s)
+
+	// Apply all modifications at once
+	s = NewStateDB(memdb.New())
+	setBalance(s)
+	putInfo(s)
+	putKey(s, ids.ID{0x1})
+	root := commit(s)
+	if root == balanceRoot {
+		t.Fatal("space did not change root")
+	}
+
+	// Apply modifications across commits
+	s = NewStateDB(memdb.New())
+	putInfo(s)
+	setBalance(s)
+	emptySpaceRoot := commit(s)
+	putKey(s, ids.ID{0x2})
+	if r := commit(s); r == root || r == emptySpaceRoot {
+		t.Fatalf("unexpected root %s", r)
+	}
+	putKey(s, ids.ID{0x1})
+	if r := commit(s); r != root {
+		t.Fatalf("root expected %s, got %s", root, r)
+	}
+	if err := DeleteSpaceKey(s, spc, k); err != nil {
+		t.Fatal(err)
+	}
+	if r := commit(s); r != emptySpaceRoot {
+		t.Fatalf("root expected %s, got %s", emptySpaceRoot, r)
+	}
+
+	// Expiring the space removes it (and its indexes) from the state
+	putKey(s, ids.ID{0x1})
+	if err := ExpireNext(DefaultGenesis(), s, 0, 20, true); err != nil {
+		t.Fatal(err)
+	}
+	if r := commit(s); r != balanceRoot {
+		t.Fatalf("root expected %s, got %s", balanceRoot, r)
+	}
+}
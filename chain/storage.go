@@ -14,6 +14,7 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ethereum/go-ethereum/common"
 	smath "github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -24,9 +25,9 @@ import (
 
 // 0x0/ (block hashes)
 // 0x1/ (tx hashes)
-//   -> [tx hash]=>nil
+//   -> [tx hash]=>[block hash]
 // 0x2/ (tx values)
-//   -> [tx hash]=>value
+//   -> [value id]=>value
 // 0x3/ (singleton space info)
 //   -> [space]:[space info/raw space]
 // 0x4/ (space keys)
@@ -40,6 +41,41 @@ import (
 //   -> [owner]=> balance
 // 0x8/ (owned spaces)
 //   -> [owner]/[space]=> nil
+// 0x9/ (space permissions)
+//   -> [raw space]
+//     -> [grantee]=> permission
+// 0xa/ (key expiry queue)
+//   -> [timestamp]/[raw space]/[key]=> [units]/[space]
+// 0xb/ (space auctions)
+//   -> [space]=> auction
+// 0xc/ (space auction bids)
+//   -> [space]
+//     -> [bidder]=> bid
+// 0xd/ (space auction settlement queue)
+//   -> [timestamp]/[space]=> nil
+// 0xe/ (block heights)
+//   -> [height]=> [block hash]
+// 0xf/ (tx receipts)
+//   -> [tx hash]=> receipt
+// 0x10/ (activity by time)
+//   -> [timestamp][height][index]=> activity
+// 0x11/ (activity by address)
+//   -> [address]/[timestamp][height][index]=> activity
+// 0x12/ (activity by space)
+//   -> [space]/[timestamp][height][index]=> activity
+// 0x13/ (state trie nodes, see [CommitState])
+//   -> [depth][path]=> node
+// 0x14/ (space trie nodes)
+//   -> [raw space]
+//     -> [depth][path]=> node
+// 0x15/ (space names)
+//   -> [raw space]=> space
+// 0x16/ (state summary journal, see [JournalDB])
+//   -> [db key]=> value at last state summary
+// 0x17/ (pending txs, see [SetPendingTxs])
+//   -> [tx hash]=> tx
+// 0x18/ (permission expiry queue)
+//   -> [timestamp]/[raw space]/[grantee]=> [space]
 
 const (
 	blockPrefix   = 0x0
@@ -51,6 +87,25 @@ const (
 	pruningPrefix = 0x6
 	balancePrefix = 0x7
 	ownedPrefix   = 0x8
+	permPrefix    = 0x9
+	keyTTLPrefix  = 0xa
+	auctionPrefix = 0xb
+	bidPrefix     = 0xc
+	settlePrefix  = 0xd
+	heightPrefix  = 0xe
+	receiptPrefix = 0xf
+
+	activityPrefix      = 0x10
+	addrActivityPrefix  = 0x11
+	spaceActivityPrefix = 0x12
+
+	stateTriePrefix = 0x13
+	spaceTriePrefix = 0x14
+	spaceNamePrefix = 0x15
+	journalPrefix   = 0x16
+	pendingTxPrefix = 0x17
+
+	permExpiryPrefix = 0x18
 
 	shortIDLen = 20
 
@@ -67,13 +122,22 @@ var (
 	linkedTxCache = &cache.LRU{Size: linkedTxLRUSize}
 
 	CompactRanges = []*CompactRange{
-		// Don't compact block/tx/txValue ranges because no overwriting/deletion
+		// Don't compact block/tx/txValue/height/receipt/activity ranges
+		// because no overwriting/deletion
 		{[]byte{infoPrefix, parser.ByteDelimiter}, []byte{keyPrefix, parser.ByteDelimiter}},
 		{[]byte{keyPrefix, parser.ByteDelimiter}, []byte{expiryPrefix, parser.ByteDelimiter}},
 		// Group expiry and pruning together
 		{[]byte{expiryPrefix, parser.ByteDelimiter}, []byte{balancePrefix, parser.ByteDelimiter}},
 		{[]byte{balancePrefix, parser.ByteDelimiter}, []byte{ownedPrefix, parser.ByteDelimiter}},
-		{[]byte{ownedPrefix, parser.ByteDelimiter}, []byte{ownedPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{ownedPrefix, parser.ByteDelimiter}, []byte{permPrefix, parser.ByteDelimiter}},
+		{[]byte{permPrefix, parser.ByteDelimiter}, []byte{keyTTLPrefix, parser.ByteDelimiter}},
+		{[]byte{keyTTLPrefix, parser.ByteDelimiter}, []byte{auctionPrefix, parser.ByteDelimiter}},
+		{[]byte{auctionPrefix, parser.ByteDelimiter}, []byte{settlePrefix, parser.ByteDelimiter}},
+		{[]byte{settlePrefix, parser.ByteDelimiter}, []byte{settlePrefix + 1, parser.ByteDelimiter}},
+		{[]byte{stateTriePrefix, parser.ByteDelimiter}, []byte{spaceNamePrefix + 1, parser.ByteDelimiter}},
+		{[]byte{journalPrefix, parser.ByteDelimiter}, []byte{journalPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{pendingTxPrefix, parser.ByteDelimiter}, []byte{pendingTxPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{permExpiryPrefix, parser.ByteDelimiter}, []byte{permExpiryPrefix + 1, parser.ByteDelimiter}},
 	}
 )
 
@@ -95,6 +159,33 @@ func PrefixTxKey(txID ids.ID) (k []byte) {
 	return k
 }
 
+// [heightPrefix] + [delimiter] + [height]
+func PrefixHeightKey(height uint64) (k []byte) {
+	k = make([]byte, 2+8)
+	k[0] = heightPrefix
+	k[1] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(k[2:], height)
+	return k
+}
+
+// [receiptPrefix] + [delimiter] + [txID]
+func PrefixReceiptKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 2+len(txID))
+	k[0] = receiptPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], txID[:])
+	return k
+}
+
+// [pendingTxPrefix] + [delimiter] + [txID]
+func PrefixPendingTxKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 2+len(txID))
+	k[0] = pendingTxPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], txID[:])
+	return k
+}
+
 // [txValuePrefix] + [delimiter] + [txID]
 func PrefixTxValueKey(txID ids.ID) (k []byte) {
 	k = make([]byte, 2+len(txID))
@@ -139,6 +230,18 @@ func SpaceValueKey(rspace ids.ShortID, key []byte) (k []byte) {
 	return k
 }
 
+// Assumes [address] does not contain delimiter
+// [permPrefix] + [delimiter] + [rawSpace] + [delimiter] + [address]
+func SpacePermissionKey(rspace ids.ShortID, address []byte) (k []byte) {
+	k = make([]byte, 2+shortIDLen+1+len(address))
+	k[0] = permPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], rspace[:])
+	k[2+shortIDLen] = parser.ByteDelimiter
+	copy(k[2+shortIDLen+1:], address)
+	return k
+}
+
 // [expiry/pruningPrefix] + [delimiter] + [timestamp] + [delimiter]
 func RangeTimeKey(p byte, t uint64) (k []byte) {
 	k = make([]byte, 2+8+1)
@@ -179,8 +282,102 @@ func PrefixOwnedKey(address common.Address, space []byte) (k []byte) {
 	return
 }
 
+// Assumes [key] does not contain delimiter
+// [keyTTLPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace] + [delimiter] + [key]
+func PrefixKeyExpiryKey(expiry uint64, rspace ids.ShortID, key []byte) (k []byte) {
+	k = make([]byte, specificTimeKeyLen+1+len(key))
+	copy(k, specificTimeKey(keyTTLPrefix, expiry, rspace))
+	k[specificTimeKeyLen] = parser.ByteDelimiter
+	copy(k[specificTimeKeyLen+1:], key)
+	return k
+}
+
+// [permExpiryPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace] + [delimiter] + [grantee]
+func PrefixPermissionExpiryKey(expiry uint64, rspace ids.ShortID, grantee common.Address) (k []byte) {
+	k = make([]byte, specificTimeKeyLen+1+common.AddressLength)
+	copy(k, specificTimeKey(permExpiryPrefix, expiry, rspace))
+	k[specificTimeKeyLen] = parser.ByteDelimiter
+	copy(k[specificTimeKeyLen+1:], grantee[:])
+	return k
+}
+
+// [auctionPrefix] + [delimiter] + [space]
+func PrefixAuctionKey(space []byte) (k []byte) {
+	k = make([]byte, 2+len(space))
+	k[0] = auctionPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], space)
+	return k
+}
+
+// Assumes [space] does not contain delimiter
+// [bidPrefix] + [delimiter] + [space] + [delimiter] + [bidder]
+func PrefixBidKey(space []byte, bidder []byte) (k []byte) {
+	k = make([]byte, 2+len(space)+1+len(bidder))
+	k[0] = bidPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], space)
+	k[2+len(space)] = parser.ByteDelimiter
+	copy(k[2+len(space)+1:], bidder)
+	return k
+}
+
+// [settlePrefix] + [delimiter] + [timestamp] + [delimiter] + [space]
+func PrefixSettleKey(end uint64, space []byte) (k []byte) {
+	k = make([]byte, 2+8+1+len(space))
+	copy(k, RangeTimeKey(settlePrefix, end))
+	copy(k[2+8+1:], space)
+	return k
+}
+
+// [activityPrefix] + [delimiter]
+// [addrActivityPrefix] + [delimiter] + [address] + [delimiter]
+// [spaceActivityPrefix] + [delimiter] + [space] + [delimiter]
+func activityBaseKey(p byte, scope []byte) (k []byte) {
+	if scope == nil {
+		return []byte{p, parser.ByteDelimiter}
+	}
+	k = make([]byte, 2+len(scope)+1)
+	k[0] = p
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], scope)
+	k[len(k)-1] = parser.ByteDelimiter
+	return k
+}
+
+const activitySuffixLen = 8 + 8 + 8
+
+// [base] + [timestamp] + [height] + [index]
+func activityKey(base []byte, t uint64, height uint64, index uint64) (k []byte) {
+	k = make([]byte, len(base)+activitySuffixLen)
+	copy(k, base)
+	binary.BigEndian.PutUint64(k[len(base):], t)
+	binary.BigEndian.PutUint64(k[len(base)+8:], height)
+	binary.BigEndian.PutUint64(k[len(base)+16:], index)
+	return k
+}
+
 const specificTimeKeyLen = 2 + 8 + 1 + shortIDLen
 
+// [spaceTriePrefix] + [delimiter] + [rawSpace] + [delimiter]
+func spaceTrieKey(rspace ids.ShortID) (k []byte) {
+	k = make([]byte, 2+shortIDLen+1)
+	k[0] = spaceTriePrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], rspace[:])
+	k[2+shortIDLen] = parser.ByteDelimiter
+	return k
+}
+
+// [spaceNamePrefix] + [delimiter] + [rawSpace]
+func spaceNameKey(rspace ids.ShortID) (k []byte) {
+	k = make([]byte, 2+shortIDLen)
+	k[0] = spaceNamePrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], rspace[:])
+	return k
+}
+
 // [expiry/pruningPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
 func specificTimeKey(p byte, t uint64, rspace ids.ShortID) (k []byte) {
 	k = make([]byte, specificTimeKeyLen)
@@ -237,8 +434,8 @@ func GetValueMeta(db database.KeyValueReader, space []byte, key []byte) (*ValueM
 	if err != nil {
 		return nil, false, err
 	}
-	vmeta := new(ValueMeta)
-	if _, err := Unmarshal(rvmeta, vmeta); err != nil {
+	vmeta, err := UnmarshalValueMeta(rvmeta)
+	if err != nil {
 		return nil, false, err
 	}
 	return vmeta, true, nil
@@ -262,13 +459,13 @@ func GetValue(db database.KeyValueReader, space []byte, key []byte) ([]byte, boo
 	if err != nil {
 		return nil, false, err
 	}
-	vmeta := new(ValueMeta)
-	if _, err := Unmarshal(rvmeta, vmeta); err != nil {
+	vmeta, err := UnmarshalValueMeta(rvmeta)
+	if err != nil {
 		return nil, false, err
 	}
 
 	// Lookup stored value
-	v, err := getLinkedValue(db, vmeta.TxID[:])
+	v, err := getLinkedValue(db, vmeta.ValueID[:])
 	if err != nil {
 		return nil, false, err
 	}
@@ -294,8 +491,8 @@ func GetAllValueMetas(db database.Database, rspace ids.ShortID) (kvs []*KeyValue
 			break
 		}
 
-		vmeta := new(ValueMeta)
-		if _, err := Unmarshal(cursor.Value(), vmeta); err != nil {
+		vmeta, err := UnmarshalValueMeta(cursor.Value())
+		if err != nil {
 			return nil, err
 		}
 
@@ -308,82 +505,210 @@ func GetAllValueMetas(db database.Database, rspace ids.ShortID) (kvs []*KeyValue
 	return kvs, cursor.Error()
 }
 
-// linkValues extracts all *SetTx.Value in [block] and replaces them with the
-// corresponding txID where they were found. The extracted value is then
-// written to disk.
+// GetValueMetas returns up to [limit] keys in [rspace] that start with
+// [prefix], in lexicographical order and starting after [startAfter] if
+// provided. [next] is the last key returned if there are more results. Keys
+// that have expired at [now] but have not been removed yet are skipped.
+func GetValueMetas(
+	db database.Database,
+	rspace ids.ShortID,
+	prefix []byte,
+	startAfter []byte,
+	now uint64,
+	limit int,
+) (kvs []*KeyValueMeta, next string, err error) {
+	kvs = []*KeyValueMeta{}
+	if limit <= 0 {
+		return kvs, "", nil
+	}
+	baseKey := SpaceValueKey(rspace, prefix)
+	start := baseKey
+	if len(startAfter) > 0 {
+		// Appending a byte yields the smallest key after [startAfter]
+		after := append(SpaceValueKey(rspace, startAfter), 0x0)
+		if bytes.Compare(after, start) > 0 {
+			start = after
+		}
+	}
+	cursor := db.NewIteratorWithStartAndPrefix(start, baseKey)
+	defer cursor.Release()
+	for cursor.Next() {
+		if len(kvs) == limit {
+			next = kvs[len(kvs)-1].Key
+			break
+		}
+		vmeta, err := UnmarshalValueMeta(cursor.Value())
+		if err != nil {
+			return nil, "", err
+		}
+		if vmeta.Expired(now) {
+			continue
+		}
+		kvs = append(kvs, &KeyValueMeta{
+			// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
+			Key:       string(cursor.Key()[2+shortIDLen+1:]),
+			ValueMeta: vmeta,
+		})
+	}
+	return kvs, next, cursor.Error()
+}
+
+// linkableValues returns pointers to all values in [utx] that are stored
+// outside of the block and the IDs they are linked under for [txID].
+func linkableValues(utx UnsignedTransaction, txID ids.ID) (valueIDs []ids.ID, values []*[]byte) {
+	switch t := utx.(type) {
+	case *SetTx:
+		return []ids.ID{txID}, []*[]byte{&t.Value}
+	case *ConditionalSetTx:
+		return []ids.ID{txID}, []*[]byte{&t.Value}
+	case *BatchTx:
+		for i, op := range t.Ops {
+			if op.Typ != Set {
+				continue
+			}
+			valueIDs = append(valueIDs, BatchValueID(txID, i))
+			values = append(values, &op.Value)
+		}
+	}
+	return valueIDs, values
+}
+
+// linkValues extracts all values in [block] (see [linkableValues]) and
+// replaces them with the ID they are linked under. The extracted value is
+// then written to disk.
 func linkValues(db database.KeyValueWriter, block *StatelessBlock) ([]*Transaction, error) {
 	g := block.vm.Genesis()
 	ogTxs := make([]*Transaction, len(block.Txs))
 	for i, tx := range block.Txs {
-		switch t := tx.UnsignedTransaction.(type) {
-		case *SetTx:
-			if len(t.Value) == 0 {
-				ogTxs[i] = tx
-				continue
-			}
+		valueIDs, values := linkableValues(tx.UnsignedTransaction, tx.ID())
+		if len(values) == 0 {
+			ogTxs[i] = tx
+			continue
+		}
 
-			// Copy transaction for later
-			cptx := tx.Copy()
-			if err := cptx.Init(g); err != nil {
-				return nil, err
-			}
-			ogTxs[i] = cptx
+		// Copy transaction for later
+		cptx := tx.Copy()
+		if err := cptx.Init(g); err != nil {
+			return nil, err
+		}
+		ogTxs[i] = cptx
 
-			if err := db.Put(PrefixTxValueKey(tx.ID()), t.Value); err != nil {
+		for j, v := range values {
+			if len(*v) == 0 {
+				continue
+			}
+			valueID := valueIDs[j]
+			if err := db.Put(PrefixTxValueKey(valueID), *v); err != nil {
 				return nil, err
 			}
-			t.Value = tx.id[:] // used to properly parse on restore
-		default:
-			ogTxs[i] = tx
+			*v = valueID[:] // used to properly parse on restore
 		}
 	}
 	return ogTxs, nil
 }
 
-// restoreValues restores the unlinked values associated with all *SetTx.Value
-// in [block].
+// restoreValues restores the unlinked values (see [linkableValues]) in
+// [block].
 func restoreValues(db database.KeyValueReader, block *StatefulBlock) error {
 	for _, tx := range block.Txs {
-		if t, ok := tx.UnsignedTransaction.(*SetTx); ok {
-			if len(t.Value) == 0 {
-				continue
-			}
-			txID, err := ids.ToID(t.Value)
+		_, values := linkableValues(tx.UnsignedTransaction, ids.Empty)
+		for _, v := range values {
+			b, err := restoreValue(db, *v)
 			if err != nil {
 				return err
 			}
-			b, err := db.Get(PrefixTxValueKey(txID))
-			if err != nil {
-				return err
-			}
-			t.Value = b
+			*v = b
 		}
 	}
 	return nil
 }
 
+func restoreValue(db database.KeyValueReader, link []byte) ([]byte, error) {
+	if len(link) == 0 {
+		return link, nil
+	}
+	valueID, err := ids.ToID(link)
+	if err != nil {
+		return nil, err
+	}
+	return db.Get(PrefixTxValueKey(valueID))
+}
+
 func SetLastAccepted(db database.KeyValueWriter, block *StatelessBlock) error {
 	bid := block.ID()
 	if err := db.Put(lastAccepted, bid[:]); err != nil {
 		return err
 	}
-	ogTxs, err := linkValues(db, block)
-	if err != nil {
-		return err
-	}
-	sbytes, err := Marshal(block.StatefulBlock)
+	ogTxs, err := putBlock(db, block)
 	if err != nil {
 		return err
 	}
-	if err := db.Put(PrefixBlockKey(bid), sbytes); err != nil {
-		return err
+	g := block.vm.Genesis()
+	for i, tx := range ogTxs {
+		if err := PutReceipt(db, newReceipt(g, block, i, tx)); err != nil {
+			return err
+		}
 	}
 	// Restore the original transactions in the block in case it is cached for
 	// later use.
 	block.Txs = ogTxs
+
+	// Index the activity in the same batch so that it is never missing for an
+	// accepted block
+	for i, a := range BlockActivity(block) {
+		if err := PutActivity(db, a, block.Hght, uint64(i)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// PutSyncedBlock stores [block], which was fetched during state sync instead
+// of being executed, and marks it as the last accepted block if [last]. No
+// receipts are stored for it.
+func PutSyncedBlock(db database.KeyValueWriter, block *StatelessBlock, last bool) error {
+	if last {
+		bid := block.ID()
+		if err := db.Put(lastAccepted, bid[:]); err != nil {
+			return err
+		}
+	}
+	ogTxs, err := putBlock(db, block)
+	if err != nil {
+		return err
+	}
+	block.Txs = ogTxs
+	return nil
+}
+
+// putBlock stores [block] with its values linked (see [linkValues]) and
+// indexes it by height. It returns the original transactions in [block].
+func putBlock(db database.KeyValueWriter, block *StatelessBlock) ([]*Transaction, error) {
+	bid := block.ID()
+	ogTxs, err := linkValues(db, block)
+	if err != nil {
+		return nil, err
+	}
+	sbytes, err := Marshal(block.StatefulBlock)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Put(PrefixBlockKey(bid), sbytes); err != nil {
+		return nil, err
+	}
+	if err := db.Put(PrefixHeightKey(block.Hght), bid[:]); err != nil {
+		return nil, err
+	}
+	// Overwrite the entries written by [SetTransaction] during execution so
+	// that accepted transactions can be looked up by ID.
+	for _, tx := range ogTxs {
+		if err := db.Put(PrefixTxKey(tx.ID()), bid[:]); err != nil {
+			return nil, err
+		}
+	}
+	return ogTxs, nil
+}
+
 func HasLastAccepted(db database.Database) (bool, error) {
 	return db.Has(lastAccepted)
 }
@@ -414,10 +739,37 @@ func GetBlock(db database.KeyValueReader, bid ids.ID) (*StatefulBlock, error) {
 	return blk, nil
 }
 
+// GetBlockIDAtHeight returns the ID of the accepted block at [height].
+func GetBlockIDAtHeight(db database.KeyValueReader, height uint64) (ids.ID, bool, error) {
+	v, err := db.Get(PrefixHeightKey(height))
+	if errors.Is(err, database.ErrNotFound) {
+		return ids.ID{}, false, nil
+	}
+	if err != nil {
+		return ids.ID{}, false, err
+	}
+	bid, err := ids.ToID(v)
+	return bid, err == nil, err
+}
+
+// ExpiredSpace is a space removed by [ExpireNext].
+type ExpiredSpace struct {
+	Space string         `serialize:"true" json:"space"`
+	Owner common.Address `serialize:"true" json:"owner"`
+}
+
 // ExpireNext queries "expiryPrefix" key space to find expiring keys,
 // deletes their spaceInfos, and schedules its key pruning with its raw space.
-func ExpireNext(db database.Database, rparent int64, rcurrent int64, bootstrapped bool) (err error) {
+// If auctions are enabled, the auction of each expired space is opened.
+func ExpireNext(g *Genesis, db database.Database, rparent int64, rcurrent int64, bootstrapped bool) (err error) {
+	_, err = expireNext(g, db, rparent, rcurrent, bootstrapped)
+	return err
+}
+
+// expireNext is [ExpireNext] but also returns the spaces that expired.
+func expireNext(g *Genesis, db database.Database, rparent int64, rcurrent int64, bootstrapped bool) ([]*ExpiredSpace, error) {
 	parent, current := uint64(rparent), uint64(rcurrent)
+	expiredSpaces := []*ExpiredSpace{}
 	startKey := RangeTimeKey(expiryPrefix, parent)
 	endKey := RangeTimeKey(expiryPrefix, current)
 	cursor := db.NewIteratorWithStart(startKey)
@@ -432,7 +784,7 @@ func ExpireNext(db database.Database, rparent int64, rcurrent int64, bootstrappe
 			break
 		}
 		if err := db.Delete(cursor.Key()); err != nil {
-			return err
+			return nil, err
 		}
 
 		// [owner] + [space]
@@ -442,34 +794,174 @@ func ExpireNext(db database.Database, rparent int64, rcurrent int64, bootstrappe
 
 		// Update owned prefix
 		if err := db.Delete(PrefixOwnedKey(owner, space)); err != nil {
-			return err
+			return nil, err
 		}
 
 		// [infoPrefix] + [delimiter] + [space]
 		k := SpaceInfoKey(space)
 		if err := db.Delete(k); err != nil {
-			return err
+			return nil, err
 		}
 
 		expired, rspc, err := extractSpecificTimeKey(curKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if bootstrapped {
 			// [pruningPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
 			k = PrefixPruningKey(expired, rspc)
 			if err := db.Put(k, nil); err != nil {
-				return err
+				return nil, err
 			}
 		} else {
 			// If we are not yet bootstrapped, we should delete the dangling value keys
 			// immediately instead of clearing async.
-			if err := database.ClearPrefix(db, db, SpaceValueKey(rspc, nil)); err != nil {
-				return err
+			if err := clearSpace(db, rspc); err != nil {
+				return nil, err
+			}
+		}
+		// The space can be bid on as soon as it expires
+		if g.AuctionWindow > 0 && len(space) != hexAddressLen {
+			if _, err := openAuction(g, db, space, expired); err != nil {
+				return nil, err
 			}
 		}
+		expiredSpaces = append(expiredSpaces, &ExpiredSpace{Space: string(space), Owner: owner})
 		log.Debug("space expired", "space", string(space))
 	}
+	if err := cursor.Error(); err != nil {
+		return nil, err
+	}
+	if err := expireKeysNext(db, parent, current); err != nil {
+		return nil, err
+	}
+	if err := expirePermissionsNext(db, parent, current); err != nil {
+		return nil, err
+	}
+	return expiredSpaces, nil
+}
+
+// expireKeysNext queries "keyTTLPrefix" key space to find expiring keys,
+// deletes them, and releases the units they were using in their space.
+func expireKeysNext(db database.Database, parent uint64, current uint64) error {
+	startKey := RangeTimeKey(keyTTLPrefix, parent)
+	endKey := RangeTimeKey(keyTTLPrefix, current)
+	cursor := db.NewIteratorWithStart(startKey)
+	defer cursor.Release()
+	for cursor.Next() {
+		// [keyTTLPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace] + [delimiter] + [key]
+		curKey := cursor.Key()
+		if bytes.Compare(startKey, curKey) < -1 { // startKey < curKey; continue search
+			continue
+		}
+		if bytes.Compare(curKey, endKey) > 0 { // curKey > endKey; end search
+			break
+		}
+		if err := db.Delete(curKey); err != nil {
+			return err
+		}
+		if len(curKey) <= specificTimeKeyLen+1 {
+			return ErrInvalidKeyFormat
+		}
+		expiry, rspc, err := extractSpecificTimeKey(curKey[:specificTimeKeyLen])
+		if err != nil {
+			return err
+		}
+		key := curKey[specificTimeKeyLen+1:]
+
+		// [units] + [space]
+		ttlValue := cursor.Value()
+		if len(ttlValue) < 8 {
+			return ErrInvalidKeyFormat
+		}
+		units := binary.BigEndian.Uint64(ttlValue[:8])
+		space := ttlValue[8:]
+
+		// The space may have expired (and been re-claimed) or the key may have
+		// been overwritten or deleted since the entry was queued.
+		i, exists, err := GetSpaceInfo(db, space)
+		if err != nil {
+			return err
+		}
+		if !exists || i.RawSpace != rspc {
+			continue
+		}
+		vmeta, exists, err := GetValueMeta(db, space, key)
+		if err != nil {
+			return err
+		}
+		if !exists || vmeta.Expiry != expiry {
+			continue
+		}
+		if err := db.Delete(SpaceValueKey(rspc, key)); err != nil {
+			return err
+		}
+		if units > 0 && units < i.Units {
+			timeRemaining := (i.Expiry - i.Updated) * i.Units
+			i.Units -= units
+			lastExpiry := i.Expiry
+			i.Updated = current
+			i.Expiry = current + timeRemaining/i.Units
+			if err := PutSpaceInfo(db, space, i, lastExpiry); err != nil {
+				return err
+			}
+		}
+		log.Debug("key expired", "space", string(space), "key", string(key))
+	}
+	return cursor.Error()
+}
+
+// expirePermissionsNext queries "permExpiryPrefix" key space to find expiring
+// permissions and deletes them.
+func expirePermissionsNext(db database.Database, parent uint64, current uint64) error {
+	startKey := RangeTimeKey(permExpiryPrefix, parent)
+	endKey := RangeTimeKey(permExpiryPrefix, current)
+	cursor := db.NewIteratorWithStart(startKey)
+	defer cursor.Release()
+	for cursor.Next() {
+		// [permExpiryPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace] + [delimiter] + [grantee]
+		curKey := cursor.Key()
+		if !bytes.HasPrefix(curKey, []byte{permExpiryPrefix, parser.ByteDelimiter}) {
+			break
+		}
+		if bytes.Compare(curKey, endKey) > 0 { // curKey > endKey; end search
+			break
+		}
+		if err := db.Delete(curKey); err != nil {
+			return err
+		}
+		if len(curKey) != specificTimeKeyLen+1+common.AddressLength {
+			return ErrInvalidKeyFormat
+		}
+		expiry, rspc, err := extractSpecificTimeKey(curKey[:specificTimeKeyLen])
+		if err != nil {
+			return err
+		}
+		grantee := common.BytesToAddress(curKey[specificTimeKeyLen+1:])
+		space := cursor.Value()
+
+		// The space may have expired (its permissions are then removed when it
+		// is pruned) or the permission may have been revoked or granted again
+		// since the entry was queued.
+		i, exists, err := GetSpaceInfo(db, space)
+		if err != nil {
+			return err
+		}
+		if !exists || i.RawSpace != rspc {
+			continue
+		}
+		p, exists, err := GetPermission(db, rspc, grantee)
+		if err != nil {
+			return err
+		}
+		if !exists || p.Expiry != expiry {
+			continue
+		}
+		if err := DeletePermission(db, rspc, grantee); err != nil {
+			return err
+		}
+		log.Debug("permission expired", "space", string(space), "grantee", grantee)
+	}
 	return cursor.Error()
 }
 
@@ -496,8 +988,7 @@ func PruneNext(db database.Database, limit int) (removals int, err error) {
 		if err := db.Delete(curKey); err != nil {
 			return removals, err
 		}
-		// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
-		if err := database.ClearPrefix(db, db, SpaceValueKey(rspc, nil)); err != nil {
+		if err := clearSpace(db, rspc); err != nil {
 			return removals, err
 		}
 		log.Debug("rspace pruned", "rspace", rspc.Hex())
@@ -506,6 +997,37 @@ func PruneNext(db database.Database, limit int) (removals int, err error) {
 	return removals, cursor.Error()
 }
 
+// PruningBacklog returns the number of expired spaces waiting to be pruned
+// (counting at most [limit] of them).
+func PruningBacklog(db database.Iteratee, limit int) (int, error) {
+	cursor := db.NewIteratorWithPrefix([]byte{pruningPrefix, parser.ByteDelimiter})
+	defer cursor.Release()
+	backlog := 0
+	for backlog < limit && cursor.Next() {
+		backlog++
+	}
+	return backlog, cursor.Error()
+}
+
+// clearSpace removes all values, permissions, and trie nodes stored under
+// [rspace].
+func clearSpace(db database.Database, rspace ids.ShortID) error {
+	// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
+	if err := database.ClearPrefix(db, db, SpaceValueKey(rspace, nil)); err != nil {
+		return err
+	}
+	// [permPrefix] + [delimiter] + [rawSpace] + [delimiter] + [address]
+	if err := database.ClearPrefix(db, db, SpacePermissionKey(rspace, nil)); err != nil {
+		return err
+	}
+	// The space is no longer referenced by the state trie once its info is
+	// removed, so its trie can be dropped at any time.
+	if err := database.ClearPrefix(db, db, spaceTrieKey(rspace)); err != nil {
+		return err
+	}
+	return db.Delete(spaceNameKey(rspace))
+}
+
 // DB
 func HasSpace(db database.KeyValueReader, space []byte) (bool, error) {
 	// [infoPrefix] + [delimiter] + [space]
@@ -599,8 +1121,75 @@ type ValueMeta struct {
 	Size uint64 `serialize:"true" json:"size"`
 	TxID ids.ID `serialize:"true" json:"txId"`
 
+	// ValueID is the key the value is linked under on accept. It is equal to
+	// [TxID] for values written by a [SetTx].
+	ValueID ids.ID `serialize:"true" json:"valueId"`
+
+	// ValueHash is the keccak256 hash of the value. It binds the value to the
+	// state root, which only commits to the [ValueMeta].
+	ValueHash common.Hash `serialize:"true" json:"valueHash"`
+
 	Created uint64 `serialize:"true" json:"created"`
 	Updated uint64 `serialize:"true" json:"updated"`
+
+	// Expiry is the time after which the value is treated as missing (if
+	// not 0).
+	Expiry uint64 `serialize:"true" json:"expiry,omitempty"`
+}
+
+// legacyValueMeta is a [ValueMeta] encoded with [codecVersion].
+type legacyValueMeta struct {
+	Size    uint64 `serialize:"true"`
+	TxID    ids.ID `serialize:"true"`
+	Created uint64 `serialize:"true"`
+	Updated uint64 `serialize:"true"`
+}
+
+// MarshalValueMeta encodes [vmeta] with [valueMetaVersion].
+func MarshalValueMeta(vmeta *ValueMeta) ([]byte, error) {
+	return codecManager.Marshal(valueMetaVersion, vmeta)
+}
+
+// UnmarshalValueMeta decodes a [ValueMeta] encoded with any codec version.
+// Those encoded with [codecVersion] have no [ValueHash] or [Expiry] and their
+// value is linked under their [TxID].
+func UnmarshalValueMeta(b []byte) (*ValueMeta, error) {
+	if len(b) >= wrappers.ShortLen && binary.BigEndian.Uint16(b) == codecVersion {
+		legacy := new(legacyValueMeta)
+		if _, err := Unmarshal(b, legacy); err != nil {
+			return nil, err
+		}
+		return &ValueMeta{
+			Size:    legacy.Size,
+			TxID:    legacy.TxID,
+			ValueID: legacy.TxID,
+			Created: legacy.Created,
+			Updated: legacy.Updated,
+		}, nil
+	}
+	vmeta := new(ValueMeta)
+	if _, err := Unmarshal(b, vmeta); err != nil {
+		return nil, err
+	}
+	return vmeta, nil
+}
+
+// Expired returns true if the value has a TTL that has elapsed at [now].
+func (v *ValueMeta) Expired(now uint64) bool {
+	return v.Expiry != 0 && v.Expiry < now
+}
+
+// PutKeyExpiry queues [key] to be removed from [space] once [expiry] has
+// passed, releasing [units] from the space.
+func PutKeyExpiry(db database.KeyValueWriter, space []byte, rspace ids.ShortID, key []byte, expiry uint64, units uint64) error {
+	v := make([]byte, 8+len(space))
+	binary.BigEndian.PutUint64(v, units)
+	copy(v[8:], space)
+	return db.Put(PrefixKeyExpiryKey(expiry, rspace, key), v)
+}
+
+func DeleteKeyExpiry(db database.KeyValueDeleter, rspace ids.ShortID, key []byte, expiry uint64) error {
+	return db.Delete(PrefixKeyExpiryKey(expiry, rspace, key))
 }
 
 func PutSpaceKey(db database.KeyValueReaderWriter, space []byte, key []byte, vmeta *ValueMeta) error {
@@ -613,7 +1202,7 @@ func PutSpaceKey(db database.KeyValueReaderWriter, space []byte, key []byte, vme
 	}
 	// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
 	k := SpaceValueKey(spaceInfo.RawSpace, key)
-	rvmeta, err := Marshal(vmeta)
+	rvmeta, err := MarshalValueMeta(vmeta)
 	if err != nil {
 		return err
 	}
@@ -632,6 +1221,90 @@ func DeleteSpaceKey(db database.Database, space []byte, key []byte) error {
 	return db.Delete(k)
 }
 
+type Permission struct {
+	Grantee common.Address `serialize:"true" json:"grantee"`
+
+	// Write allows [Grantee] to set values in the space.
+	Write bool `serialize:"true" json:"write"`
+	// Delete allows [Grantee] to delete values in the space.
+	Delete bool `serialize:"true" json:"delete"`
+
+	// Prefix restricts [Grantee] to keys starting with [Prefix] (if not empty).
+	Prefix string `serialize:"true" json:"prefix"`
+	// Expiry is the time after which the permission no longer applies (if not
+	// 0).
+	Expiry uint64 `serialize:"true" json:"expiry"`
+}
+
+func GetPermission(db database.KeyValueReader, rspace ids.ShortID, address common.Address) (*Permission, bool, error) {
+	// [permPrefix] + [delimiter] + [rawSpace] + [delimiter] + [address]
+	k := SpacePermissionKey(rspace, address[:])
+	v, err := db.Get(k)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	p := new(Permission)
+	if _, err := Unmarshal(v, p); err != nil {
+		return nil, false, err
+	}
+	return p, true, nil
+}
+
+func PutPermission(db database.KeyValueWriter, rspace ids.ShortID, p *Permission) error {
+	k := SpacePermissionKey(rspace, p.Grantee[:])
+	b, err := Marshal(p)
+	if err != nil {
+		return err
+	}
+	return db.Put(k, b)
+}
+
+func DeletePermission(db database.KeyValueDeleter, rspace ids.ShortID, address common.Address) error {
+	k := SpacePermissionKey(rspace, address[:])
+	return db.Delete(k)
+}
+
+// PutPermissionExpiry queues the permission of [grantee] in [space] to be
+// removed once [expiry] has passed.
+func PutPermissionExpiry(db database.KeyValueWriter, space []byte, rspace ids.ShortID, grantee common.Address, expiry uint64) error {
+	return db.Put(PrefixPermissionExpiryKey(expiry, rspace, grantee), space)
+}
+
+// countPermissions returns the number of permissions in [rspace], counting
+// at most [max] of them.
+func countPermissions(db database.Iteratee, rspace ids.ShortID, max uint64) (uint64, error) {
+	cursor := db.NewIteratorWithPrefix(SpacePermissionKey(rspace, nil))
+	defer cursor.Release()
+	var n uint64
+	for n < max && cursor.Next() {
+		n++
+	}
+	return n, cursor.Error()
+}
+
+func GetAllPermissions(db database.Database, rspace ids.ShortID) (perms []*Permission, err error) {
+	baseKey := SpacePermissionKey(rspace, nil)
+	cursor := db.NewIteratorWithStart(baseKey)
+	defer cursor.Release()
+	perms = []*Permission{}
+	for cursor.Next() {
+		curKey := cursor.Key()
+		if !bytes.HasPrefix(curKey, baseKey) { // curKey does not start with base key; end search
+			break
+		}
+
+		p := new(Permission)
+		if _, err := Unmarshal(cursor.Value(), p); err != nil {
+			return nil, err
+		}
+		perms = append(perms, p)
+	}
+	return perms, cursor.Error()
+}
+
 func SetTransaction(db database.KeyValueWriter, tx *Transaction) error {
 	k := PrefixTxKey(tx.ID())
 	return db.Put(k, nil)
@@ -642,6 +1315,62 @@ func HasTransaction(db database.KeyValueReader, txID ids.ID) (bool, error) {
 	return db.Has(k)
 }
 
+// GetTransactionBlockID returns the ID of the accepted block that included
+// [txID].
+func GetTransactionBlockID(db database.KeyValueReader, txID ids.ID) (ids.ID, bool, error) {
+	v, err := db.Get(PrefixTxKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return ids.ID{}, false, nil
+	}
+	if err != nil {
+		return ids.ID{}, false, err
+	}
+	if len(v) == 0 {
+		// Transaction was executed but its block has not been recorded
+		return ids.ID{}, false, nil
+	}
+	bid, err := ids.ToID(v)
+	return bid, err == nil, err
+}
+
+// SetPendingTxs replaces the pending txs persisted in [db] with [txs], which
+// must be initialized.
+func SetPendingTxs(db database.Database, txs []*Transaction) error {
+	batch := db.NewBatch()
+	cursor := db.NewIteratorWithPrefix([]byte{pendingTxPrefix, parser.ByteDelimiter})
+	defer cursor.Release()
+	for cursor.Next() {
+		if err := batch.Delete(common.CopyBytes(cursor.Key())); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Error(); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err := batch.Put(PrefixPendingTxKey(tx.ID()), tx.Bytes()); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// GetPendingTxs returns the pending txs persisted in [db]. They must be
+// initialized before use.
+func GetPendingTxs(db database.Iteratee) ([]*Transaction, error) {
+	cursor := db.NewIteratorWithPrefix([]byte{pendingTxPrefix, parser.ByteDelimiter})
+	defer cursor.Release()
+	txs := []*Transaction{}
+	for cursor.Next() {
+		tx := new(Transaction)
+		if _, err := Unmarshal(cursor.Value(), tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, cursor.Error()
+}
+
 func getLinkedValue(db database.KeyValueReader, b []byte) ([]byte, error) {
 	bh := string(b)
 	if v, ok := linkedTxCache.Get(bh); ok {
@@ -703,11 +1432,13 @@ func ModifyBalance(db database.KeyValueReaderWriter, address common.Address, add
 	return n, SetBalance(db, address, n)
 }
 
+// ApplyReward gives [reward] to the owner of the space selected by [txRoot]
+// and [txID] (unless it is [sender]).
 func ApplyReward(
-	db database.Database, blkID ids.ID, txID ids.ID, sender common.Address, reward uint64,
+	db database.Database, txRoot ids.ID, txID ids.ID, sender common.Address, reward uint64,
 ) (common.Address, bool, error) {
 	seed := [64]byte{}
-	copy(seed[:], blkID[:])
+	copy(seed[:], txRoot[:])
 	copy(seed[32:], txID[:])
 	iterator := crypto.Keccak256(seed[:])
 
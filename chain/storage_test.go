@@ -6,6 +6,7 @@ package chain
 import (
 	"bytes"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/database/memdb"
@@ -13,6 +14,7 @@ import (
 	"github.com/ava-labs/spacesvm/parser"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	gomock "github.com/golang/mock/gomock"
 )
 
 func TestSpaceValueKey(t *testing.T) {
@@ -142,6 +144,46 @@ func TestPutSpaceInfoAndKey(t *testing.T) {
 	}
 }
 
+func TestValueMetaVersions(t *testing.T) {
+	t.Parallel()
+
+	vmeta := &ValueMeta{
+		Size:      3,
+		TxID:      ids.GenerateTestID(),
+		ValueID:   ids.GenerateTestID(),
+		ValueHash: crypto.Keccak256Hash([]byte("foo")),
+		Created:   1,
+		Updated:   2,
+		Expiry:    3,
+	}
+	b, err := MarshalValueMeta(vmeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalValueMeta(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *decoded != *vmeta {
+		t.Fatalf("value meta expected %+v, got %+v", vmeta, decoded)
+	}
+
+	// Value metadata encoded before it was versioned is still decoded
+	legacy := &legacyValueMeta{Size: 3, TxID: ids.GenerateTestID(), Created: 1, Updated: 2}
+	b, err = Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = UnmarshalValueMeta(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &ValueMeta{Size: 3, TxID: legacy.TxID, ValueID: legacy.TxID, Created: 1, Updated: 2}
+	if *decoded != *expected {
+		t.Fatalf("value meta expected %+v, got %+v", expected, decoded)
+	}
+}
+
 func TestSpecificTimeKey(t *testing.T) {
 	rspc0 := ids.ShortID{'k'}
 	k := PrefixExpiryKey(100, rspc0)
@@ -337,7 +379,7 @@ func TestGetAllValueMetas(t *testing.T) {
 	for i, tv := range tt {
 		if i > 0 {
 			// Expire old spaces between txs
-			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
+			if err := ExpireNext(DefaultGenesis(), db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
 				t.Fatalf("#%d: ExpireNext errored %v", i, err)
 			}
 		}
@@ -391,3 +433,209 @@ func TestGetAllValueMetas(t *testing.T) {
 		}
 	}
 }
+
+func TestSetLastAcceptedIndexes(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := DefaultGenesis()
+	tx := &Transaction{
+		UnsignedTransaction: &SetTx{
+			BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Price: 10},
+			Space:  "foo",
+			Key:    "bar",
+			Value:  []byte("hello"),
+		},
+	}
+	dh, err := DigestHash(tx.UnsignedTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Signature, err = Sign(dh, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := gomock.NewController(t)
+	vm := NewMockVM(ctrl)
+	vm.EXPECT().Genesis().Return(g).AnyTimes()
+	blk := &StatelessBlock{
+		StatefulBlock: &StatefulBlock{Hght: 7, Txs: []*Transaction{tx}},
+		vm:            vm,
+	}
+	if err := blk.init(); err != nil {
+		t.Fatal(err)
+	}
+	winner := common.Address{0x1}
+	blk.Winners[tx.ID()] = &Activity{To: winner.Hex(), Units: 3}
+	if err := SetTransaction(db, tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists, err := GetTransactionBlockID(db, tx.ID()); exists || err != nil {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	if err := SetLastAccepted(db, blk); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, exists, err := GetBlockIDAtHeight(db, 7)
+	if err != nil || !exists || bid != blk.ID() {
+		t.Fatalf("unexpected block ID %s (exists %v, err %v), expected %s", bid, exists, err, blk.ID())
+	}
+	if _, exists, err := GetBlockIDAtHeight(db, 8); exists || err != nil {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	bid, exists, err = GetTransactionBlockID(db, tx.ID())
+	if err != nil || !exists || bid != blk.ID() {
+		t.Fatalf("unexpected block ID %s (exists %v, err %v), expected %s", bid, exists, err, blk.ID())
+	}
+
+	stBlk, err := GetBlock(db, bid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := stBlk.Txs[0].UnsignedTransaction.(*SetTx).Value; !bytes.Equal(v, []byte("hello")) {
+		t.Fatalf("unexpected restored value %q", v)
+	}
+
+	r, exists, err := GetReceipt(db, tx.ID())
+	if err != nil || !exists {
+		t.Fatalf("unexpected exists %v, err %v", exists, err)
+	}
+	expected := &Receipt{
+		TxID:            tx.ID(),
+		BlockID:         blk.ID(),
+		Height:          7,
+		Price:           10,
+		FeeUnits:        tx.FeeUnits(g),
+		Fee:             tx.FeeUnits(g) * 10,
+		Burned:          tx.FeeUnits(g)*10 - 3,
+		RewardRecipient: winner,
+		Reward:          3,
+	}
+	if *r != *expected {
+		t.Fatalf("receipt expected %+v, got %+v", expected, r)
+	}
+}
+
+func TestGetValueMetas(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+
+	spc, rspc := []byte("foo"), ids.ShortID{0x1}
+	if err := PutSpaceInfo(db, spc, &SpaceInfo{RawSpace: rspc}, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"a", "b", "ba", "bb", "bc", "c"} {
+		if err := PutSpaceKey(db, spc, []byte(k), &ValueMeta{Size: uint64(len(k))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Expired keys are skipped
+	if err := PutSpaceKey(db, spc, []byte("bd"), &ValueMeta{Size: 2, Expiry: 9}); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		prefix     string
+		startAfter string
+		limit      int
+		keys       []string
+		next       string
+	}{
+		{limit: 10, keys: []string{"a", "b", "ba", "bb", "bc", "c"}},
+		{limit: 2, keys: []string{"a", "b"}, next: "b"},
+		{startAfter: "b", limit: 2, keys: []string{"ba", "bb"}, next: "bb"},
+		{startAfter: "bb", limit: 2, keys: []string{"bc", "c"}},
+		{prefix: "b", limit: 10, keys: []string{"b", "ba", "bb", "bc"}},
+		{prefix: "b", startAfter: "a", limit: 1, keys: []string{"b"}, next: "b"},
+		{prefix: "b", startAfter: "bb", limit: 10, keys: []string{"bc"}},
+		{prefix: "b", startAfter: "c", limit: 10, keys: []string{}},
+		{prefix: "d", limit: 10, keys: []string{}},
+		{limit: 0, keys: []string{}},
+	}
+	for i, tv := range tt {
+		kvs, next, err := GetValueMetas(db, rspc, []byte(tv.prefix), []byte(tv.startAfter), 10, tv.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := make([]string, len(kvs))
+		for j, kv := range kvs {
+			keys[j] = kv.Key
+		}
+		if !reflect.DeepEqual(keys, tv.keys) {
+			t.Fatalf("#%d: keys expected %v, got %v", i, tv.keys, keys)
+		}
+		if next != tv.next {
+			t.Fatalf("#%d: next expected %q, got %q", i, tv.next, next)
+		}
+	}
+}
+
+func TestPendingTxs(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := DefaultGenesis()
+	txs := make([]*Transaction, 3)
+	for i := range txs {
+		tx := &Transaction{
+			UnsignedTransaction: &TransferTx{
+				BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Price: 10},
+				Units:  uint64(i + 1),
+			},
+		}
+		dh, err := DigestHash(tx.UnsignedTransaction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Signature, err = Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		txs[i] = tx
+	}
+
+	// Persisted txs replace the previous ones
+	for _, pending := range [][]*Transaction{txs, txs[1:], {}} {
+		if err := SetPendingTxs(db, pending); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := GetPendingTxs(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) != len(pending) {
+			t.Fatalf("pending txs expected %d, got %d", len(pending), len(loaded))
+		}
+		expected := map[ids.ID]bool{}
+		for _, tx := range pending {
+			expected[tx.ID()] = true
+		}
+		for _, tx := range loaded {
+			if err := tx.Init(g); err != nil {
+				t.Fatal(err)
+			}
+			if !expected[tx.ID()] {
+				t.Fatalf("unexpected pending tx %s", tx.ID())
+			}
+		}
+	}
+}
@@ -0,0 +1,491 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// State sync lets a new node start from the state at a recent block instead
+// of executing every block since genesis.
+//
+// Periodically, a node records its last accepted block as its state summary
+// and keeps serving the state at the last [stateSummaryRetention] summaries,
+// so that a node syncing to a summary isn't interrupted as soon as the next
+// one is recorded. To do so while it accepts more blocks, it writes through a
+// [JournalDB], which records the value each authenticated key had at the last
+// summary the first time it is modified after it. [GetStateChunk] merges the
+// current state with the journals of the summaries from the requested one on.
+//
+// A syncing node writes the chunks it fetches to a [StateSyncer], which
+// rebuilds the state trie and checks that its root matches the state root
+// of the summary block.
+
+const (
+	journalAbsent  = 0x0
+	journalPresent = 0x1
+
+	// stateSummaryRetention is the number of state summaries whose state is
+	// served.
+	stateSummaryRetention = 2
+	stateSummaryLen       = 8 + len(ids.ID{})
+)
+
+var stateSummaryKey = []byte("state_summary")
+
+// StateSummary is the block a node serves the state at.
+type StateSummary struct {
+	Height  uint64 `serialize:"true" json:"height"`
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+}
+
+// SyncedPrefixes are the prefixes of all ranges fetched during state sync, in
+// the order they must be written to a [StateSyncer].
+//
+// Space keys and permissions are fetched after space info so that the entries
+// of expired spaces that have not been pruned yet can be skipped. The
+// pruning queue is not fetched, so these entries are never pruned by the
+// syncing node.
+var SyncedPrefixes = []byte{
+	infoPrefix, keyPrefix, expiryPrefix, balancePrefix, ownedPrefix,
+	permPrefix, keyTTLPrefix, auctionPrefix, bidPrefix, settlePrefix,
+	permExpiryPrefix,
+}
+
+// [journalPrefix] + [delimiter] + [summary height] + [key]
+func journalKey(height uint64, key []byte) (k []byte) {
+	k = make([]byte, 2+8+len(key))
+	k[0] = journalPrefix
+	k[1] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(k[2:], height)
+	copy(k[2+8:], key)
+	return k
+}
+
+// JournalDB journals the value each authenticated key had at the last state
+// summary the first time it is modified after it (nothing is journaled until
+// a summary is recorded).
+type JournalDB struct {
+	database.Database
+}
+
+func NewJournalDB(db database.Database) *JournalDB {
+	return &JournalDB{Database: db}
+}
+
+// journal writes the current value of [key] to the journal using [w] unless
+// it has already been journaled.
+func (j *JournalDB) journal(w database.KeyValueWriter, key []byte) error {
+	if len(key) < 2 || key[1] != parser.ByteDelimiter || !authenticated(key[0]) {
+		return nil
+	}
+	summary, exists, err := GetStateSummary(j.Database)
+	if err != nil || !exists {
+		return err
+	}
+	jk := journalKey(summary.Height, key)
+	has, err := j.Database.Has(jk)
+	if err != nil || has {
+		return err
+	}
+	v, err := j.Database.Get(key)
+	if errors.Is(err, database.ErrNotFound) {
+		return w.Put(jk, []byte{journalAbsent})
+	}
+	if err != nil {
+		return err
+	}
+	jv := make([]byte, 1+len(v))
+	jv[0] = journalPresent
+	copy(jv[1:], v)
+	return w.Put(jk, jv)
+}
+
+func (j *JournalDB) Put(key []byte, value []byte) error {
+	if err := j.journal(j.Database, key); err != nil {
+		return err
+	}
+	return j.Database.Put(key, value)
+}
+
+func (j *JournalDB) Delete(key []byte) error {
+	if err := j.journal(j.Database, key); err != nil {
+		return err
+	}
+	return j.Database.Delete(key)
+}
+
+func (j *JournalDB) NewBatch() database.Batch {
+	return &journalBatch{Batch: j.Database.NewBatch(), j: j}
+}
+
+// journalBatch writes journal entries in the same batch as the modifications
+// they are for.
+type journalBatch struct {
+	database.Batch
+
+	j *JournalDB
+}
+
+func (b *journalBatch) Put(key []byte, value []byte) error {
+	if err := b.j.journal(b.Batch, key); err != nil {
+		return err
+	}
+	return b.Batch.Put(key, value)
+}
+
+func (b *journalBatch) Delete(key []byte) error {
+	if err := b.j.journal(b.Batch, key); err != nil {
+		return err
+	}
+	return b.Batch.Delete(key)
+}
+
+// PutStateSummary records the current state as the state summary at
+// [height] and clears the journal of the summaries that are no longer
+// retained.
+func PutStateSummary(db database.Database, height uint64, blkID ids.ID) error {
+	summaries, err := GetStateSummaries(db)
+	if err != nil {
+		return err
+	}
+	summaries = append([]*StateSummary{{Height: height, BlockID: blkID}}, summaries...)
+	if len(summaries) > stateSummaryRetention {
+		summaries = summaries[:stateSummaryRetention]
+	}
+	batch := db.NewBatch()
+	if err := clearJournal(db, batch, summaries); err != nil {
+		return err
+	}
+	v := make([]byte, len(summaries)*stateSummaryLen)
+	for i, summary := range summaries {
+		binary.BigEndian.PutUint64(v[i*stateSummaryLen:], summary.Height)
+		copy(v[i*stateSummaryLen+8:], summary.BlockID[:])
+	}
+	if err := batch.Put(stateSummaryKey, v); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// DeleteStateSummary removes all state summaries (and their journals).
+func DeleteStateSummary(db database.Database) error {
+	batch := db.NewBatch()
+	if err := clearJournal(db, batch, nil); err != nil {
+		return err
+	}
+	if err := batch.Delete(stateSummaryKey); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// clearJournal deletes the journal entries of all summaries except
+// [retained].
+func clearJournal(db database.Iteratee, batch database.Batch, retained []*StateSummary) error {
+	base := []byte{journalPrefix, parser.ByteDelimiter}
+	cursor := db.NewIteratorWithPrefix(base)
+	defer cursor.Release()
+	for cursor.Next() {
+		k := cursor.Key()
+		if len(k) >= 2+8 && hasStateSummary(retained, binary.BigEndian.Uint64(k[2:])) {
+			continue
+		}
+		if err := batch.Delete(common.CopyBytes(k)); err != nil {
+			return err
+		}
+	}
+	return cursor.Error()
+}
+
+func hasStateSummary(summaries []*StateSummary, height uint64) bool {
+	for _, summary := range summaries {
+		if summary.Height == height {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStateSummaries returns the retained state summaries, starting with the
+// last one.
+func GetStateSummaries(db database.KeyValueReader) ([]*StateSummary, error) {
+	v, err := db.Get(stateSummaryKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 || len(v)%stateSummaryLen != 0 {
+		return nil, ErrInvalidKeyFormat
+	}
+	summaries := make([]*StateSummary, 0, len(v)/stateSummaryLen)
+	for ; len(v) > 0; v = v[stateSummaryLen:] {
+		blkID, err := ids.ToID(v[8:stateSummaryLen])
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, &StateSummary{Height: binary.BigEndian.Uint64(v), BlockID: blkID})
+	}
+	return summaries, nil
+}
+
+// GetStateSummary returns the last state summary.
+func GetStateSummary(db database.KeyValueReader) (*StateSummary, bool, error) {
+	summaries, err := GetStateSummaries(db)
+	if err != nil || len(summaries) == 0 {
+		return nil, false, err
+	}
+	return summaries[0], true, nil
+}
+
+// KeyValue is an entry in a range fetched during state sync.
+type KeyValue struct {
+	Key   []byte `serialize:"true" json:"key"`
+	Value []byte `serialize:"true" json:"value"`
+
+	// Linked is the value linked under the [ValueMeta] in [Value] (only set
+	// for space keys).
+	Linked []byte `serialize:"true" json:"linked,omitempty"`
+}
+
+// chunkLayer is a source of the entries merged by [GetStateChunk].
+type chunkLayer struct {
+	it database.Iterator
+	ok bool
+	// journal is true if [it] iterates over a journal, whose keys are
+	// prefixed by [journalKey].
+	journal bool
+}
+
+func (l *chunkLayer) key() []byte {
+	if l.journal {
+		return l.it.Key()[2+8:]
+	}
+	return l.it.Key()
+}
+
+// GetStateChunk returns the entries with [prefix] at the retained state
+// summary at [height], starting at [start], until [limit] entries or at least
+// [maxSize] bytes are returned. It also returns the key to start the next
+// chunk at (nil if there are no more entries).
+func GetStateChunk(
+	db database.Database,
+	height uint64,
+	prefix byte,
+	start []byte,
+	limit int,
+	maxSize int,
+) ([]*KeyValue, []byte, error) {
+	base := []byte{prefix, parser.ByteDelimiter}
+	if !authenticated(prefix) {
+		return nil, nil, fmt.Errorf("%w: prefix %d is not synced", ErrInvalidKeyFormat, prefix)
+	}
+	if len(start) == 0 {
+		start = base
+	}
+	if !bytes.HasPrefix(start, base) {
+		return nil, nil, fmt.Errorf("%w: start does not have prefix %d", ErrInvalidKeyFormat, prefix)
+	}
+	summaries, err := GetStateSummaries(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hasStateSummary(summaries, height) {
+		return nil, nil, fmt.Errorf("%w: height %d", ErrStateSummaryMissing, height)
+	}
+
+	// Keys modified since the summary were journaled in its journal or (if
+	// they were first modified after a later summary) in the journal of a
+	// later summary, so the journals take precedence over the current state
+	// and earlier journals take precedence over later ones.
+	layers := []*chunkLayer{}
+	for i := len(summaries) - 1; i >= 0; i-- {
+		if h := summaries[i].Height; h >= height {
+			it := db.NewIteratorWithStartAndPrefix(journalKey(h, start), journalKey(h, base))
+			defer it.Release()
+			layers = append(layers, &chunkLayer{it: it, journal: true})
+		}
+	}
+	curr := db.NewIteratorWithStartAndPrefix(start, base)
+	defer curr.Release()
+	layers = append(layers, &chunkLayer{it: curr})
+	for _, l := range layers {
+		l.ok = l.it.Next()
+	}
+
+	var (
+		kvs  = []*KeyValue{}
+		size int
+		last []byte
+	)
+	for len(kvs) < limit && size < maxSize {
+		var next *chunkLayer
+		for _, l := range layers {
+			if l.ok && (next == nil || bytes.Compare(l.key(), next.key()) < 0) {
+				next = l
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		key, value := common.CopyBytes(next.key()), common.CopyBytes(next.it.Value())
+		absent := false
+		if next.journal {
+			if len(value) == 0 {
+				return nil, nil, ErrInvalidKeyFormat
+			}
+			absent = value[0] == journalAbsent
+			value = value[1:]
+		}
+		for _, l := range layers {
+			if l.ok && bytes.Equal(l.key(), key) {
+				l.ok = l.it.Next()
+			}
+		}
+		last = key
+		if absent {
+			// Added after the summary
+			continue
+		}
+
+		kv := &KeyValue{Key: key, Value: value}
+		if prefix == keyPrefix {
+			linked, err := getSyncedValue(db, value)
+			if err != nil {
+				return nil, nil, err
+			}
+			kv.Linked = linked
+		}
+		kvs = append(kvs, kv)
+		size += len(kv.Key) + len(kv.Value) + len(kv.Linked)
+	}
+	done := true
+	for _, l := range layers {
+		if err := l.it.Error(); err != nil {
+			return nil, nil, err
+		}
+		done = done && !l.ok
+	}
+	if done {
+		return kvs, nil, nil
+	}
+	return kvs, append(last, 0x0), nil
+}
+
+// getSyncedValue returns the value linked under [rvmeta] (if any). Linked
+// values are never modified or deleted, so the current value is the value at
+// the summary.
+func getSyncedValue(db database.KeyValueReader, rvmeta []byte) ([]byte, error) {
+	vmeta, err := UnmarshalValueMeta(rvmeta)
+	if err != nil {
+		return nil, err
+	}
+	v, err := db.Get(PrefixTxValueKey(vmeta.ValueID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return v, err
+}
+
+// StateSyncer writes the ranges fetched during state sync and verifies them
+// against the state root of the summary block.
+type StateSyncer struct {
+	db *StateDB
+
+	// rspaces are the raw spaces of all synced space info
+	rspaces map[ids.ShortID]struct{}
+}
+
+func NewStateSyncer(db database.Database) *StateSyncer {
+	return &StateSyncer{db: NewStateDB(db), rspaces: map[ids.ShortID]struct{}{}}
+}
+
+// Put writes [kvs] from the range with [prefix]. Ranges must be written in
+// the order of [SyncedPrefixes].
+func (s *StateSyncer) Put(prefix byte, kvs []*KeyValue) error {
+	if !authenticated(prefix) {
+		return fmt.Errorf("%w: prefix %d is not synced", ErrInvalidStateChunk, prefix)
+	}
+	for _, kv := range kvs {
+		if len(kv.Key) < 2 || kv.Key[0] != prefix || kv.Key[1] != parser.ByteDelimiter {
+			return fmt.Errorf("%w: key %x does not have prefix %d", ErrInvalidStateChunk, kv.Key, prefix)
+		}
+		switch prefix {
+		case infoPrefix:
+			i := new(SpaceInfo)
+			if _, err := Unmarshal(kv.Value, i); err != nil {
+				return err
+			}
+			s.rspaces[i.RawSpace] = struct{}{}
+		case keyPrefix, permPrefix:
+			// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
+			// [permPrefix] + [delimiter] + [rawSpace] + [delimiter] + [address]
+			if len(kv.Key) < 2+shortIDLen+1 {
+				return fmt.Errorf("%w: key %x is too short", ErrInvalidStateChunk, kv.Key)
+			}
+			rspace, err := ids.ToShortID(kv.Key[2 : 2+shortIDLen])
+			if err != nil {
+				return err
+			}
+			if _, ok := s.rspaces[rspace]; !ok {
+				// The space expired but has not been pruned yet
+				continue
+			}
+			if prefix == keyPrefix {
+				if err := s.putLinked(kv); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.db.Put(kv.Key, kv.Value); err != nil {
+			return err
+		}
+	}
+
+	// Update the tries after every chunk so that we don't need to track all
+	// keys until the end
+	_, err := CommitState(s.db)
+	return err
+}
+
+// putLinked writes the value linked under the value meta in [kv], which is
+// only committed to by the state root through its hash.
+func (s *StateSyncer) putLinked(kv *KeyValue) error {
+	vmeta, err := UnmarshalValueMeta(kv.Value)
+	if err != nil {
+		return err
+	}
+	if h := crypto.Keccak256Hash(kv.Linked); h != vmeta.ValueHash {
+		return fmt.Errorf("%w: value hash expected %s got %s", ErrInvalidStateChunk, vmeta.ValueHash, h)
+	}
+	if len(kv.Linked) == 0 {
+		return nil
+	}
+	return s.db.Put(PrefixTxValueKey(vmeta.ValueID), kv.Linked)
+}
+
+// Verify checks that the state written matches [root].
+func (s *StateSyncer) Verify(root ids.ID) error {
+	r, err := CommitState(s.db)
+	if err != nil {
+		return err
+	}
+	if r != root {
+		return fmt.Errorf("%w: expected %s got %s", ErrInvalidStateRoot, root, r)
+	}
+	return nil
+}
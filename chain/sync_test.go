@@ -0,0 +1,201 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestStateSync(t *testing.T) {
+	t.Parallel()
+
+	db := NewJournalDB(memdb.New())
+	sdb := NewStateDB(db)
+	putValue := func(db database.Database, space string, key string, v []byte) {
+		valueID := ids.ID(crypto.Keccak256Hash(v))
+		vmeta := &ValueMeta{Size: uint64(len(v)), ValueID: valueID, ValueHash: crypto.Keccak256Hash(v)}
+		if err := PutSpaceKey(db, []byte(space), []byte(key), vmeta); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put(PrefixTxValueKey(valueID), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, spc := range []string{"a", "b", "c"} {
+		info := &SpaceInfo{Owner: common.Address{byte(i + 1)}, Expiry: 1000}
+		if spc == "c" {
+			info.Expiry = 50
+		}
+		if err := PutSpaceInfo(sdb, []byte(spc), info, 0); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{"k1", "k2", "k3"} {
+			putValue(sdb, spc, k, []byte(spc+k))
+		}
+		if err := PutPermission(sdb, info.RawSpace, &Permission{Grantee: common.Address{0x9}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := SetBalance(sdb, info.Owner, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := CommitState(sdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PutStateSummary(db, 10, ids.ID{0x1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Modify the state directly and through a batch
+	if err := SetBalance(sdb, common.Address{0x1}, 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteSpaceKey(sdb, []byte("a"), []byte("k2")); err != nil {
+		t.Fatal(err)
+	}
+	vdb := versiondb.New(sdb)
+	putValue(vdb, "b", "k4", []byte("new"))
+	putValue(vdb, "b", "k1", []byte("updated"))
+	if err := PutSpaceInfo(vdb, []byte("d"), &SpaceInfo{Owner: common.Address{0x4}, Expiry: 1000}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := vdb.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	// Expire space "c" without pruning it
+	if err := ExpireNext(DefaultGenesis(), sdb, 0, 60, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CommitState(sdb); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func(height uint64, prefix byte) (kvs []*KeyValue) {
+		var start []byte
+		for {
+			chunk, next, err := GetStateChunk(db, height, prefix, start, 2, 1024)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kvs = append(kvs, chunk...)
+			if next == nil {
+				return kvs
+			}
+			start = next
+		}
+	}
+
+	sync := func(height uint64) *StateSyncer {
+		syncer := NewStateSyncer(memdb.New())
+		for _, prefix := range SyncedPrefixes {
+			if err := syncer.Put(prefix, fetch(height, prefix)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return syncer
+	}
+
+	// The state at the summary can still be synced
+	syncer := sync(10)
+	if err := syncer.Verify(root); err != nil {
+		t.Fatal(err)
+	}
+	v, exists, err := GetValue(syncer.db, []byte("b"), []byte("k1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || string(v) != "bk1" {
+		t.Fatalf("unexpected value %q", v)
+	}
+
+	// Expired spaces are skipped once they are in the summary
+	current, err := GetStateRoot(sdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PutStateSummary(db, 20, ids.ID{0x2}); err != nil {
+		t.Fatal(err)
+	}
+	syncer = sync(20)
+	if err := syncer.Verify(root); !errors.Is(err, ErrInvalidStateRoot) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := syncer.Verify(current); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := HasSpaceKey(syncer.db, []byte("c"), []byte("k1")); err != nil || has {
+		t.Fatalf("unexpected key in expired space (%v)", err)
+	}
+
+	// The state at the previous summary is still served after more changes,
+	// including to keys that were already changed since it
+	if err := SetBalance(sdb, common.Address{0x1}, 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetBalance(sdb, common.Address{0x2}, 30); err != nil {
+		t.Fatal(err)
+	}
+	putValue(sdb, "b", "k1", []byte("again"))
+	putValue(sdb, "a", "k2", []byte("back"))
+	if err := DeleteSpaceKey(sdb, []byte("b"), []byte("k4")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CommitState(sdb); err != nil {
+		t.Fatal(err)
+	}
+	if err := sync(10).Verify(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := sync(20).Verify(current); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the last summaries are retained
+	if err := PutStateSummary(db, 30, ids.ID{0x3}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := GetStateChunk(db, 10, balancePrefix, nil, 1, 1); !errors.Is(err, ErrStateSummaryMissing) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := sync(20).Verify(current); err != nil {
+		t.Fatal(err)
+	}
+	summaries, err := GetStateSummaries(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 || summaries[0].Height != 30 || summaries[1].Height != 20 {
+		t.Fatalf("unexpected summaries %+v", summaries)
+	}
+
+	// Values must match their value meta
+	kvs := fetch(30, keyPrefix)
+	for _, kv := range kvs {
+		kv.Linked = []byte("other")
+	}
+	syncer = NewStateSyncer(memdb.New())
+	if err := syncer.Put(infoPrefix, fetch(30, infoPrefix)); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncer.Put(keyPrefix, kvs); !errors.Is(err, ErrInvalidStateChunk) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Entries must be in the range
+	if err := syncer.Put(balancePrefix, fetch(30, ownedPrefix)); !errors.Is(err, ErrInvalidStateChunk) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, _, err := GetStateChunk(db, 30, pruningPrefix, nil, 1, 1); !errors.Is(err, ErrInvalidKeyFormat) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
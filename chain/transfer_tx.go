@@ -56,19 +56,15 @@ func (t *TransferTx) Copy() UnsignedTransaction {
 }
 
 func (t *TransferTx) TypedData() *tdata.TypedData {
-	return tdata.CreateTypedData(
-		t.Magic, Transfer,
+	return t.typedData(
+		Transfer,
 		[]tdata.Type{
 			{Name: tdTo, Type: tdAddress},
 			{Name: tdUnits, Type: tdUint64},
-			{Name: tdPrice, Type: tdUint64},
-			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdTo:      t.To.Hex(),
-			tdUnits:   strconv.FormatUint(t.Units, 10),
-			tdPrice:   strconv.FormatUint(t.Price, 10),
-			tdBlockID: t.BlockID.String(),
+			tdTo:    t.To.Hex(),
+			tdUnits: strconv.FormatUint(t.Units, 10),
 		},
 	)
 }
@@ -15,11 +15,18 @@ type Transaction struct {
 	UnsignedTransaction `serialize:"true" json:"unsignedTransaction"`
 	Signature           []byte `serialize:"true" json:"signature"`
 
+	// Signatures are additional signatures over the same digest, used to
+	// authorize actions on spaces controlled by an owner set. The fee is
+	// always paid by the signer of [Signature].
+	Signatures [][]byte `serialize:"true" json:"signatures"`
+
 	digestHash []byte
 	bytes      []byte
 	id         ids.ID
+	replayID   ids.ID
 	size       uint64
 	sender     common.Address
+	cosigners  []common.Address
 }
 
 func NewTx(utx UnsignedTransaction, sig []byte) *Transaction {
@@ -29,12 +36,31 @@ func NewTx(utx UnsignedTransaction, sig []byte) *Transaction {
 	}
 }
 
+// NewMultiSigTx creates a transaction sent by the signer of [sig] and
+// co-signed by the signers of [sigs].
+func NewMultiSigTx(utx UnsignedTransaction, sig []byte, sigs [][]byte) *Transaction {
+	tx := NewTx(utx, sig)
+	if len(sigs) > 0 {
+		tx.Signatures = sigs
+	}
+	return tx
+}
+
 func (t *Transaction) Copy() *Transaction {
 	sig := make([]byte, len(t.Signature))
 	copy(sig, t.Signature)
+	var sigs [][]byte
+	if len(t.Signatures) > 0 {
+		sigs = make([][]byte, len(t.Signatures))
+		for i, s := range t.Signatures {
+			sigs[i] = make([]byte, len(s))
+			copy(sigs[i], s)
+		}
+	}
 	return &Transaction{
 		UnsignedTransaction: t.UnsignedTransaction.Copy(),
 		Signature:           sig,
+		Signatures:          sigs,
 	}
 }
 
@@ -68,7 +94,77 @@ func (t *Transaction) Init(g *Genesis) error {
 		return err
 	}
 	t.sender = crypto.PubkeyToAddress(*pk)
+	t.replayID = replayID(t.sender, t.digestHash)
+
+	// Derive cosigners
+	if len(t.Signatures) > MaxOwners {
+		return ErrTooManySignatures
+	}
+	signers := map[common.Address]struct{}{t.sender: {}}
+	t.cosigners = make([]common.Address, 0, len(t.Signatures))
+	for _, sig := range t.Signatures {
+		pk, err := DeriveSender(t.digestHash, sig)
+		if err != nil {
+			return err
+		}
+		cosigner := crypto.PubkeyToAddress(*pk)
+		if _, ok := signers[cosigner]; ok {
+			return ErrDuplicateSignature
+		}
+		signers[cosigner] = struct{}{}
+		t.cosigners = append(t.cosigners, cosigner)
+	}
+
+	t.size = uint64(len(t.Bytes()))
+	return nil
+}
+
+// InitUnsigned initializes [t] as if it was signed by [sender] and
+// co-signed by [cosigners], so that it can be simulated before it is signed.
+// Missing signatures are replaced by placeholders so that the size and fee
+// units of [t] match those of the signed tx.
+func (t *Transaction) InitUnsigned(g *Genesis, sender common.Address, cosigners []common.Address) error {
+	if len(cosigners) > MaxOwners {
+		return ErrTooManySignatures
+	}
+	if len(t.Signature) == 0 {
+		t.Signature = make([]byte, crypto.SignatureLength)
+	}
+	if len(t.Signatures) == 0 && len(cosigners) > 0 {
+		t.Signatures = make([][]byte, len(cosigners))
+		for i := range t.Signatures {
+			t.Signatures[i] = make([]byte, crypto.SignatureLength)
+		}
+	}
+	if len(t.Signatures) != len(cosigners) {
+		return ErrInvalidSignature
+	}
+	signers := map[common.Address]struct{}{sender: {}}
+	for _, cosigner := range cosigners {
+		if _, ok := signers[cosigner]; ok {
+			return ErrDuplicateSignature
+		}
+		signers[cosigner] = struct{}{}
+	}
 
+	stx, err := Marshal(t)
+	if err != nil {
+		return err
+	}
+	t.bytes = stx
+	id, err := ids.ToID(crypto.Keccak256(t.bytes))
+	if err != nil {
+		return err
+	}
+	t.id = id
+	dh, err := DigestHash(t.UnsignedTransaction)
+	if err != nil {
+		return err
+	}
+	t.digestHash = dh
+	t.sender = sender
+	t.replayID = replayID(t.sender, t.digestHash)
+	t.cosigners = cosigners
 	t.size = uint64(len(t.Bytes()))
 	return nil
 }
@@ -81,8 +177,29 @@ func (t *Transaction) ID() ids.ID { return t.id }
 
 func (t *Transaction) DigestHash() []byte { return t.digestHash }
 
+// ReplayID identifies the action authorized by the sender of [t]. Unlike
+// [ID], it doesn't commit to the signatures, which can be re-encoded (e.g. by
+// reordering or dropping cosignatures) without invalidating [t].
+func (t *Transaction) ReplayID() ids.ID { return t.replayID }
+
+func replayID(sender common.Address, digestHash []byte) ids.ID {
+	return ids.ID(crypto.Keccak256Hash(sender[:], digestHash))
+}
+
 func (t *Transaction) Sender() common.Address { return t.sender }
 
+func (t *Transaction) Cosigners() []common.Address { return t.cosigners }
+
+// FeeUnits charges [BaseTxUnits] for each additional signature that must be
+// verified.
+func (t *Transaction) FeeUnits(g *Genesis) uint64 {
+	return t.UnsignedTransaction.FeeUnits(g) + uint64(len(t.Signatures))*g.BaseTxUnits
+}
+
+func (t *Transaction) LoadUnits(g *Genesis) uint64 {
+	return t.UnsignedTransaction.LoadUnits(g) + uint64(len(t.Signatures))*g.BaseTxUnits
+}
+
 func (t *Transaction) Execute(g *Genesis, db database.Database, blk *StatelessBlock, context *Context) error {
 	if err := t.UnsignedTransaction.ExecuteBase(g); err != nil {
 		return err
@@ -92,19 +209,20 @@ func (t *Transaction) Execute(g *Genesis, db database.Database, blk *StatelessBl
 		// Should not happen beause of mempool cleanup
 		return ErrInvalidBlockID
 	}
-	if context.RecentTxIDs.Contains(t.ID()) {
-		// Tx hash must not be recently executed (otherwise could be replayed)
+	if context.RecentReplayIDs.Contains(t.ReplayID()) {
+		// Tx must not be recently executed (otherwise could be replayed)
 		//
-		// NOTE: We only need to keep cached tx hashes around as long as the
+		// NOTE: We only need to keep cached replay IDs around as long as the
 		// block hash referenced in the tx is valid
 		return ErrDuplicateTx
 	}
 
 	// Ensure sender has balance
-	if _, err := ModifyBalance(db, t.sender, false, t.FeeUnits(g)*t.GetPrice()); err != nil {
+	price := EffectivePrice(t.UnsignedTransaction, context.NextPrice)
+	if _, err := ModifyBalance(db, t.sender, false, t.FeeUnits(g)*price); err != nil {
 		return err
 	}
-	if t.GetPrice() < context.NextPrice {
+	if price < context.NextPrice {
 		return ErrInsufficientPrice
 	}
 	if err := t.UnsignedTransaction.Execute(&TransactionContext{
@@ -113,41 +231,21 @@ func (t *Transaction) Execute(g *Genesis, db database.Database, blk *StatelessBl
 		BlockTime: uint64(blk.Tmstmp),
 		TxID:      t.id,
 		Sender:    t.sender,
+		Cosigners: t.cosigners,
 	}); err != nil {
 		return err
 	}
-	if err := SetTransaction(db, t); err != nil {
-		return err
-	}
+	return SetTransaction(db, t)
+}
 
-	// Process lottery reward
-	//
-	// If there is no space after the selected iterator, no reward will be
-	// distributed.
-	if blk.Dummy() {
-		// Do not process any rewards if it is just a dummy block
-		return nil
+// rewardAmount is the lottery reward paid out for [t] in a block with
+// [price].
+func (t *Transaction) rewardAmount(g *Genesis, price uint64) uint64 {
+	if g.FeeMarketEnabled {
+		// The unburned part of the base price and the tip are paid out instead
+		return t.FeeUnits(g) * (price - burnedPrice(g, price) + EffectiveTip(t.UnsignedTransaction, price))
 	}
-	rewardAmount := t.FeeUnits(g) * blk.Price * g.LotteryRewardMultipler / LotteryRewardDivisor
-	if rewardAmount == 0 {
-		// For transactions (like transfers) where the [FeeUnits] are equal to the [BaseTxFee], it
-		// is possible that the reward could be 0.
-		return nil
-	}
-	recipient, distributed, err := ApplyReward(db, blk.ID(), t.ID(), t.sender, rewardAmount)
-	if err != nil {
-		return err
-	}
-	if distributed {
-		blk.Winners[t.ID()] = &Activity{
-			Tmstmp: blk.Tmstmp,
-			Typ:    Reward,
-			TxID:   t.ID(),
-			To:     recipient.Hex(),
-			Units:  rewardAmount,
-		}
-	}
-	return nil
+	return t.FeeUnits(g) * price * g.LotteryRewardMultipler / LotteryRewardDivisor
 }
 
 func (t *Transaction) Activity() *Activity {
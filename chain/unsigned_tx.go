@@ -17,6 +17,10 @@ type TransactionContext struct {
 	BlockTime uint64
 	TxID      ids.ID
 	Sender    common.Address
+
+	// Cosigners are the addresses that signed the transaction in addition to
+	// [Sender].
+	Cosigners []common.Address
 }
 
 type UnsignedTransaction interface {
@@ -24,9 +28,13 @@ type UnsignedTransaction interface {
 	GetBlockID() ids.ID
 	GetMagic() uint64
 	GetPrice() uint64
+	GetMaxPrice() uint64
+	GetPriorityTip() uint64
 	SetBlockID(ids.ID)
 	SetMagic(uint64)
 	SetPrice(uint64)
+	SetMaxPrice(uint64)
+	SetPriorityTip(uint64)
 	FeeUnits(*Genesis) uint64  // number of units to mine tx
 	LoadUnits(*Genesis) uint64 // units that should impact fee rate
 
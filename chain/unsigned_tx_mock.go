@@ -136,6 +136,20 @@ func (mr *MockUnsignedTransactionMockRecorder) GetMagic() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMagic", reflect.TypeOf((*MockUnsignedTransaction)(nil).GetMagic))
 }
 
+// GetMaxPrice mocks base method.
+func (m *MockUnsignedTransaction) GetMaxPrice() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxPrice")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetMaxPrice indicates an expected call of GetMaxPrice.
+func (mr *MockUnsignedTransactionMockRecorder) GetMaxPrice() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxPrice", reflect.TypeOf((*MockUnsignedTransaction)(nil).GetMaxPrice))
+}
+
 // GetPrice mocks base method.
 func (m *MockUnsignedTransaction) GetPrice() uint64 {
 	m.ctrl.T.Helper()
@@ -150,6 +164,20 @@ func (mr *MockUnsignedTransactionMockRecorder) GetPrice() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrice", reflect.TypeOf((*MockUnsignedTransaction)(nil).GetPrice))
 }
 
+// GetPriorityTip mocks base method.
+func (m *MockUnsignedTransaction) GetPriorityTip() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriorityTip")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetPriorityTip indicates an expected call of GetPriorityTip.
+func (mr *MockUnsignedTransactionMockRecorder) GetPriorityTip() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriorityTip", reflect.TypeOf((*MockUnsignedTransaction)(nil).GetPriorityTip))
+}
+
 // LoadUnits mocks base method.
 func (m *MockUnsignedTransaction) LoadUnits(arg0 *Genesis) uint64 {
 	m.ctrl.T.Helper()
@@ -188,6 +216,18 @@ func (mr *MockUnsignedTransactionMockRecorder) SetMagic(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMagic", reflect.TypeOf((*MockUnsignedTransaction)(nil).SetMagic), arg0)
 }
 
+// SetMaxPrice mocks base method.
+func (m *MockUnsignedTransaction) SetMaxPrice(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMaxPrice", arg0)
+}
+
+// SetMaxPrice indicates an expected call of SetMaxPrice.
+func (mr *MockUnsignedTransactionMockRecorder) SetMaxPrice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaxPrice", reflect.TypeOf((*MockUnsignedTransaction)(nil).SetMaxPrice), arg0)
+}
+
 // SetPrice mocks base method.
 func (m *MockUnsignedTransaction) SetPrice(arg0 uint64) {
 	m.ctrl.T.Helper()
@@ -200,6 +240,18 @@ func (mr *MockUnsignedTransactionMockRecorder) SetPrice(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrice", reflect.TypeOf((*MockUnsignedTransaction)(nil).SetPrice), arg0)
 }
 
+// SetPriorityTip mocks base method.
+func (m *MockUnsignedTransaction) SetPriorityTip(arg0 uint64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPriorityTip", arg0)
+}
+
+// SetPriorityTip indicates an expected call of SetPriorityTip.
+func (mr *MockUnsignedTransactionMockRecorder) SetPriorityTip(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriorityTip", reflect.TypeOf((*MockUnsignedTransaction)(nil).SetPriorityTip), arg0)
+}
+
 // TypedData mocks base method.
 func (m *MockUnsignedTransaction) TypedData() *tdata.TypedData {
 	m.ctrl.T.Helper()
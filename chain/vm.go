@@ -9,8 +9,10 @@ import (
 )
 
 type Context struct {
-	RecentBlockIDs  ids.Set
-	RecentTxIDs     ids.Set
+	RecentBlockIDs ids.Set
+	// RecentReplayIDs are the [Transaction.ReplayID]s of recently executed
+	// transactions
+	RecentReplayIDs ids.Set
 	RecentLoadUnits uint64
 
 	Prices []uint64
@@ -30,4 +32,5 @@ type VM interface {
 	Verified(*StatelessBlock)
 	Rejected(*StatelessBlock)
 	Accepted(*StatelessBlock)
+	Metrics() *Metrics
 }
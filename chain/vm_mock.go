@@ -123,6 +123,20 @@ func (mr *MockVMMockRecorder) Mempool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mempool", reflect.TypeOf((*MockVM)(nil).Mempool))
 }
 
+// Metrics mocks base method.
+func (m *MockVM) Metrics() *Metrics {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metrics")
+	ret0, _ := ret[0].(*Metrics)
+	return ret0
+}
+
+// Metrics indicates an expected call of Metrics.
+func (mr *MockVMMockRecorder) Metrics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metrics", reflect.TypeOf((*MockVM)(nil).Metrics))
+}
+
 // Rejected mocks base method.
 func (m *MockVM) Rejected(arg0 *StatelessBlock) {
 	m.ctrl.T.Helper()
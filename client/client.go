@@ -7,12 +7,14 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 
@@ -38,11 +40,31 @@ type Client interface {
 	Claimed(ctx context.Context, space string) (bool, error)
 	// Returns the corresponding space information.
 	Info(ctx context.Context, space string) (*chain.SpaceInfo, []*chain.KeyValueMeta, error)
+	// Returns up to [limit] keys in a space starting with [prefix] and after
+	// [startAfter], along with the key to start after for the next page (empty
+	// if there are no more keys).
+	ListKeys(
+		ctx context.Context,
+		space string,
+		prefix string,
+		startAfter string,
+		limit int,
+	) ([]*chain.KeyValueMeta, string, error)
 	// Balance returns the balance of an account
 	Balance(ctx context.Context, addr common.Address) (bal uint64, err error)
 	// Resolve returns the value associated with a path
 	Resolve(ctx context.Context, path string) (exists bool, value []byte, valueMeta *chain.ValueMeta, err error)
 
+	// ProveInfo returns the space information with a proof against the last
+	// accepted state root (see the "proof" package to verify it).
+	ProveInfo(ctx context.Context, space string) (*vm.InfoReply, error)
+	// ProveBalance returns the balance of an account with a proof against the
+	// last accepted state root.
+	ProveBalance(ctx context.Context, addr common.Address) (*vm.BalanceReply, error)
+	// ProveResolve returns the value associated with a path (or its absence)
+	// with a proof against the last accepted state root.
+	ProveResolve(ctx context.Context, path string) (*vm.ResolveReply, error)
+
 	// Requests the suggested price and cost from VM.
 	SuggestedRawFee(ctx context.Context) (uint64, uint64, error)
 	// Issues the transaction and returns the transaction ID.
@@ -51,18 +73,58 @@ type Client interface {
 	// Requests the suggested price and cost from VM, returns the input as
 	// TypedData.
 	SuggestedFee(ctx context.Context, i *chain.Input) (*tdata.TypedData, uint64, error)
+	// Like [SuggestedFee] but estimates the fee required for the transaction
+	// to be included with urgency [u].
+	SuggestedUrgentFee(ctx context.Context, i *chain.Input, u vm.Urgency) (*tdata.TypedData, uint64, error)
+	// Returns the price, cost and usage of up to [blocks] accepted blocks (from
+	// the oldest to the last accepted).
+	FeeHistory(ctx context.Context, blocks int) ([]*vm.BlockFee, error)
 	// Issues a human-readable transaction and returns the transaction ID.
-	IssueTx(ctx context.Context, td *tdata.TypedData, sig []byte) (ids.ID, error)
+	// [cosigs] are optional co-signatures over the same typed data.
+	IssueTx(ctx context.Context, td *tdata.TypedData, sig []byte, cosigs ...[]byte) (ids.ID, error)
+	// Executes a transaction without signing or issuing it and returns the
+	// changes it would make to the state.
+	Simulate(ctx context.Context, args *vm.SimulateArgs) (*vm.SimulateReply, error)
 
 	// Checks the status of the transaction, and returns "true" if confirmed.
 	HasTx(ctx context.Context, id ids.ID) (bool, error)
 	// Polls the transactions until its status is confirmed.
 	PollTx(ctx context.Context, txID ids.ID) (confirmed bool, err error)
 
+	// Returns the accepted block with all values restored.
+	GetBlockByID(ctx context.Context, id ids.ID) (*chain.StatefulBlock, error)
+	// Returns the ID and the accepted block at the given height.
+	GetBlockByHeight(ctx context.Context, height uint64) (ids.ID, *chain.StatefulBlock, error)
+	// Returns an accepted transaction and the ID of the block it was included
+	// in.
+	GetTx(ctx context.Context, id ids.ID) (*chain.Transaction, ids.ID, error)
+	// Returns a transaction in the mempool of the node.
+	PendingTx(ctx context.Context, id ids.ID) (*chain.Transaction, error)
+	// Returns the receipt of a transaction, "false" if not yet accepted.
+	Receipt(ctx context.Context, id ids.ID) (bool, *chain.Receipt, error)
+
 	// Recent actions on the network (sorted from recent to oldest)
 	RecentActivity(ctx context.Context) ([]*chain.Activity, error)
+	// Indexed actions in a time range (sorted from oldest to recent), also
+	// returns the cursor of the next page (empty if there are no more).
+	ActivityRange(ctx context.Context, page vm.ActivityPage) ([]*chain.Activity, []byte, error)
+	// Indexed actions sent or received by an address
+	AddressActivity(ctx context.Context, addr common.Address, page vm.ActivityPage) ([]*chain.Activity, []byte, error)
+	// Indexed actions in a space
+	SpaceActivity(ctx context.Context, space string, page vm.ActivityPage) ([]*chain.Activity, []byte, error)
 	// All spaces owned by a given address
 	Owned(ctx context.Context, owner common.Address) ([]string, error)
+	// All permissions granted in a given space
+	Permissions(ctx context.Context, space string) ([]*chain.Permission, error)
+
+	// Returns the auction and all bids for an unclaimed space
+	Auction(ctx context.Context, space string) (*chain.Auction, []*chain.SpaceBid, error)
+	// All auctions that have not yet been settled
+	Auctions(ctx context.Context) ([]*chain.Auction, error)
+
+	// Subscribe streams the events selected by [s] as blocks are accepted.
+	// The channel is closed once [ctx] is done or the connection fails.
+	Subscribe(ctx context.Context, s *vm.Subscription) (<-chan *vm.Event, error)
 }
 
 // New creates a new client object.
@@ -72,10 +134,11 @@ func New(uri string, reqTimeout time.Duration) Client {
 		vm.PublicEndpoint,
 		"spacesvm",
 	)
-	return &client{req: req}
+	return &client{uri: uri, req: req}
 }
 
 type client struct {
+	uri string
 	req rpc.EndpointRequester
 }
 
@@ -143,6 +206,25 @@ func (cli *client) Info(ctx context.Context, space string) (*chain.SpaceInfo, []
 	return resp.Info, resp.Values, nil
 }
 
+func (cli *client) ListKeys(
+	ctx context.Context,
+	space string,
+	prefix string,
+	startAfter string,
+	limit int,
+) ([]*chain.KeyValueMeta, string, error) {
+	resp := new(vm.ListKeysReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"listKeys",
+		&vm.ListKeysArgs{Space: space, Prefix: prefix, StartAfter: startAfter, Limit: limit},
+		resp,
+	); err != nil {
+		return nil, "", err
+	}
+	return resp.Keys, resp.Next, nil
+}
+
 func (cli *client) Accepted(ctx context.Context) (ids.ID, error) {
 	resp := new(vm.LastAcceptedReply)
 	if err := cli.req.SendRequest(
@@ -183,6 +265,19 @@ func (cli *client) IssueRawTx(ctx context.Context, d []byte) (ids.ID, error) {
 	return resp.TxID, nil
 }
 
+func (cli *client) Simulate(ctx context.Context, args *vm.SimulateArgs) (*vm.SimulateReply, error) {
+	resp := new(vm.SimulateReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"simulate",
+		args,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (cli *client) HasTx(ctx context.Context, txID ids.ID) (bool, error) {
 	resp := new(vm.HasTxReply)
 	if err := cli.req.SendRequest(
@@ -197,11 +292,19 @@ func (cli *client) HasTx(ctx context.Context, txID ids.ID) (bool, error) {
 }
 
 func (cli *client) SuggestedFee(ctx context.Context, i *chain.Input) (*tdata.TypedData, uint64, error) {
+	return cli.SuggestedUrgentFee(ctx, i, "")
+}
+
+func (cli *client) SuggestedUrgentFee(
+	ctx context.Context,
+	i *chain.Input,
+	u vm.Urgency,
+) (*tdata.TypedData, uint64, error) {
 	resp := new(vm.SuggestedFeeReply)
 	if err := cli.req.SendRequest(
 		ctx,
 		"suggestedFee",
-		&vm.SuggestedFeeArgs{Input: i},
+		&vm.SuggestedFeeArgs{Input: i, Urgency: u},
 		resp,
 	); err != nil {
 		return nil, 0, err
@@ -209,12 +312,34 @@ func (cli *client) SuggestedFee(ctx context.Context, i *chain.Input) (*tdata.Typ
 	return resp.TypedData, resp.TotalCost, nil
 }
 
-func (cli *client) IssueTx(ctx context.Context, td *tdata.TypedData, sig []byte) (ids.ID, error) {
+func (cli *client) FeeHistory(ctx context.Context, blocks int) ([]*vm.BlockFee, error) {
+	resp := new(vm.FeeHistoryReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"feeHistory",
+		&vm.FeeHistoryArgs{Blocks: blocks},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Blocks, nil
+}
+
+func (cli *client) IssueTx(
+	ctx context.Context,
+	td *tdata.TypedData,
+	sig []byte,
+	cosigs ...[]byte,
+) (ids.ID, error) {
+	sigs := make([]hexutil.Bytes, len(cosigs))
+	for i, cosig := range cosigs {
+		sigs[i] = cosig
+	}
 	resp := new(vm.IssueTxReply)
 	if err := cli.req.SendRequest(
 		ctx,
 		"issueTx",
-		&vm.IssueTxArgs{TypedData: td, Signature: sig},
+		&vm.IssueTxArgs{TypedData: td, Signature: sig, Signatures: sigs},
 		resp,
 	); err != nil {
 		return ids.Empty, err
@@ -273,6 +398,45 @@ func (cli *client) Resolve(ctx context.Context, path string) (bool, []byte, *cha
 	return true, resp.Value, resp.ValueMeta, nil
 }
 
+func (cli *client) ProveInfo(ctx context.Context, space string) (*vm.InfoReply, error) {
+	resp := new(vm.InfoReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"info",
+		&vm.InfoArgs{Space: space, Proof: true},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) ProveBalance(ctx context.Context, addr common.Address) (*vm.BalanceReply, error) {
+	resp := new(vm.BalanceReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"balance",
+		&vm.BalanceArgs{Address: addr, Proof: true},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) ProveResolve(ctx context.Context, path string) (*vm.ResolveReply, error) {
+	resp := new(vm.ResolveReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"resolve",
+		&vm.ResolveArgs{Path: path, Proof: true},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (cli *client) IssueTxHR(ctx context.Context, d []byte, sig []byte) (ids.ID, error) {
 	return ids.ID{}, errors.New("not implemented")
 }
@@ -305,6 +469,53 @@ func (cli *client) RecentActivity(ctx context.Context) (activity []*chain.Activi
 	return resp.Activity, nil
 }
 
+func (cli *client) ActivityRange(ctx context.Context, page vm.ActivityPage) ([]*chain.Activity, []byte, error) {
+	resp := new(vm.ActivityReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"activityRange",
+		&page,
+		resp,
+	); err != nil {
+		return nil, nil, err
+	}
+	return resp.Activity, resp.Next, nil
+}
+
+func (cli *client) AddressActivity(
+	ctx context.Context,
+	addr common.Address,
+	page vm.ActivityPage,
+) ([]*chain.Activity, []byte, error) {
+	resp := new(vm.ActivityReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"addressActivity",
+		&vm.AddressActivityArgs{Address: addr, ActivityPage: page},
+		resp,
+	); err != nil {
+		return nil, nil, err
+	}
+	return resp.Activity, resp.Next, nil
+}
+
+func (cli *client) SpaceActivity(
+	ctx context.Context,
+	space string,
+	page vm.ActivityPage,
+) ([]*chain.Activity, []byte, error) {
+	resp := new(vm.ActivityReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"spaceActivity",
+		&vm.SpaceActivityArgs{Space: space, ActivityPage: page},
+		resp,
+	); err != nil {
+		return nil, nil, err
+	}
+	return resp.Activity, resp.Next, nil
+}
+
 func (cli *client) Owned(ctx context.Context, addr common.Address) (spaces []string, err error) {
 	resp := new(vm.OwnedReply)
 	if err = cli.req.SendRequest(
@@ -319,3 +530,182 @@ func (cli *client) Owned(ctx context.Context, addr common.Address) (spaces []str
 	}
 	return resp.Spaces, nil
 }
+
+func (cli *client) Permissions(ctx context.Context, space string) (perms []*chain.Permission, err error) {
+	resp := new(vm.PermissionsReply)
+	if err = cli.req.SendRequest(
+		ctx,
+		"permissions",
+		&vm.PermissionsArgs{
+			Space: space,
+		},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Permissions, nil
+}
+
+func (cli *client) Auction(ctx context.Context, space string) (*chain.Auction, []*chain.SpaceBid, error) {
+	resp := new(vm.AuctionReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"auction",
+		&vm.AuctionArgs{
+			Space: space,
+		},
+		resp,
+	); err != nil {
+		return nil, nil, err
+	}
+	return resp.Auction, resp.Bids, nil
+}
+
+func (cli *client) Auctions(ctx context.Context) ([]*chain.Auction, error) {
+	resp := new(vm.AuctionsReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"auctions",
+		nil,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Auctions, nil
+}
+
+func (cli *client) Receipt(ctx context.Context, txID ids.ID) (bool, *chain.Receipt, error) {
+	resp := new(vm.ReceiptReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"receipt",
+		&vm.ReceiptArgs{TxID: txID},
+		resp,
+	); err != nil {
+		return false, nil, err
+	}
+	return resp.Accepted, resp.Receipt, nil
+}
+
+// blockReply mirrors [vm.GetBlockReply] without the JSON-encoded block, which
+// cannot be decoded into the [chain.UnsignedTransaction] interface.
+type blockReply struct {
+	BlockID ids.ID `json:"blockId"`
+	Bytes   []byte `json:"bytes"`
+}
+
+func (cli *client) GetBlockByID(ctx context.Context, id ids.ID) (*chain.StatefulBlock, error) {
+	resp := new(blockReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"getBlockByID",
+		&vm.GetBlockByIDArgs{BlockID: id},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	if err := checkBlockID(id, resp.Bytes); err != nil {
+		return nil, err
+	}
+	return cli.parseBlock(ctx, resp.Bytes)
+}
+
+func (cli *client) GetBlockByHeight(ctx context.Context, height uint64) (ids.ID, *chain.StatefulBlock, error) {
+	resp := new(blockReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"getBlockByHeight",
+		&vm.GetBlockByHeightArgs{Height: height},
+		resp,
+	); err != nil {
+		return ids.Empty, nil, err
+	}
+	if err := checkBlockID(resp.BlockID, resp.Bytes); err != nil {
+		return ids.Empty, nil, err
+	}
+	blk, err := cli.parseBlock(ctx, resp.Bytes)
+	return resp.BlockID, blk, err
+}
+
+// checkBlockID ensures we are not served a block other than [id].
+func checkBlockID(id ids.ID, b []byte) error {
+	if found := ids.ID(crypto.Keccak256Hash(b)); found != id {
+		return fmt.Errorf("%w: expected %s got %s", ErrInvalidBlock, id, found)
+	}
+	return nil
+}
+
+func (cli *client) parseBlock(ctx context.Context, b []byte) (*chain.StatefulBlock, error) {
+	blk := new(chain.StatefulBlock)
+	if _, err := chain.Unmarshal(b, blk); err != nil {
+		return nil, err
+	}
+	if len(blk.Txs) == 0 {
+		return blk, nil
+	}
+	g, err := cli.Genesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range blk.Txs {
+		if err := tx.Init(g); err != nil {
+			return nil, err
+		}
+	}
+	return blk, nil
+}
+
+// txReply mirrors [vm.GetTxReply] and [vm.PendingTxReply] without the
+// JSON-encoded transaction.
+type txReply struct {
+	BlockID ids.ID `json:"blockId"`
+	Bytes   []byte `json:"bytes"`
+}
+
+func (cli *client) GetTx(ctx context.Context, id ids.ID) (*chain.Transaction, ids.ID, error) {
+	resp := new(txReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"getTx",
+		&vm.GetTxArgs{TxID: id},
+		resp,
+	); err != nil {
+		return nil, ids.Empty, err
+	}
+	tx := new(chain.Transaction)
+	if _, err := chain.Unmarshal(resp.Bytes, tx); err != nil {
+		return nil, ids.Empty, err
+	}
+	g, err := cli.Genesis(ctx)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	if err := tx.Init(g); err != nil {
+		return nil, ids.Empty, err
+	}
+	return tx, resp.BlockID, nil
+}
+
+func (cli *client) PendingTx(ctx context.Context, id ids.ID) (*chain.Transaction, error) {
+	resp := new(txReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"pendingTx",
+		&vm.PendingTxArgs{TxID: id},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	tx := new(chain.Transaction)
+	if _, err := chain.Unmarshal(resp.Bytes, tx); err != nil {
+		return nil, err
+	}
+	g, err := cli.Genesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Init(g); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
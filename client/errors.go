@@ -5,4 +5,11 @@ package client
 
 import "errors"
 
-var ErrIntegrityFailure = errors.New("received file that does not match hash")
+var (
+	ErrIntegrityFailure = errors.New("received file that does not match hash")
+	ErrReceiptMissing   = errors.New("receipt missing")
+	ErrInvalidBlock     = errors.New("received block that does not match ID")
+	ErrSimulationFailed = errors.New("simulated transaction failed")
+	ErrNotSender        = errors.New("private key is not the sender of the transaction")
+	ErrNotReplaceable   = errors.New("transaction can't be replaced")
+)
@@ -8,14 +8,17 @@ import (
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 
 	"github.com/ava-labs/spacesvm/chain"
 	"github.com/ava-labs/spacesvm/tdata"
+	"github.com/ava-labs/spacesvm/vm"
 )
 
 func PPInfo(info *chain.SpaceInfo) {
@@ -40,6 +43,52 @@ func PPActivity(a []*chain.Activity) error {
 	return nil
 }
 
+func PPEvent(ev *vm.Event) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	switch ev.Type {
+	case vm.ErrorEvent:
+		color.Red(string(b))
+	case vm.ExpiryEvent:
+		color.Yellow(string(b))
+	case vm.BalanceEvent:
+		color.Green(string(b))
+	default:
+		color.Cyan(string(b))
+	}
+	return nil
+}
+
+func PPSimulation(r *vm.SimulateReply) {
+	color.Yellow(
+		"simulated tx (fee units=%d, price=%d, total cost=%d)",
+		r.FeeUnits, r.Price, r.TotalCost,
+	)
+	if len(r.Error) > 0 {
+		color.Red("would fail: %s", r.Error)
+		return
+	}
+	for _, c := range r.Changes {
+		switch {
+		case c.Before == nil:
+			color.Green("+ %s %s: %s", c.Type, c.Key, c.After)
+		case c.After == nil:
+			color.Red("- %s %s: %s", c.Type, c.Key, c.Before)
+		default:
+			color.Cyan("~ %s %s: %s -> %s", c.Type, c.Key, c.Before, c.After)
+		}
+	}
+}
+
+// IsConditionFailed returns true if [err] was caused by the condition of a
+// [chain.ConditionalSetTx] not holding. The error type is lost over RPC, so
+// this inspects the error message.
+func IsConditionFailed(err error) bool {
+	return err != nil && strings.Contains(err.Error(), chain.ErrConditionFailed.Error())
+}
+
 // Signs and issues the transaction (node construction).
 func SignIssueTx(
 	ctx context.Context,
@@ -48,13 +97,17 @@ func SignIssueTx(
 	priv *ecdsa.PrivateKey,
 	opts ...OpOption,
 ) (txID ids.ID, cost uint64, err error) {
-	ret := &Op{}
+	ret := &Op{urgency: vm.UrgencyNormal}
 	ret.applyOpts(opts)
 
-	td, txCost, err := cli.SuggestedFee(ctx, input)
+	td, txCost, err := cli.SuggestedUrgentFee(ctx, input, ret.urgency)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
+	if ret.dryRun != nil {
+		sender := crypto.PubkeyToAddress(priv.PublicKey)
+		return ids.Empty, txCost, dryRun(ctx, ret, cli, td, sender, nil)
+	}
 
 	dh, err := tdata.DigestHash(td)
 	if err != nil {
@@ -105,7 +158,23 @@ func SignIssueRawTx(
 
 	utx.SetBlockID(la)
 	utx.SetMagic(g.Magic)
-	utx.SetPrice(price + blockCost/utx.FeeUnits(g))
+	chain.SetFee(g, utx, price, blockCost, chain.DefaultPriorityTip)
+	return signIssueRawTx(ctx, ret, cli, g, utx, priv)
+}
+
+// signIssueRawTx signs and issues [utx] with its fee already set.
+func signIssueRawTx(
+	ctx context.Context,
+	ret *Op,
+	cli Client,
+	g *chain.Genesis,
+	utx chain.UnsignedTransaction,
+	priv *ecdsa.PrivateKey,
+) (ids.ID, uint64, error) {
+	if ret.dryRun != nil {
+		sender := crypto.PubkeyToAddress(priv.PublicKey)
+		return ids.Empty, chain.MaxFee(utx, utx.FeeUnits(g)), dryRun(ctx, ret, cli, utx.TypedData(), sender, nil)
+	}
 
 	dh, err := chain.DigestHash(utx)
 	if err != nil {
@@ -123,10 +192,10 @@ func SignIssueRawTx(
 	}
 
 	color.Yellow(
-		"issuing tx %s (fee units=%d, load units=%d, price=%d, blkID=%s)",
-		tx.ID(), tx.FeeUnits(g), tx.LoadUnits(g), tx.GetPrice(), tx.GetBlockID(),
+		"issuing tx %s (fee units=%d, load units=%d, price=%d, max price=%d, tip=%d, blkID=%s)",
+		tx.ID(), tx.FeeUnits(g), tx.LoadUnits(g), tx.GetPrice(), tx.GetMaxPrice(), tx.GetPriorityTip(), tx.GetBlockID(),
 	)
-	txID, err = cli.IssueRawTx(ctx, tx.Bytes())
+	txID, err := cli.IssueRawTx(ctx, tx.Bytes())
 	if err != nil {
 		return ids.Empty, 0, err
 	}
@@ -134,14 +203,35 @@ func SignIssueRawTx(
 	if err := handleConfirmation(ctx, ret, cli, txID, priv); err != nil {
 		return ids.Empty, 0, err
 	}
-	return txID, utx.GetPrice() * utx.FeeUnits(g), nil
+	return txID, chain.MaxFee(utx, utx.FeeUnits(g)), nil
+}
+
+// dryRun simulates [td] instead of issuing it (see [WithDryRun]).
+func dryRun(
+	ctx context.Context, ret *Op, cli Client,
+	td *tdata.TypedData, sender common.Address, cosigners []common.Address,
+) error {
+	r, err := cli.Simulate(ctx, &vm.SimulateArgs{
+		TypedData: td,
+		Sender:    sender,
+		Cosigners: cosigners,
+	})
+	if err != nil {
+		return err
+	}
+	*ret.dryRun = *r
+	PPSimulation(r)
+	if len(r.Error) > 0 {
+		return fmt.Errorf("%w: %s", ErrSimulationFailed, r.Error)
+	}
+	return nil
 }
 
 func handleConfirmation(
 	ctx context.Context, ret *Op, cli Client,
 	txID ids.ID, priv *ecdsa.PrivateKey,
 ) error {
-	if ret.pollTx {
+	if ret.pollTx || ret.receipt != nil {
 		color.Yellow("issued transaction %s (now polling)", txID)
 		confirmed, err := cli.PollTx(ctx, txID)
 		if err != nil {
@@ -154,6 +244,21 @@ func handleConfirmation(
 		}
 	}
 
+	if ret.receipt != nil {
+		accepted, r, err := cli.Receipt(ctx, txID)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return fmt.Errorf("%w: %s", ErrReceiptMissing, txID)
+		}
+		*ret.receipt = *r
+		color.Yellow(
+			"receipt: block=%s height=%d index=%d fee=%d (units=%d, price=%d, burned=%d, reward=%d)",
+			r.BlockID, r.Height, r.Index, r.Fee, r.FeeUnits, r.Price, r.Burned, r.Reward,
+		)
+	}
+
 	if len(ret.space) > 0 {
 		info, _, err := cli.Info(ctx, ret.space)
 		if err != nil {
@@ -179,6 +284,9 @@ type Op struct {
 	pollTx  bool
 	space   string
 	balance bool
+	receipt *chain.Receipt
+	dryRun  *vm.SimulateReply
+	urgency vm.Urgency
 }
 
 type OpOption func(*Op)
@@ -202,3 +310,22 @@ func WithInfo(space string) OpOption {
 func WithBalance() OpOption {
 	return func(op *Op) { op.balance = true }
 }
+
+// Non-nil to poll the transaction and populate [r] with its receipt once
+// confirmed.
+func WithReceipt(r *chain.Receipt) OpOption {
+	return func(op *Op) { op.receipt = r }
+}
+
+// Non-nil to simulate the transaction instead of issuing it and populate [r]
+// with the result. Signatures are not required to simulate the transaction
+// and an error is returned if it would fail.
+func WithDryRun(r *vm.SimulateReply) OpOption {
+	return func(op *Op) { op.dryRun = r }
+}
+
+// Sets how soon a transaction issued with [SignIssueTx] should be included
+// ([vm.UrgencyNormal] by default).
+func WithUrgency(u vm.Urgency) OpOption {
+	return func(op *Op) { op.urgency = u }
+}
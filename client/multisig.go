@@ -0,0 +1,157 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var (
+	ErrNoSignatures      = errors.New("no signatures")
+	ErrTypedDataMismatch = errors.New("typed data does not match")
+)
+
+// PartialTx is a transaction that is collecting signatures from the owners of
+// a space. It can be passed between signers (offline) and is issued once it
+// has enough signatures. The first signature pays the fee.
+type PartialTx struct {
+	TypedData  *tdata.TypedData `json:"typedData"`
+	Signatures []hexutil.Bytes  `json:"signatures"`
+}
+
+// NewPartialTx creates an unsigned [PartialTx] for [input] using the fee
+// parameters suggested by [cli].
+func NewPartialTx(ctx context.Context, cli Client, input *chain.Input) (*PartialTx, error) {
+	td, _, err := cli.SuggestedFee(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return &PartialTx{TypedData: td}, nil
+}
+
+// Sign adds a signature by [priv]. Signing twice with the same key is a no-op.
+func (p *PartialTx) Sign(priv *ecdsa.PrivateKey) error {
+	dh, err := tdata.DigestHash(p.TypedData)
+	if err != nil {
+		return fmt.Errorf("%w: failed to compute digest hash", err)
+	}
+	signers, err := p.Signers()
+	if err != nil {
+		return err
+	}
+	addr := crypto.PubkeyToAddress(priv.PublicKey)
+	for _, signer := range signers {
+		if signer == addr {
+			return nil
+		}
+	}
+	sig, err := chain.Sign(dh, priv)
+	if err != nil {
+		return err
+	}
+	p.Signatures = append(p.Signatures, sig)
+	return nil
+}
+
+// Merge adds all signatures in [o] that are not already in [p]. Both must be
+// over the same typed data.
+func (p *PartialTx) Merge(o *PartialTx) error {
+	dh, err := tdata.DigestHash(p.TypedData)
+	if err != nil {
+		return err
+	}
+	odh, err := tdata.DigestHash(o.TypedData)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(dh, odh) {
+		return ErrTypedDataMismatch
+	}
+	signers, err := p.Signers()
+	if err != nil {
+		return err
+	}
+	seen := make(map[common.Address]struct{}, len(signers))
+	for _, signer := range signers {
+		seen[signer] = struct{}{}
+	}
+	for _, sig := range o.Signatures {
+		pk, err := chain.DeriveSender(dh, sig)
+		if err != nil {
+			return err
+		}
+		addr := crypto.PubkeyToAddress(*pk)
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		p.Signatures = append(p.Signatures, sig)
+	}
+	return nil
+}
+
+// Signers returns the addresses that have signed [p], in signature order.
+func (p *PartialTx) Signers() ([]common.Address, error) {
+	dh, err := tdata.DigestHash(p.TypedData)
+	if err != nil {
+		return nil, err
+	}
+	signers := make([]common.Address, len(p.Signatures))
+	for i, sig := range p.Signatures {
+		pk, err := chain.DeriveSender(dh, sig)
+		if err != nil {
+			return nil, err
+		}
+		signers[i] = crypto.PubkeyToAddress(*pk)
+	}
+	return signers, nil
+}
+
+// IssuePartialTx issues [p] with its first signature as the sender.
+func IssuePartialTx(
+	ctx context.Context,
+	cli Client,
+	p *PartialTx,
+	priv *ecdsa.PrivateKey,
+	opts ...OpOption,
+) (txID ids.ID, err error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	if len(p.Signatures) == 0 {
+		return ids.Empty, ErrNoSignatures
+	}
+	if ret.dryRun != nil {
+		signers, err := p.Signers()
+		if err != nil {
+			return ids.Empty, err
+		}
+		return ids.Empty, dryRun(ctx, ret, cli, p.TypedData, signers[0], signers[1:])
+	}
+	cosigs := make([][]byte, len(p.Signatures)-1)
+	for i, sig := range p.Signatures[1:] {
+		cosigs[i] = sig
+	}
+	txID, err = cli.IssueTx(ctx, p.TypedData, p.Signatures[0], cosigs...)
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	if err := handleConfirmation(ctx, ret, cli, txID, priv); err != nil {
+		return ids.Empty, err
+	}
+	return txID, nil
+}
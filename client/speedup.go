@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/mempool"
+)
+
+// SpeedUp replaces the pending transaction [txID] with a copy that pays enough
+// more to replace it in the mempool (see [mempool.ReplacementBumpPercent]) and
+// at least the currently suggested fee.
+//
+// [priv] must be the sender of [txID]. Transactions co-signed by the owners of
+// a space can't be replaced this way.
+func SpeedUp(
+	ctx context.Context,
+	cli Client,
+	txID ids.ID,
+	priv *ecdsa.PrivateKey,
+	opts ...OpOption,
+) (ids.ID, uint64, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	tx, err := cli.PendingTx(ctx, txID)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	if tx.Sender() != crypto.PubkeyToAddress(priv.PublicKey) {
+		return ids.Empty, 0, ErrNotSender
+	}
+	if len(tx.Signatures) > 0 {
+		return ids.Empty, 0, ErrNotReplaceable
+	}
+
+	g, err := cli.Genesis(ctx)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	la, err := cli.Accepted(ctx)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	price, blockCost, err := cli.SuggestedRawFee(ctx)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+
+	utx := tx.UnsignedTransaction.Copy()
+	utx.SetBlockID(la)
+	if g.FeeMarketEnabled {
+		tip := maxUint64(mempool.Bump(utx.GetPriorityTip()), chain.DefaultPriorityTip)
+		utx.SetPriorityTip(tip)
+		utx.SetMaxPrice(maxUint64(mempool.Bump(utx.GetMaxPrice()), 2*price+tip))
+	} else {
+		suggested := price + blockCost/utx.FeeUnits(g)
+		utx.SetPrice(maxUint64(mempool.Bump(utx.GetPrice()), suggested))
+	}
+	return signIssueRawTx(ctx, ret, cli, g, utx, priv)
+}
+
+func maxUint64(a uint64, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
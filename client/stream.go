@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+// streamURI returns the websocket URI of [vm.StreamEndpoint] on the node at
+// [uri].
+func streamURI(uri string) string {
+	switch {
+	case strings.HasPrefix(uri, "https://"):
+		uri = "wss://" + strings.TrimPrefix(uri, "https://")
+	case strings.HasPrefix(uri, "http://"):
+		uri = "ws://" + strings.TrimPrefix(uri, "http://")
+	}
+	return strings.TrimSuffix(uri, "/") + vm.StreamEndpoint
+}
+
+func (cli *client) Subscribe(ctx context.Context, s *vm.Subscription) (<-chan *vm.Event, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, streamURI(cli.uri), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.WriteJSON(s); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	events := make(chan *vm.Event)
+	done := make(chan struct{})
+	go func() {
+		// Unblocks [ReadJSON] once [ctx] is done
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+	go func() {
+		defer close(events)
+		defer close(done)
+		for {
+			ev := new(vm.Event)
+			if err := conn.ReadJSON(ev); err != nil {
+				if ctx.Err() == nil {
+					color.Red("stream closed %v", err)
+				}
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -5,17 +5,56 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 
+	"github.com/ava-labs/spacesvm/chain"
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/vm"
 )
 
+var (
+	activityAddress string
+	activitySpace   string
+	activitySince   time.Duration
+)
+
+func init() {
+	activityCmd.PersistentFlags().StringVar(
+		&activityAddress,
+		"address",
+		"",
+		"only show activity sent or received by this address",
+	)
+	activityCmd.PersistentFlags().StringVar(
+		&activitySpace,
+		"space",
+		"",
+		"only show activity in this space",
+	)
+	activityCmd.PersistentFlags().DurationVar(
+		&activitySince,
+		"since",
+		0,
+		"only show activity in this duration before now",
+	)
+}
+
 var activityCmd = &cobra.Command{
 	Use:   "activity [options]",
 	Short: "View recent activity on the network",
-	RunE:  activityFunc,
+	Long: `
+Without any filters, shows the most recent activity kept in memory
+by the node. With filters, queries the persistent activity index.
+
+$ spaces-cli activity --space hello --since 24h
+`,
+	RunE: activityFunc,
 }
 
 func activityFunc(cmd *cobra.Command, args []string) error {
@@ -23,12 +62,57 @@ func activityFunc(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
 	}
 	cli := client.New(uri, requestTimeout)
-	activity, err := cli.RecentActivity(context.Background())
-	if err != nil {
-		return err
+	if len(activityAddress) == 0 && len(activitySpace) == 0 && activitySince == 0 {
+		activity, err := cli.RecentActivity(context.Background())
+		if err != nil {
+			return err
+		}
+		return client.PPActivity(activity)
+	}
+	if len(activityAddress) > 0 && len(activitySpace) > 0 {
+		return errors.New("--address and --space cannot be used together")
+	}
+
+	var fetch func(page vm.ActivityPage) ([]*chain.Activity, []byte, error)
+	switch {
+	case len(activityAddress) > 0:
+		if !common.IsHexAddress(activityAddress) {
+			return fmt.Errorf("invalid address %q", activityAddress)
+		}
+		addr := common.HexToAddress(activityAddress)
+		fetch = func(page vm.ActivityPage) ([]*chain.Activity, []byte, error) {
+			return cli.AddressActivity(context.Background(), addr, page)
+		}
+	case len(activitySpace) > 0:
+		if err := parser.CheckContents(activitySpace); err != nil {
+			return fmt.Errorf("%w: failed to parse space", err)
+		}
+		fetch = func(page vm.ActivityPage) ([]*chain.Activity, []byte, error) {
+			return cli.SpaceActivity(context.Background(), activitySpace, page)
+		}
+	default:
+		fetch = func(page vm.ActivityPage) ([]*chain.Activity, []byte, error) {
+			return cli.ActivityRange(context.Background(), page)
+		}
+	}
+
+	page := vm.ActivityPage{}
+	if activitySince > 0 {
+		page.Since = uint64(time.Now().Add(-activitySince).Unix())
 	}
-	if err := client.PPActivity(activity); err != nil {
-		return err
+	for {
+		activity, next, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		if len(activity) > 0 || len(page.Cursor) == 0 {
+			if err := client.PPActivity(activity); err != nil {
+				return err
+			}
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		page.Cursor = next
 	}
-	return nil
 }
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var auctionCmd = &cobra.Command{
+	Use:   "auction [options] [space]",
+	Short: "Views open auctions or the bids for a space",
+	RunE:  auctionFunc,
+}
+
+func auctionFunc(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("expected at most 1 argument, got %d", len(args))
+	}
+
+	cli := client.New(uri, requestTimeout)
+	if len(args) == 0 {
+		auctions, err := cli.Auctions(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(auctions) == 0 {
+			color.Cyan("no open auctions")
+		}
+		for _, a := range auctions {
+			ppAuction(a)
+		}
+		return nil
+	}
+
+	if err := parser.CheckContents(args[0]); err != nil {
+		return fmt.Errorf("%w: failed to parse space", err)
+	}
+	a, bids, err := cli.Auction(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	ppAuction(a)
+	for _, b := range bids {
+		color.Cyan("  bidder=%s units=%d updated=%v", b.Bidder.Hex(), b.Units, time.Unix(int64(b.Updated), 0))
+	}
+	return nil
+}
+
+func ppAuction(a *chain.Auction) {
+	end := time.Unix(int64(a.End), 0)
+	color.Green(
+		"auction %s: high bid=%d bidder=%s end=%v (%v remaining)",
+		a.Space, a.HighBid, a.HighBidder.Hex(), end, time.Until(end),
+	)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch [options] <operations file>",
+	Short: "Atomically applies multiple operations",
+	Long: `
+Issues "BatchTx" to apply an ordered list of set, delete, and
+lifeline operations. Either all operations succeed or none
+of them are applied.
+
+The operations file is a JSON array of operations:
+
+[
+  {"type": "set", "space": "hello", "key": "foo", "value": "hello world"},
+  {"type": "delete", "space": "hello", "key": "bar"},
+  {"type": "lifeline", "space": "hello", "units": 10}
+]
+
+$ spaces-cli batch ops.json
+<<COMMENT
+success
+COMMENT
+`,
+	RunE: batchFunc,
+}
+
+// batchOp mirrors [chain.BatchOp] but accepts [Value] as a plain string.
+type batchOp struct {
+	Type  string `json:"type"`
+	Space string `json:"space"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Units uint64 `json:"units"`
+}
+
+func batchFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	ops, err := getBatchOp(args)
+	if err != nil {
+		return err
+	}
+
+	utx := &chain.BatchTx{
+		BaseTx: &chain.BaseTx{},
+		Ops:    ops,
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("applied %d operations", len(ops))
+	return nil
+}
+
+func getBatchOp(args []string) (ops []*chain.BatchOp, err error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	rops := []*batchOp{}
+	if err := json.Unmarshal(b, &rops); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse operations", err)
+	}
+	if len(rops) == 0 {
+		return nil, chain.ErrNonActionable
+	}
+
+	ops = make([]*chain.BatchOp, len(rops))
+	for i, rop := range rops {
+		ops[i] = &chain.BatchOp{
+			Typ:   rop.Type,
+			Space: rop.Space,
+			Key:   rop.Key,
+			Units: rop.Units,
+		}
+		if len(rop.Value) > 0 {
+			ops[i].Value = []byte(rop.Value)
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var bidCmd = &cobra.Command{
+	Use:   "bid [options] <space> <units>",
+	Short: "Bids on an unclaimed space",
+	Long: `
+Issues "BidTx" to escrow <units> of your balance as a bid
+for an unclaimed space (only available if auctions are
+enabled in the genesis). The first bid on a space opens
+an auction. Once the auction window ends, the space is given
+to the highest bidder and all other bids are refunded.
+
+Bidding again on the same space replaces your previous bid.
+
+$ spaces-cli bid hello 5000
+<<COMMENT
+success
+COMMENT
+`,
+	RunE: bidFunc,
+}
+
+func bidFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	space, units, err := getBidOp(args)
+	if err != nil {
+		return err
+	}
+
+	utx := &chain.BidTx{
+		BaseTx: &chain.BaseTx{},
+		Space:  space,
+		Units:  units,
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("bid %d on %s", units, space)
+	return nil
+}
+
+func getBidOp(args []string) (space string, units uint64, err error) {
+	if len(args) != 2 {
+		return "", 0, fmt.Errorf("expected exactly 2 arguments, got %d", len(args))
+	}
+
+	if err := parser.CheckContents(args[0]); err != nil {
+		return "", 0, fmt.Errorf("%w: failed to parse space", err)
+	}
+	units, err = strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: failed to parse units", err)
+	}
+	return args[0], units, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var blockCmd = &cobra.Command{
+	Use:   "block [options] <block ID | height>",
+	Short: "Reads an accepted block and its transactions",
+	Long: `
+Reads an accepted block by its ID or height.
+
+$ spaces-cli block 12
+<<COMMENT
+block 2Y3...: height=12 parent=... timestamp=... price=1 cost=2 txs=1
+COMMENT
+`,
+	RunE: blockFunc,
+}
+
+func blockFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+
+	cli := client.New(uri, requestTimeout)
+	var (
+		id  ids.ID
+		blk *chain.StatefulBlock
+		err error
+	)
+	if height, perr := strconv.ParseUint(args[0], 10, 64); perr == nil {
+		id, blk, err = cli.GetBlockByHeight(context.Background(), height)
+	} else {
+		id, err = ids.FromString(args[0])
+		if err != nil {
+			return fmt.Errorf("%w: failed to parse block ID", err)
+		}
+		blk, err = cli.GetBlockByID(context.Background(), id)
+	}
+	if err != nil {
+		return err
+	}
+
+	color.Green(
+		"block %s: height=%d parent=%s timestamp=%v price=%d cost=%d txs=%d",
+		id, blk.Hght, blk.Prnt, time.Unix(blk.Tmstmp, 0), blk.Price, blk.Cost, len(blk.Txs),
+	)
+	for _, tx := range blk.Txs {
+		if err := ppTx(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ppTx(tx *chain.Transaction) error {
+	b, err := json.Marshal(tx.UnsignedTransaction)
+	if err != nil {
+		return err
+	}
+	color.Cyan("tx %s: sender=%s %s", tx.ID(), tx.Sender().Hex(), string(b))
+	return nil
+}
@@ -56,9 +56,12 @@ func claimFunc(cmd *cobra.Command, args []string) error {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	color.Green("claimed %s", space)
 	return nil
@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var combineCmd = &cobra.Command{
+	Use:   "combine [options] <transaction file> [<transaction file>...]",
+	Short: "Combines partial signatures and issues the transaction",
+	Long: `
+Merges the signatures of one or more transaction files created
+with "spaces-cli partial-sign" (which must all be for the same
+transaction) and issues the result. The signer of the first
+signature pays the fee.
+
+$ spaces-cli combine move-alice.json move-bob.json
+<<COMMENT
+success
+COMMENT
+`,
+	RunE: combineFunc,
+}
+
+func combineFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected at least 1 argument, got %d", len(args))
+	}
+
+	p, err := readPartialTx(args[0])
+	if err != nil {
+		return err
+	}
+	for _, path := range args[1:] {
+		o, err := readPartialTx(path)
+		if err != nil {
+			return err
+		}
+		if err := p.Merge(o); err != nil {
+			return fmt.Errorf("%w: %s", err, path)
+		}
+	}
+	signers, err := p.Signers()
+	if err != nil {
+		return err
+	}
+	for _, signer := range signers {
+		color.Yellow("signed by %s", signer.Hex())
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	txID, err := client.IssuePartialTx(context.Background(), cli, p, priv, withDryRun(opts)...)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("issued %s with %d signatures", txID, len(p.Signatures))
+	return nil
+}
@@ -6,9 +6,33 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
 	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/vm"
 )
 
+var dryRun bool
+
+// addDryRunFlag adds "--dry-run" to a command that issues transactions.
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"simulate the transaction and print the state it would change instead of issuing it",
+	)
+}
+
+// withDryRun adds [client.WithDryRun] to [opts] if "--dry-run" is set.
+func withDryRun(opts []client.OpOption) []client.OpOption {
+	if !dryRun {
+		return opts
+	}
+	return append(opts, client.WithDryRun(new(vm.SimulateReply)))
+}
+
 func getPathOp(args []string) (space string, key string, err error) {
 	if len(args) != 1 {
 		return "", "", fmt.Errorf("expected exactly 1 argument, got %d", len(args))
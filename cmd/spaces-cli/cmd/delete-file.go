@@ -32,9 +32,12 @@ func deleteFileFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	cli := client.New(uri, requestTimeout)
-	if err := tree.Delete(context.Background(), cli, args[0], priv); err != nil {
+	if err := tree.Delete(context.Background(), cli, args[0], priv, withDryRun(nil)...); err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	color.Green("deleted file %s", args[0])
 	return nil
@@ -43,9 +43,12 @@ func deleteFunc(cmd *cobra.Command, args []string) error {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	color.Green("deleted %s from %s", key, space)
 	return nil
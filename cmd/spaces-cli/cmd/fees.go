@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var feesCmd = &cobra.Command{
+	Use:   "fees [options] [blocks]",
+	Short: "Reads the fees and usage of recently accepted blocks",
+	Long: `
+Reads the price, cost and usage of the last accepted blocks (10 by default).
+
+$ spaces-cli fees 2
+<<COMMENT
+block 2Y3...: height=11 price=1 cost=0 txs=0 load=0 fill=0.00
+block 8Jk...: height=12 price=2 cost=1 txs=3 load=230 fill=0.93
+COMMENT
+`,
+	RunE: feesFunc,
+}
+
+func feesFunc(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("expected at most 1 argument, got %d", len(args))
+	}
+	blocks := 10
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("%w: failed to parse number of blocks", err)
+		}
+		blocks = n
+	}
+
+	cli := client.New(uri, requestTimeout)
+	history, err := cli.FeeHistory(context.Background(), blocks)
+	if err != nil {
+		return err
+	}
+	for _, b := range history {
+		color.Green(
+			"block %s: height=%d price=%d cost=%d txs=%d load=%d fill=%.2f",
+			b.BlockID, b.Height, b.Price, b.Cost, b.Txs, b.LoadUnits, b.FillRatio,
+		)
+	}
+	return nil
+}
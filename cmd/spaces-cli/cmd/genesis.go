@@ -23,6 +23,9 @@ var (
 	minPrice    int64
 	claimReward int64
 
+	auctionWindow uint64
+	minBid        uint64
+
 	airdropHash  string
 	airdropUnits uint64
 )
@@ -46,6 +49,18 @@ func init() {
 		-1,
 		"seconds until a spaces will expire after being claimed",
 	)
+	genesisCmd.PersistentFlags().Uint64Var(
+		&auctionWindow,
+		"auction-window",
+		0,
+		"seconds an auction for a space lasts after it expires or the first bid (0 to claim spaces without auctions)",
+	)
+	genesisCmd.PersistentFlags().Uint64Var(
+		&minBid,
+		"min-bid",
+		0,
+		"minimum bid in a space auction",
+	)
 	genesisCmd.PersistentFlags().StringVar(
 		&airdropHash,
 		"airdrop-hash",
@@ -91,6 +106,8 @@ func genesisFunc(cmd *cobra.Command, args []string) error {
 	if claimReward >= 0 {
 		genesis.ClaimReward = uint64(claimReward)
 	}
+	genesis.AuctionWindow = auctionWindow
+	genesis.MinBid = minBid
 	if len(airdropHash) > 0 {
 		genesis.AirdropHash = airdropHash
 		if airdropUnits == 0 {
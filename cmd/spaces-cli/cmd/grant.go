@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var (
+	grantWrite     bool
+	grantDelete    bool
+	grantPrefix    string
+	grantExpiresIn time.Duration
+)
+
+func init() {
+	grantCmd.PersistentFlags().BoolVar(
+		&grantWrite,
+		"write",
+		false,
+		"allow the grantee to write values",
+	)
+	grantCmd.PersistentFlags().BoolVar(
+		&grantDelete,
+		"delete",
+		false,
+		"allow the grantee to delete values",
+	)
+	grantCmd.PersistentFlags().StringVar(
+		&grantPrefix,
+		"prefix",
+		"",
+		"only allow access to keys starting with this prefix",
+	)
+	grantCmd.PersistentFlags().DurationVar(
+		&grantExpiresIn,
+		"expires-in",
+		0,
+		"duration after which the permission no longer applies (0 for never)",
+	)
+}
+
+var grantCmd = &cobra.Command{
+	Use:   "grant [options] <space> <address>",
+	Short: "Delegates write and/or delete rights in a space",
+	Long: `
+Issues "GrantTx" to allow another address to write and/or
+delete values in a space owned by the sender. Granting to an
+address that already has a permission replaces it.
+
+# allows 0x... to write keys starting with "blog" for one day
+$ spaces-cli grant hello 0x... --write --prefix blog --expires-in 24h
+<<COMMENT
+success
+COMMENT
+`,
+	RunE: grantFunc,
+}
+
+func grantFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	space, to, err := getPermissionOp(args)
+	if err != nil {
+		return err
+	}
+	if !grantWrite && !grantDelete {
+		return fmt.Errorf("%w: must specify --write and/or --delete", chain.ErrNonActionable)
+	}
+	if len(grantPrefix) > 0 {
+		if err := parser.CheckContents(grantPrefix); err != nil {
+			return fmt.Errorf("%w: failed to parse prefix", err)
+		}
+	}
+
+	utx := &chain.GrantTx{
+		BaseTx: &chain.BaseTx{},
+		Space:  space,
+		To:     to,
+		Write:  grantWrite,
+		Delete: grantDelete,
+		Prefix: grantPrefix,
+	}
+	if grantExpiresIn > 0 {
+		utx.Expiry = uint64(time.Now().Add(grantExpiresIn).Unix())
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithInfo(space))
+		opts = append(opts, client.WithBalance())
+	}
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("granted %s access to %s", to.Hex(), space)
+	return nil
+}
+
+func getPermissionOp(args []string) (space string, to common.Address, err error) {
+	if len(args) != 2 {
+		return "", common.Address{}, fmt.Errorf("expected exactly 2 arguments, got %d", len(args))
+	}
+
+	if err := parser.CheckContents(args[0]); err != nil {
+		return "", common.Address{}, fmt.Errorf("%w: failed to parse space", err)
+	}
+	if !common.IsHexAddress(args[1]) {
+		return "", common.Address{}, fmt.Errorf("invalid address %q", args[1])
+	}
+	return args[0], common.HexToAddress(args[1]), nil
+}
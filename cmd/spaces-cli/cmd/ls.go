@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var (
+	lsStartAfter string
+	lsLimit      int
+)
+
+// lsPageSize is the number of keys requested at a time
+const lsPageSize = 100
+
+func init() {
+	lsCmd.PersistentFlags().StringVar(
+		&lsStartAfter,
+		"start-after",
+		"",
+		"only list keys after this key",
+	)
+	lsCmd.PersistentFlags().IntVar(
+		&lsLimit,
+		"limit",
+		0,
+		"maximum number of keys to list (0 for all)",
+	)
+}
+
+var lsCmd = &cobra.Command{
+	Use:   "ls [options] <space>[/<prefix>]",
+	Short: "Lists the keys in a space",
+	Long: `
+Lists the keys in a space that start with the optional prefix.
+
+$ spaces-cli ls hello/fo
+<<COMMENT
+foo=>{"tx":"...","created":...}
+COMMENT
+`,
+	RunE: lsFunc,
+}
+
+func lsFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	space, prefix := args[0], ""
+	if i := strings.Index(args[0], parser.Delimiter); i >= 0 {
+		space, prefix = args[0][:i], args[0][i+1:]
+	}
+	if err := parser.CheckContents(space); err != nil {
+		return fmt.Errorf("%w: failed to parse space", err)
+	}
+
+	cli := client.New(uri, requestTimeout)
+	startAfter, listed := lsStartAfter, 0
+	for {
+		limit := lsPageSize
+		if lsLimit > 0 && lsLimit-listed < limit {
+			limit = lsLimit - listed
+		}
+		kvs, next, err := cli.ListKeys(context.Background(), space, prefix, startAfter, limit)
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			hr, err := json.Marshal(kv.ValueMeta)
+			if err != nil {
+				return err
+			}
+			color.Yellow("%s=>%s", kv.Key, string(hr))
+		}
+		listed += len(kvs)
+		if len(next) == 0 || (lsLimit > 0 && listed >= lsLimit) {
+			return nil
+		}
+		startAfter = next
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var partialInputFile string
+
+func init() {
+	partialSignCmd.PersistentFlags().StringVar(
+		&partialInputFile,
+		"input",
+		"",
+		"chain.Input JSON file used to create the transaction file if it does not exist",
+	)
+}
+
+var partialSignCmd = &cobra.Command{
+	Use:   "partial-sign [options] <transaction file>",
+	Short: "Adds a signature to a transaction that needs multiple signers",
+	Long: `
+Adds a signature by the private key to a transaction file
+(typed data and the signatures collected so far). Signing an
+existing file does not require network access, so keys can
+be kept offline.
+
+When the transaction file does not exist yet, it is created
+from the "chain.Input" JSON passed with --input (this requires
+network access to fetch the current fee and block).
+
+# creates the transaction and adds the first signature
+$ spaces-cli partial-sign move.json --input move-input.json
+<<COMMENT
+signed move.json (1 signature)
+COMMENT
+
+# adds a second signature with another key
+$ spaces-cli partial-sign move.json --private-key-file=.other-key
+<<COMMENT
+signed move.json (2 signatures)
+COMMENT
+`,
+	RunE: partialSignFunc,
+}
+
+func partialSignFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+
+	p, err := readPartialTx(args[0])
+	switch {
+	case errors.Is(err, os.ErrNotExist) && len(partialInputFile) > 0:
+		b, err := os.ReadFile(partialInputFile)
+		if err != nil {
+			return err
+		}
+		input := new(chain.Input)
+		if err := json.Unmarshal(b, input); err != nil {
+			return fmt.Errorf("%w: failed to parse input", err)
+		}
+		cli := client.New(uri, requestTimeout)
+		p, err = client.NewPartialTx(context.Background(), cli, input)
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	}
+
+	if err := p.Sign(priv); err != nil {
+		return err
+	}
+	if err := writePartialTx(args[0], p); err != nil {
+		return err
+	}
+	color.Green("signed %s (%d signatures)", args[0], len(p.Signatures))
+	return nil
+}
+
+func readPartialTx(path string) (*client.PartialTx, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(client.PartialTx)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse %s", err, path)
+	}
+	return p, nil
+}
+
+func writePartialTx(path string, p *client.PartialTx) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, fsModeWrite)
+}
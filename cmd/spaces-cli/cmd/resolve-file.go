@@ -13,9 +13,21 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/proof"
 	"github.com/ava-labs/spacesvm/tree"
 )
 
+var resolveFileVerify bool
+
+func init() {
+	resolveFileCmd.PersistentFlags().BoolVar(
+		&resolveFileVerify,
+		"verify",
+		false,
+		"verify the file root and chunks against the last accepted state root",
+	)
+}
+
 var resolveFileCmd = &cobra.Command{
 	Use:   "resolve-file [options] <space/key> <output path>",
 	Short: "Reads a file at space/key and saves it to disk",
@@ -38,7 +50,10 @@ func resolveFileFunc(cmd *cobra.Command, args []string) error {
 	}
 	defer f.Close()
 
-	cli := client.New(uri, requestTimeout)
+	var cli client.Client = client.New(uri, requestTimeout)
+	if resolveFileVerify {
+		cli = proof.NewClient(cli)
+	}
 	if err := tree.Download(context.Background(), cli, args[0], f); err != nil {
 		return err
 	}
@@ -12,8 +12,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/proof"
 )
 
+var resolveVerify bool
+
+func init() {
+	resolveCmd.PersistentFlags().BoolVar(
+		&resolveVerify,
+		"verify",
+		false,
+		"verify the value (or its absence) against the last accepted state root",
+	)
+}
+
 var resolveCmd = &cobra.Command{
 	Use:   "resolve [options] space/key",
 	Short: "Reads a value at space/key",
@@ -24,11 +36,19 @@ func resolveFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
 	}
-	cli := client.New(uri, requestTimeout)
+	var cli client.Client = client.New(uri, requestTimeout)
+	var pcli *proof.Client
+	if resolveVerify {
+		pcli = proof.NewClient(cli)
+		cli = pcli
+	}
 	_, v, vmeta, err := cli.Resolve(context.Background(), args[0])
 	if err != nil {
 		return err
 	}
+	if pcli != nil {
+		color.Yellow("verified against block %s", pcli.LastBlockID())
+	}
 
 	color.Yellow("%s=>%q", args[0], v)
 	hr, err := json.Marshal(vmeta)
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var revokeCmd = &cobra.Command{
+	Use:   "revoke [options] <space> <address>",
+	Short: "Revokes a permission previously granted in a space",
+	RunE:  revokeFunc,
+}
+
+func revokeFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	space, to, err := getPermissionOp(args)
+	if err != nil {
+		return err
+	}
+
+	utx := &chain.RevokeTx{
+		BaseTx: &chain.BaseTx{},
+		Space:  space,
+		To:     to,
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithInfo(space))
+		opts = append(opts, client.WithBalance())
+	}
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("revoked %s access to %s", to.Hex(), space)
+	return nil
+}
@@ -41,21 +41,55 @@ func init() {
 		createCmd,
 		genesisCmd,
 		claimCmd,
+		bidCmd,
+		auctionCmd,
 		lifelineCmd,
 		setCmd,
 		deleteCmd,
 		resolveCmd,
 		infoCmd,
+		lsCmd,
 		activityCmd,
 		transferCmd,
 		moveCmd,
+		grantCmd,
+		revokeCmd,
+		setOwnersCmd,
+		partialSignCmd,
+		combineCmd,
+		batchCmd,
 		setFileCmd,
 		resolveFileCmd,
 		deleteFileCmd,
 		networkCmd,
 		ownedCmd,
+		blockCmd,
+		feesCmd,
+		txCmd,
+		speedupCmd,
+		watchCmd,
 	)
 
+	for _, cmd := range []*cobra.Command{
+		claimCmd,
+		bidCmd,
+		lifelineCmd,
+		setCmd,
+		deleteCmd,
+		transferCmd,
+		moveCmd,
+		grantCmd,
+		revokeCmd,
+		setOwnersCmd,
+		combineCmd,
+		batchCmd,
+		setFileCmd,
+		deleteFileCmd,
+		speedupCmd,
+	} {
+		addDryRunFlag(cmd)
+	}
+
 	rootCmd.PersistentFlags().StringVar(
 		&privateKeyFile,
 		"private-key-file",
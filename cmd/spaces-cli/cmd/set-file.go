@@ -42,10 +42,13 @@ func setFileFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	// TODO: protect against overflow
-	path, err := tree.Upload(context.Background(), cli, priv, space, f, int(g.MaxValueSize))
+	path, err := tree.Upload(context.Background(), cli, priv, space, f, int(g.MaxValueSize), withDryRun(nil)...)
 	if err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	color.Green("uploaded file %s from %s", path, f.Name())
 	return nil
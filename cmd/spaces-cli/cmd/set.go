@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -16,6 +19,33 @@ import (
 	"github.com/ava-labs/spacesvm/parser"
 )
 
+var (
+	ifMatch  string
+	ifAbsent bool
+	setTTL   time.Duration
+)
+
+func init() {
+	setCmd.PersistentFlags().StringVar(
+		&ifMatch,
+		"if-match",
+		"",
+		"only write if the current value was written by this txID",
+	)
+	setCmd.PersistentFlags().BoolVar(
+		&ifAbsent,
+		"if-absent",
+		false,
+		"only write if there is no current value",
+	)
+	setCmd.PersistentFlags().DurationVar(
+		&setTTL,
+		"ttl",
+		0,
+		"remove the value after this duration (0 to keep until deleted)",
+	)
+}
+
 var setCmd = &cobra.Command{
 	Use:   "set [options] <space/key> <value>",
 	Short: "Writes a key-value pair for the given space",
@@ -54,6 +84,26 @@ $ spaces-cli set hello.avax/foo "hello world" --private-key-file=.different-key
 <<COMMENT
 error
 COMMENT
+
+# Only writes the key-value pair if the current value was written by
+# the given transaction (as reported by "spaces-cli resolve").
+$ spaces-cli set hello.avax/foo "hello world" --if-match=<txID>
+<<COMMENT
+success
+COMMENT
+
+# Only writes the key-value pair if the key does not exist yet.
+$ spaces-cli set hello.avax/bar "hello world" --if-absent
+<<COMMENT
+success
+COMMENT
+
+# Writes a key-value pair that is removed after 6 hours (values with
+# a shorter lifetime are cheaper to write).
+$ spaces-cli set hello.avax/session "hello world" --ttl 6h
+<<COMMENT
+success
+COMMENT
 `,
 	RunE: setFunc,
 }
@@ -69,11 +119,32 @@ func setFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	utx := &chain.SetTx{
+	var utx chain.UnsignedTransaction = &chain.SetTx{
 		BaseTx: &chain.BaseTx{},
 		Space:  space,
 		Key:    key,
 		Value:  val,
+		TTL:    uint64(setTTL / time.Second),
+	}
+	if len(ifMatch) > 0 || ifAbsent {
+		if setTTL > 0 {
+			return errors.New("--ttl cannot be combined with --if-match or --if-absent")
+		}
+		expected := ids.Empty
+		if len(ifMatch) > 0 {
+			expected, err = ids.FromString(ifMatch)
+			if err != nil {
+				return fmt.Errorf("%w: failed to parse txID", err)
+			}
+		}
+		utx = &chain.ConditionalSetTx{
+			BaseTx:   &chain.BaseTx{},
+			Space:    space,
+			Key:      key,
+			Value:    val,
+			IfMatch:  expected,
+			IfAbsent: ifAbsent,
+		}
 	}
 
 	cli := client.New(uri, requestTimeout)
@@ -82,9 +153,12 @@ func setFunc(cmd *cobra.Command, args []string) error {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	color.Green("set %s in %s", key, space)
 	return nil
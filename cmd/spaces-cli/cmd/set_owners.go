@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var setOwnersCmd = &cobra.Command{
+	Use:   "set-owners [options] <space> <threshold> [<address>...]",
+	Short: "Places a space under the control of an M-of-N owner set",
+	Long: `
+Issues "SetOwnersTx" to require <threshold> of the given
+addresses to sign any transaction that modifies the space.
+Omitting all addresses (with a threshold of 0) returns control
+to the single owner.
+
+If the space is already controlled by an owner set, collect
+signatures with "spaces-cli partial-sign" and issue the
+transaction with "spaces-cli combine" instead.
+
+# requires 2 of the 3 addresses to sign
+$ spaces-cli set-owners hello 2 0x... 0x... 0x...
+<<COMMENT
+success
+COMMENT
+`,
+	RunE: setOwnersFunc,
+}
+
+func setOwnersFunc(cmd *cobra.Command, args []string) error {
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	space, owners, threshold, err := getSetOwnersOp(args)
+	if err != nil {
+		return err
+	}
+
+	utx := &chain.SetOwnersTx{
+		BaseTx:    &chain.BaseTx{},
+		Space:     space,
+		Owners:    owners,
+		Threshold: threshold,
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithInfo(space))
+		opts = append(opts, client.WithBalance())
+	}
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("set %d-of-%d owners for %s", threshold, len(owners), space)
+	return nil
+}
+
+func getSetOwnersOp(args []string) (space string, owners []common.Address, threshold uint64, err error) {
+	if len(args) < 2 {
+		return "", nil, 0, fmt.Errorf("expected at least 2 arguments, got %d", len(args))
+	}
+
+	if err := parser.CheckContents(args[0]); err != nil {
+		return "", nil, 0, fmt.Errorf("%w: failed to parse space", err)
+	}
+	threshold, err = strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("%w: failed to parse threshold", err)
+	}
+	for _, arg := range args[2:] {
+		if !common.IsHexAddress(arg) {
+			return "", nil, 0, fmt.Errorf("invalid address %q", arg)
+		}
+		owners = append(owners, common.HexToAddress(arg))
+	}
+	return args[0], owners, threshold, nil
+}
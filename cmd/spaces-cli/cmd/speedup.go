@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var speedupCmd = &cobra.Command{
+	Use:   "speedup [options] <tx ID>",
+	Short: "Replaces a pending transaction with one paying a higher price",
+	RunE:  speedupFunc,
+}
+
+func speedupFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	txID, err := ids.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse tx ID", err)
+	}
+
+	priv, err := crypto.LoadECDSA(privateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	cli := client.New(uri, requestTimeout)
+	opts := []client.OpOption{client.WithPollTx()}
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	newID, _, err := client.SpeedUp(context.Background(), cli, txID, priv, withDryRun(opts)...)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	color.Green("replaced %s with %s", txID, newID)
+	return nil
+}
@@ -45,9 +45,12 @@ func transferFunc(cmd *cobra.Command, args []string) error {
 	if verbose {
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, withDryRun(opts)...); err != nil {
 		return err
 	}
+	if dryRun {
+		return nil
+	}
 
 	color.Green("transferred %d to %s", units, to.Hex())
 	return nil
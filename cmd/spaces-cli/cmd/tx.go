@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var txCmd = &cobra.Command{
+	Use:   "tx [options] <tx ID>",
+	Short: "Reads an accepted transaction",
+	RunE:  txFunc,
+}
+
+func txFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	txID, err := ids.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse tx ID", err)
+	}
+
+	cli := client.New(uri, requestTimeout)
+	tx, blkID, err := cli.GetTx(context.Background(), txID)
+	if err != nil {
+		return err
+	}
+	color.Green("included in block %s", blkID)
+	return ppTx(tx)
+}
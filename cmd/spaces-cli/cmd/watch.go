@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var (
+	watchBlocks      bool
+	watchTxs         []string
+	watchAddresses   []string
+	watchExpirations bool
+)
+
+func init() {
+	watchCmd.PersistentFlags().BoolVar(
+		&watchBlocks,
+		"blocks",
+		false,
+		"show every accepted block",
+	)
+	watchCmd.PersistentFlags().StringSliceVar(
+		&watchTxs,
+		"tx",
+		nil,
+		"show when these transactions are accepted",
+	)
+	watchCmd.PersistentFlags().StringSliceVar(
+		&watchAddresses,
+		"address",
+		nil,
+		"show balance changes of these addresses",
+	)
+	watchCmd.PersistentFlags().BoolVar(
+		&watchExpirations,
+		"expirations",
+		false,
+		"show every space that expires",
+	)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [space[/keyprefix]...] [options]",
+	Short: "Streams events from accepted blocks",
+	Long: `
+Streams changes to the given spaces (or keys starting with a prefix
+in a space) as blocks are accepted, until interrupted. Without any
+filters, shows every accepted block.
+
+$ spaces-cli watch hello hello/foo --address 0x... --expirations
+`,
+	RunE: watchFunc,
+}
+
+func watchFunc(cmd *cobra.Command, args []string) error {
+	s := &vm.Subscription{
+		Blocks:      watchBlocks,
+		Paths:       args,
+		Expirations: watchExpirations,
+	}
+	for _, tx := range watchTxs {
+		txID, err := ids.FromString(tx)
+		if err != nil {
+			return fmt.Errorf("%w: invalid tx ID %q", err, tx)
+		}
+		s.TxIDs = append(s.TxIDs, txID)
+	}
+	for _, addr := range watchAddresses {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid address %q", addr)
+		}
+		s.Addresses = append(s.Addresses, common.HexToAddress(addr))
+	}
+	if !s.Blocks && !s.Expirations && len(s.Paths)+len(s.TxIDs)+len(s.Addresses) == 0 {
+		s.Blocks = true
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	cli := client.New(uri, requestTimeout)
+	events, err := cli.Subscribe(ctx, s)
+	if err != nil {
+		return err
+	}
+	color.Blue("watching %s (press Ctrl+C to stop)", uri)
+	for ev := range events {
+		if err := client.PPEvent(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,7 +23,7 @@ import (
 var AirdropData []byte
 
 func init() {
-	log.Root().SetHandler(log.LvlFilterHandler(log.LvlDebug, log.StreamHandler(os.Stderr, log.LogfmtFormat())))
+	log.Root().SetHandler(vm.LevelHandler(log.LvlDebug, log.StreamHandler(os.Stderr, log.LogfmtFormat())))
 }
 
 var rootCmd = &cobra.Command{
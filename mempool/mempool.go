@@ -5,9 +5,12 @@ package mempool
 
 import (
 	"container/heap"
+	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ava-labs/spacesvm/chain"
 )
@@ -21,37 +24,83 @@ type Mempool struct {
 	maxHeap *txHeap
 	minHeap *txHeap
 
+	// basePrice is the block price transactions are prioritized for (see
+	// [chain.Priority]).
+	basePrice uint64
+
+	// ops is the pending transaction performing each replaceable operation
+	// (see [replacementKey]).
+	ops map[opKey]ids.ID
+
+	// maxSenderTxs is the number of transactions a sender can have in the
+	// mempool (no limit if <= 0).
+	maxSenderTxs int
+	balances     BalanceFunc
+	senders      map[common.Address]*senderTxs
+
 	// Pending is a channel of length one, which the mempool ensures has an item on
 	// it as long as there is an unissued transaction remaining in [txs]
 	Pending chan struct{}
 	// newTxs is an array of [Tx] that are ready to be gossiped.
 	newTxs []*chain.Transaction
+
+	metrics *metrics
 }
 
 // New creates a new [Mempool]. [maxSize] must be > 0 or else the
 // implementation may panic.
-func New(g *chain.Genesis, maxSize int) *Mempool {
+//
+// Each sender can have at most [maxSenderTxs] transactions in the mempool (no
+// limit if <= 0) and, unless [balances] is nil, enough balance to pay for
+// all of them.
+func New(g *chain.Genesis, maxSize int, maxSenderTxs int, balances BalanceFunc) *Mempool {
 	return &Mempool{
-		g:       g,
-		maxSize: maxSize,
-		maxHeap: newTxHeap(maxSize, false),
-		minHeap: newTxHeap(maxSize, true),
-		Pending: make(chan struct{}, 1),
+		g:            g,
+		maxSize:      maxSize,
+		maxHeap:      newTxHeap(maxSize, false),
+		minHeap:      newTxHeap(maxSize, true),
+		basePrice:    g.MinPrice,
+		ops:          make(map[opKey]ids.ID),
+		maxSenderTxs: maxSenderTxs,
+		balances:     balances,
+		senders:      make(map[common.Address]*senderTxs),
+		Pending:      make(chan struct{}, 1),
+		metrics:      newMetrics(),
 	}
 }
 
+// Add adds [tx] to the mempool. If a pending tx from the same sender performs
+// the same operation, [tx] replaces it if it pays at least
+// [ReplacementBumpPercent] more and is dropped otherwise. [tx] is also dropped
+// if its sender is at quota or can't pay for all of its pending txs (see
+// [Check]).
+//
+// If the mempool is full, the lowest paying tx is evicted.
 func (th *Mempool) Add(tx *chain.Transaction) bool {
 	txID := tx.ID()
-	price := tx.GetPrice()
 
 	th.mu.Lock()
 	defer th.mu.Unlock()
+	price := chain.Priority(tx.UnsignedTransaction, th.basePrice)
 
 	// Don't add duplicates
 	if th.maxHeap.Has(txID) {
 		return false
 	}
 
+	if err := th.check(tx); err != nil {
+		return false
+	}
+
+	// Replace the pending tx performing the same operation
+	if key, ok := replacementKey(tx); ok {
+		if prevID, ok := th.ops[key]; ok {
+			th.remove(prevID)
+			th.metrics.replacements.Inc()
+		}
+		th.ops[key] = txID
+	}
+
 	oldLen := th.maxHeap.Len()
 
 	// Optimistically add tx to mempool
@@ -67,17 +116,24 @@ func (th *Mempool) Add(tx *chain.Transaction) bool {
 		tx:    tx,
 		index: oldLen,
 	})
+	th.addSender(&txEntry{
+		id:    txID,
+		price: price,
+		tx:    tx,
+	})
 
 	// Remove the lowest paying tx
 	//
 	// Note: we do this after adding the new transaction in case it is the new
 	// lowest paying transaction
 	if th.maxHeap.Len() > th.maxSize {
-		t, _ := th.popMin()
+		t := th.remove(th.minHeap.items[0].id)
+		th.metrics.evictions.Inc()
 		if t.ID() == txID {
 			return false
 		}
 	}
+	th.metrics.size.Set(float64(th.maxHeap.Len()))
 
 	// When adding [tx] to the mempool make sure that there is an item in Pending
 	// to signal the VM to produce a block. Note: if the VM's buildStatus has already
@@ -89,6 +145,74 @@ func (th *Mempool) Add(tx *chain.Transaction) bool {
 	return true
 }
 
+// Check returns the reason [tx] would be dropped by [Add], if any:
+// [ErrReplacementUnderpriced] if it doesn't pay enough to replace a pending
+// tx, [ErrSenderQuotaExceeded] if its sender is at quota, or
+// [ErrInsufficientBalance] if its sender can't pay for all of its pending txs.
+func (th *Mempool) Check(tx *chain.Transaction) error {
+	th.mu.RLock()
+	defer th.mu.RUnlock()
+
+	return th.check(tx)
+}
+
+// check assumes the read lock is held.
+func (th *Mempool) check(tx *chain.Transaction) error {
+	prev, err := th.checkReplacement(tx)
+	if err != nil {
+		return err
+	}
+	return th.checkSender(tx, prev)
+}
+
+// checkReplacement returns the pending tx replaced by [tx], if any. It assumes
+// the read lock is held.
+func (th *Mempool) checkReplacement(tx *chain.Transaction) (*chain.Transaction, error) {
+	key, replaceable := replacementKey(tx)
+	if !replaceable {
+		return nil, nil
+	}
+	prevID, ok := th.ops[key]
+	if !ok || prevID == tx.ID() {
+		return nil, nil
+	}
+	prev, ok := th.maxHeap.Get(prevID)
+	if !ok {
+		return nil, nil
+	}
+	if !replaces(prev.tx, tx) {
+		return nil, fmt.Errorf("%w: %s must pay at least %d%% more than %s", ErrReplacementUnderpriced, tx.ID(), ReplacementBumpPercent, prevID)
+	}
+	return prev.tx, nil
+}
+
+// SetBasePrice reorders the mempool for blocks with price [base]. This is a
+// no-op unless [chain.Genesis.FeeMarketEnabled], as the priority of a
+// transaction doesn't depend on the block price otherwise.
+func (th *Mempool) SetBasePrice(base uint64) { // O(N)
+	if !th.g.FeeMarketEnabled {
+		return
+	}
+
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	if base == th.basePrice {
+		return
+	}
+	th.basePrice = base
+	heaps := []*txHeap{th.maxHeap, th.minHeap}
+	for _, s := range th.senders {
+		heaps = append(heaps, s.minHeap)
+	}
+	for _, h := range heaps {
+		for _, e := range h.items {
+			e.price = chain.Priority(e.tx.UnsignedTransaction, base)
+		}
+		heap.Init(h)
+	}
+}
+
 // Assumes there is non-zero items in [Mempool]
 func (th *Mempool) PeekMax() (*chain.Transaction, uint64) {
 	th.mu.RLock()
@@ -172,6 +296,22 @@ func (th *Mempool) Has(id ids.ID) bool {
 	return th.maxHeap.Has(id)
 }
 
+// Txs returns all transactions in the mempool, from the highest to the lowest
+// priority.
+func (th *Mempool) Txs() []*chain.Transaction {
+	th.mu.RLock()
+	entries := make([]*txEntry, len(th.maxHeap.items))
+	copy(entries, th.maxHeap.items)
+	th.mu.RUnlock()
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].price > entries[j].price })
+	txs := make([]*chain.Transaction, len(entries))
+	for i, e := range entries {
+		txs[i] = e.tx
+	}
+	return txs
+}
+
 // GetNewTxs returns the array of [newTxs] and replaces it with a new array.
 func (th *Mempool) NewTxs(maxUnits uint64) []*chain.Transaction {
 	th.mu.Lock()
@@ -213,6 +353,11 @@ func (th *Mempool) remove(id ids.ID) *chain.Transaction {
 		return nil
 	}
 	heap.Remove(th.maxHeap, maxEntry.index) // O(log N)
+	th.metrics.size.Set(float64(th.maxHeap.Len()))
+	if key, ok := replacementKey(maxEntry.tx); ok && th.ops[key] == id {
+		delete(th.ops, key)
+	}
+	th.removeSender(maxEntry.tx)
 
 	minEntry, ok := th.minHeap.Get(id) // O(1)
 	if !ok {
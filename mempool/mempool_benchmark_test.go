@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/ava-labs/spacesvm/chain"
@@ -78,7 +79,11 @@ func createTestMempool(
 				Space: string(spc),
 			},
 		}
-		sig, err := chain.Sign(tx.DigestHash(), priv)
+		dh, err := chain.DigestHash(tx.UnsignedTransaction)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -92,7 +97,7 @@ func createTestMempool(
 
 	sampleBlkIDs = ids.NewSet(sampleBlk)
 
-	mp = mempool.New(g, maxSize)
+	mp = mempool.New(g, maxSize, 0, nil)
 
 	b.StartTimer()
 	for _, tx := range txs {
@@ -102,3 +107,38 @@ func createTestMempool(
 	}
 	return mp, sampleBlkIDs
 }
+
+// BenchmarkMempoolAddSenders adds txs from a few senders (one of them sending
+// most txs) to a mempool with per-sender quotas and balance checks.
+func BenchmarkMempoolAddSenders(b *testing.B) {
+	b.StopTimer()
+
+	g := chain.DefaultGenesis()
+	privs := make([]*ecdsa.PrivateKey, 50)
+	for i := range privs {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			b.Fatal(err)
+		}
+		privs[i] = priv
+	}
+	txs := make([]*chain.Transaction, 10000)
+	for i := range txs {
+		// Half of the txs are sent by the first sender
+		priv := privs[0]
+		if i%2 == 1 {
+			priv = privs[i%len(privs)]
+		}
+		txs[i] = newTransferTx(b, g, priv, uint64(i%100+1), uint64(i+1))
+	}
+	balances := func(common.Address) (uint64, error) { return 1 << 62, nil }
+
+	for i := 0; i < b.N; i++ {
+		mp := mempool.New(g, 2000, 100, balances)
+		b.StartTimer()
+		for _, tx := range txs {
+			mp.Add(tx)
+		}
+		b.StopTimer()
+	}
+}
@@ -4,10 +4,14 @@
 package mempool_test
 
 import (
+	"crypto/ecdsa"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/spacesvm/chain"
 	"github.com/ava-labs/spacesvm/mempool"
@@ -15,7 +19,11 @@ import (
 
 func TestMempool(t *testing.T) {
 	g := chain.DefaultGenesis()
-	txm := mempool.New(g, 3)
+	txm := mempool.New(g, 3, 0, nil)
+	reg := prometheus.NewRegistry()
+	if err := txm.RegisterMetrics(reg); err != nil {
+		t.Fatal(err)
+	}
 	priv, err := crypto.GenerateKey()
 	if err != nil {
 		t.Fatal(err)
@@ -54,4 +62,310 @@ func TestMempool(t *testing.T) {
 	if length := txm.Len(); length != 3 {
 		t.Fatalf("length expected 3, got %d", length)
 	}
+	txs := txm.Txs()
+	if len(txs) != 3 {
+		t.Fatalf("txs expected 3, got %d", len(txs))
+	}
+	for i, price := range []uint64{250, 220, 200} {
+		if p := txs[i].GetPrice(); p != price {
+			t.Fatalf("#%d: price expected %d, got %d", i, price, p)
+		}
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]float64{}
+	for _, f := range families {
+		m := f.GetMetric()[0]
+		values[f.GetName()] = m.GetGauge().GetValue() + m.GetCounter().GetValue()
+	}
+	if values["mempool_size"] != 3 || values["mempool_evictions"] != 1 {
+		t.Fatalf("unexpected metrics %v", values)
+	}
+}
+
+func TestMempoolFeeMarket(t *testing.T) {
+	g := chain.DefaultGenesis()
+	g.FeeMarketEnabled = true
+	txm := mempool.New(g, 4, 0, nil)
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// [MaxPrice] and [PriorityTip] of each tx
+	fees := [][2]uint64{{100, 1}, {12, 5}, {20, 3}, {30, 4}}
+	txs := make([]*chain.Transaction, len(fees))
+	for i, fee := range fees {
+		tx := &chain.Transaction{
+			UnsignedTransaction: &chain.TransferTx{
+				BaseTx: &chain.BaseTx{
+					MaxPrice:    fee[0],
+					PriorityTip: fee[1],
+				},
+				Units: uint64(i + 1),
+			},
+		}
+		dh, err := chain.DigestHash(tx.UnsignedTransaction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Signature = sig
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		if !txm.Add(tx) {
+			t.Fatalf("tx %s was not added", tx.ID())
+		}
+		txs[i] = tx
+	}
+
+	// Ordered by effective tip (+1) at the base price
+	checkOrder := func(base uint64, order []int, priorities []uint64) {
+		t.Helper()
+		txm.SetBasePrice(base)
+		all := txm.Txs()
+		for i, j := range order {
+			if all[i].ID() != txs[j].ID() {
+				t.Fatalf("base %d: #%d expected tx %d", base, i, j)
+			}
+		}
+		if tx, priority := txm.PeekMax(); tx.ID() != txs[order[0]].ID() || priority != priorities[0] {
+			t.Fatalf("base %d: unexpected max priority %d", base, priority)
+		}
+		if tx, priority := txm.PeekMin(); tx.ID() != txs[order[len(order)-1]].ID() || priority != priorities[1] {
+			t.Fatalf("base %d: unexpected min priority %d", base, priority)
+		}
+	}
+	checkOrder(1, []int{1, 3, 2, 0}, []uint64{6, 2})
+	checkOrder(10, []int{3, 2, 1, 0}, []uint64{5, 2})
+	// [txs[1]] can't afford the base price anymore
+	checkOrder(15, []int{3, 2, 0, 1}, []uint64{5, 0})
+}
+
+func TestMempoolReplacement(t *testing.T) {
+	g := chain.DefaultGenesis()
+	txm := mempool.New(g, 10, 0, nil)
+	reg := prometheus.NewRegistry()
+	if err := txm.RegisterMetrics(reg); err != nil {
+		t.Fatal(err)
+	}
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv2, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx := func(priv *ecdsa.PrivateKey, price uint64, value string) *chain.Transaction {
+		tx := &chain.Transaction{
+			UnsignedTransaction: &chain.SetTx{
+				BaseTx: &chain.BaseTx{Price: price},
+				Space:  "foo",
+				Key:    "bar",
+				Value:  []byte(value),
+			},
+		}
+		dh, err := chain.DigestHash(tx.UnsignedTransaction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Signature = sig
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	orig := newTx(priv, 100, "a")
+	if !txm.Add(orig) {
+		t.Fatal("tx was not added")
+	}
+	// The same operation by another sender is not a replacement
+	if !txm.Add(newTx(priv2, 50, "a")) {
+		t.Fatal("tx from other sender was not added")
+	}
+
+	underpriced := newTx(priv, 109, "b")
+	if err := txm.Check(underpriced); !errors.Is(err, mempool.ErrReplacementUnderpriced) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if txm.Add(underpriced) {
+		t.Fatal("underpriced replacement was added")
+	}
+	if !txm.Has(orig.ID()) || txm.Len() != 2 {
+		t.Fatal("original tx was replaced")
+	}
+
+	replacement := newTx(priv, mempool.Bump(100), "b")
+	if err := txm.Check(replacement); err != nil {
+		t.Fatal(err)
+	}
+	if !txm.Add(replacement) {
+		t.Fatal("replacement was not added")
+	}
+	if txm.Has(orig.ID()) || !txm.Has(replacement.ID()) || txm.Len() != 2 {
+		t.Fatal("original tx was not replaced")
+	}
+
+	// Once the replacement leaves the mempool, any price is accepted again
+	if max, _ := txm.PopMax(); max.ID() != replacement.ID() {
+		t.Fatalf("unexpected tx %s", max.ID())
+	}
+	if !txm.Add(newTx(priv, 1, "c")) {
+		t.Fatal("tx was not added")
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() == "mempool_replacements" && f.GetMetric()[0].GetCounter().GetValue() != 1 {
+			t.Fatalf("replacements expected 1, got %v", f.GetMetric()[0].GetCounter().GetValue())
+		}
+	}
+}
+
+func TestBump(t *testing.T) {
+	for v, bumped := range map[uint64]uint64{0: 1, 1: 2, 9: 10, 10: 11, 100: 110, 101: 112} {
+		if b := mempool.Bump(v); b != bumped {
+			t.Fatalf("bump of %d expected %d, got %d", v, bumped, b)
+		}
+	}
+}
+
+func newTransferTx(t testing.TB, g *chain.Genesis, priv *ecdsa.PrivateKey, price uint64, units uint64) *chain.Transaction {
+	tx := &chain.Transaction{
+		UnsignedTransaction: &chain.TransferTx{
+			BaseTx: &chain.BaseTx{Price: price},
+			Units:  units,
+		},
+	}
+	dh, err := chain.DigestHash(tx.UnsignedTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.Sign(dh, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Signature = sig
+	if err := tx.Init(g); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestMempoolSenderQuota(t *testing.T) {
+	g := chain.DefaultGenesis()
+	txm := mempool.New(g, 4, 2, nil)
+	spammer, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Senders at quota can't add txs, even if the mempool is not full
+	spam := make([]*chain.Transaction, 0, 2)
+	for i, price := range []uint64{10, 20} {
+		tx := newTransferTx(t, g, spammer, price, uint64(i+1))
+		if !txm.Add(tx) {
+			t.Fatalf("tx %d was not added", i)
+		}
+		spam = append(spam, tx)
+	}
+	tx := newTransferTx(t, g, spammer, 50, 3)
+	if err := txm.Check(tx); !errors.Is(err, mempool.ErrSenderQuotaExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if txm.Add(tx) {
+		t.Fatal("tx of sender at quota was added")
+	}
+	if txm.Len() != 2 {
+		t.Fatalf("unexpected mempool size %d", txm.Len())
+	}
+
+	// Other senders can still fill the mempool
+	low := newTransferTx(t, g, priv, 5, 1)
+	if !txm.Add(low) {
+		t.Fatal("tx of sender under quota was not added")
+	}
+	if !txm.Add(newTransferTx(t, g, priv, 6, 2)) {
+		t.Fatal("tx of sender under quota was not added")
+	}
+
+	// Once full, the lowest paying tx is evicted
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !txm.Add(newTransferTx(t, g, other, 7, 1)) {
+		t.Fatal("tx was not added")
+	}
+	if txm.Has(low.ID()) || !txm.Has(spam[0].ID()) || !txm.Has(spam[1].ID()) || txm.Len() != 4 {
+		t.Fatal("lowest paying tx was not evicted")
+	}
+
+	// Senders at quota can't add txs to a full mempool either
+	if err := txm.Check(tx); !errors.Is(err, mempool.ErrSenderQuotaExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Once a tx of a sender is removed, it can add another
+	txm.Remove(spam[0].ID())
+	if !txm.Add(tx) {
+		t.Fatal("tx of sender under quota was not added")
+	}
+}
+
+func TestMempoolSenderBalance(t *testing.T) {
+	g := chain.DefaultGenesis()
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	tx0 := newTransferTx(t, g, priv, 10, 1)
+	tx1 := newTransferTx(t, g, priv, 10, 2)
+	tx2 := newTransferTx(t, g, priv, 10, 3)
+
+	// [sender] can only pay for 2 of the txs
+	balance := tx0.FeeUnits(g)*10 + tx1.FeeUnits(g)*10
+	txm := mempool.New(g, 10, 0, func(addr common.Address) (uint64, error) {
+		if addr != sender {
+			t.Fatalf("unexpected address %s", addr)
+		}
+		return balance, nil
+	})
+	for _, tx := range []*chain.Transaction{tx0, tx1} {
+		if !txm.Add(tx) {
+			t.Fatalf("tx %s was not added", tx.ID())
+		}
+	}
+	if err := txm.Check(tx2); !errors.Is(err, mempool.ErrInsufficientBalance) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if txm.Add(tx2) {
+		t.Fatal("tx exceeding balance was added")
+	}
+
+	// Removed txs no longer count against the balance
+	txm.Remove(tx0.ID())
+	if !txm.Add(tx2) {
+		t.Fatal("tx was not added")
+	}
 }
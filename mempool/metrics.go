@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "mempool"
+
+type metrics struct {
+	size         prometheus.Gauge
+	evictions    prometheus.Counter
+	replacements prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		size: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "size",
+			Help:      "number of transactions in the mempool",
+		}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "evictions",
+			Help:      "number of lowest paying transactions evicted from a full mempool",
+		}),
+		replacements: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "replacements",
+			Help:      "number of pending transactions replaced by a higher paying transaction performing the same operation",
+		}),
+	}
+}
+
+// RegisterMetrics registers the metrics of the mempool with [reg].
+func (th *Mempool) RegisterMetrics(reg prometheus.Registerer) error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(th.metrics.size),
+		reg.Register(th.metrics.evictions),
+		reg.Register(th.metrics.replacements),
+	)
+	return errs.Err
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// ReplacementBumpPercent is how much more (in percent) a transaction must pay
+// to replace a pending transaction from the same sender performing the same
+// operation.
+const ReplacementBumpPercent = 10
+
+var ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+
+// opKey identifies the operation performed by a transaction. Only one pending
+// transaction per [opKey] is kept in the mempool.
+type opKey struct {
+	sender common.Address
+	op     string
+}
+
+// replacementKey returns the [opKey] of [tx] and false if [tx] can't be
+// replaced.
+func replacementKey(tx *chain.Transaction) (opKey, bool) {
+	var op string
+	switch utx := tx.UnsignedTransaction.(type) {
+	case *chain.SetTx:
+		op = chain.Set + "/" + utx.Space + "/" + utx.Key
+	case *chain.DeleteTx:
+		op = chain.Delete + "/" + utx.Space + "/" + utx.Key
+	case *chain.ClaimTx:
+		op = chain.Claim + "/" + utx.Space
+	case *chain.LifelineTx:
+		op = chain.Lifeline + "/" + utx.Space
+	case *chain.MoveTx:
+		op = chain.Move + "/" + utx.Space
+	default:
+		return opKey{}, false
+	}
+	return opKey{sender: tx.Sender(), op: op}, true
+}
+
+// Bump returns [v] increased by [ReplacementBumpPercent] (and by at least 1).
+func Bump(v uint64) uint64 {
+	bumped := (v*(100+ReplacementBumpPercent) + 99) / 100
+	if bumped == v {
+		return v + 1
+	}
+	return bumped
+}
+
+// replaces returns true if [next] pays at least [ReplacementBumpPercent] more
+// than [prev].
+func replaces(prev *chain.Transaction, next *chain.Transaction) bool {
+	if prev.GetMaxPrice() > 0 {
+		return bumped(prev.GetMaxPrice(), next.GetMaxPrice()) &&
+			bumped(prev.GetPriorityTip(), next.GetPriorityTip())
+	}
+	return bumped(prev.GetPrice(), next.GetPrice())
+}
+
+func bumped(prev uint64, next uint64) bool {
+	return next*100 >= prev*(100+ReplacementBumpPercent)
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"container/heap"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+var (
+	ErrSenderQuotaExceeded = errors.New("sender has too many pending transactions")
+	ErrInsufficientBalance = errors.New("insufficient balance to pay for pending transactions")
+)
+
+// BalanceFunc returns the balance of [addr], which must cover the fees of all
+// the pending transactions sent by [addr].
+type BalanceFunc func(addr common.Address) (uint64, error)
+
+// senderTxs are the pending transactions of a sender.
+type senderTxs struct {
+	// minHeap orders the transactions of the sender by priority, so that the
+	// lowest paying one can be evicted first.
+	minHeap *txHeap
+	// fees is the sum of the [chain.MaxFee] of the transactions.
+	fees uint64
+}
+
+func (th *Mempool) maxFee(tx *chain.Transaction) uint64 {
+	return chain.MaxFee(tx.UnsignedTransaction, tx.FeeUnits(th.g))
+}
+
+// checkSender returns an error if adding [tx] (and removing [prev], if not
+// nil) would exceed the quota or the balance of its sender. It assumes the
+// read lock is held.
+func (th *Mempool) checkSender(tx *chain.Transaction, prev *chain.Transaction) error {
+	sender := tx.Sender()
+	var (
+		pending int
+		fees    uint64
+	)
+	if s, ok := th.senders[sender]; ok {
+		pending, fees = s.minHeap.Len(), s.fees
+	}
+
+	// Senders at quota can only replace their own transactions
+	if prev == nil && th.maxSenderTxs > 0 && pending >= th.maxSenderTxs {
+		return ErrSenderQuotaExceeded
+	}
+
+	if th.balances == nil {
+		return nil
+	}
+	fees += th.maxFee(tx)
+	if prev != nil {
+		fees -= th.maxFee(prev)
+	}
+	balance, err := th.balances(sender)
+	if err != nil {
+		return err
+	}
+	if fees > balance {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// addSender tracks [entry] as a transaction of its sender. It assumes the write
+// lock is held.
+func (th *Mempool) addSender(entry *txEntry) {
+	sender := entry.tx.Sender()
+	s, ok := th.senders[sender]
+	if !ok {
+		s = &senderTxs{minHeap: newTxHeap(1, true)}
+		th.senders[sender] = s
+	}
+	entry.index = s.minHeap.Len()
+	heap.Push(s.minHeap, entry)
+	s.fees += th.maxFee(entry.tx)
+}
+
+// removeSender stops tracking [tx] as a transaction of its sender. It assumes
+// the write lock is held.
+func (th *Mempool) removeSender(tx *chain.Transaction) {
+	sender := tx.Sender()
+	s, ok := th.senders[sender]
+	if !ok {
+		return
+	}
+	entry, ok := s.minHeap.Get(tx.ID())
+	if !ok {
+		return
+	}
+	heap.Remove(s.minHeap, entry.index)
+	s.fees -= th.maxFee(tx)
+	if s.minHeap.Len() == 0 {
+		delete(th.senders, sender)
+	}
+}
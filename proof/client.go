@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proof
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var _ client.Client = &Client{}
+
+// Client is a [client.Client] that verifies the results of [Info], [Balance]
+// and [Resolve] against the state root of the block each proof was made at.
+//
+// The state root is read from the block, which is only checked to match its
+// ID. Callers that don't trust the node for the ID of the last accepted block
+// should compare [LastBlockID] with another source.
+type Client struct {
+	client.Client
+
+	l           sync.Mutex
+	lastBlockID ids.ID
+	lastRoot    ids.ID
+}
+
+// NewClient wraps [cli] so that it verifies state proofs.
+func NewClient(cli client.Client) *Client {
+	return &Client{Client: cli}
+}
+
+// LastBlockID returns the ID of the block the last verified proof was made
+// at.
+func (c *Client) LastBlockID() ids.ID {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return c.lastBlockID
+}
+
+// stateRoot returns the state root of the block [p] was made at.
+func (c *Client) stateRoot(ctx context.Context, p *chain.StateProof) (ids.ID, error) {
+	if p == nil {
+		return ids.Empty, ErrInvalidProof
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	if p.BlockID != ids.Empty && p.BlockID == c.lastBlockID {
+		return c.lastRoot, nil
+	}
+	blk, err := c.Client.GetBlockByID(ctx, p.BlockID)
+	if err != nil {
+		return ids.Empty, err
+	}
+	c.lastBlockID, c.lastRoot = p.BlockID, blk.StateRoot
+	return blk.StateRoot, nil
+}
+
+func (c *Client) Info(ctx context.Context, space string) (*chain.SpaceInfo, []*chain.KeyValueMeta, error) {
+	resp, err := c.Client.ProveInfo(ctx, space)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err := c.stateRoot(ctx, resp.Proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := Info(root, space, resp.Info, resp.Proof); err != nil {
+		return nil, nil, err
+	}
+	return resp.Info, resp.Values, nil
+}
+
+func (c *Client) Balance(ctx context.Context, addr common.Address) (uint64, error) {
+	resp, err := c.Client.ProveBalance(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	root, err := c.stateRoot(ctx, resp.Proof)
+	if err != nil {
+		return 0, err
+	}
+	if err := Balance(root, addr, resp.Balance, resp.Proof); err != nil {
+		return 0, err
+	}
+	return resp.Balance, nil
+}
+
+func (c *Client) Resolve(ctx context.Context, path string) (bool, []byte, *chain.ValueMeta, error) {
+	resp, err := c.Client.ProveResolve(ctx, path)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	root, err := c.stateRoot(ctx, resp.Proof)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	if err := Resolve(
+		root, path, resp.Exists, resp.Value, resp.ValueMeta, resp.Proof, uint64(time.Now().Unix()),
+	); err != nil {
+		return false, nil, nil, err
+	}
+	if !resp.Exists {
+		return false, nil, nil, nil
+	}
+	// Content-addressed keys don't need to be checked separately because the
+	// value must match [chain.ValueMeta.ValueHash]
+	return true, resp.Value, resp.ValueMeta, nil
+}
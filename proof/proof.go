@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package proof verifies the state proofs returned by the "spacesvm" API.
+package proof
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/smt"
+)
+
+var (
+	ErrInvalidProof = smt.ErrInvalidProof
+	ErrRootMismatch = errors.New("proof is not against expected root")
+)
+
+// Balance checks that [p] proves that [addr] has [bal] at [root].
+func Balance(root ids.ID, addr common.Address, bal uint64, p *chain.StateProof) error {
+	if err := checkRoot(root, p); err != nil {
+		return err
+	}
+	path := chain.BalancePath(addr)
+	vh := chain.BalanceValueHash(bal)
+	err := smt.Verify(common.Hash(root), path, &vh, p.State)
+	if err != nil && bal == 0 {
+		// Accounts without a balance may not be in the state at all
+		err = smt.Verify(common.Hash(root), path, nil, p.State)
+	}
+	return err
+}
+
+// Info checks that [p] proves that [space] has [info] at [root] (or that it
+// does not exist if [info] is nil).
+func Info(root ids.ID, space string, info *chain.SpaceInfo, p *chain.StateProof) error {
+	if err := checkRoot(root, p); err != nil {
+		return err
+	}
+	if info == nil {
+		return smt.Verify(common.Hash(root), chain.InfoPath([]byte(space)), nil, p.State)
+	}
+	b, err := chain.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return verifyInfo(root, []byte(space), b, p)
+}
+
+// Resolve checks that [p] proves the result of resolving [path] at [root]:
+//   - If [exists], the value at [path] is [value] with [vmeta]
+//   - If not and [vmeta] is not nil, the value at [path] expired before [now]
+//   - Otherwise, there is no value at [path]
+func Resolve(
+	root ids.ID,
+	path string,
+	exists bool,
+	value []byte,
+	vmeta *chain.ValueMeta,
+	p *chain.StateProof,
+	now uint64,
+) error {
+	space, key, err := parser.ResolvePath(path)
+	if err != nil {
+		return err
+	}
+	if err := checkRoot(root, p); err != nil {
+		return err
+	}
+	if exists && vmeta == nil {
+		return fmt.Errorf("%w: value meta missing", ErrInvalidProof)
+	}
+
+	// Ensure the space is (or is not) in the state
+	if len(p.Info) == 0 {
+		if err := smt.Verify(common.Hash(root), chain.InfoPath([]byte(space)), nil, p.State); err != nil {
+			return err
+		}
+		if vmeta != nil {
+			return fmt.Errorf("%w: space missing", ErrInvalidProof)
+		}
+		return nil
+	}
+	if err := verifyInfo(root, []byte(space), p.Info, p); err != nil {
+		return err
+	}
+
+	// Ensure the value is (or is not) in the space
+	vpath := chain.ValuePath([]byte(key))
+	if vmeta == nil {
+		return smt.Verify(p.SpaceRoot, vpath, nil, p.Value)
+	}
+	b, err := chain.MarshalValueMeta(vmeta)
+	if err != nil {
+		return err
+	}
+	vh := chain.ValueMetaHash(b)
+	if err := smt.Verify(p.SpaceRoot, vpath, &vh, p.Value); err != nil {
+		return err
+	}
+	if !exists {
+		if !vmeta.Expired(now) {
+			return fmt.Errorf("%w: value has not expired", ErrInvalidProof)
+		}
+		return nil
+	}
+	if h := crypto.Keccak256Hash(value); h != vmeta.ValueHash {
+		return fmt.Errorf("%w: value hash expected %s got %s", ErrInvalidProof, vmeta.ValueHash, h)
+	}
+	return nil
+}
+
+func checkRoot(root ids.ID, p *chain.StateProof) error {
+	if p == nil {
+		return fmt.Errorf("%w: proof missing", ErrInvalidProof)
+	}
+	if p.Root != root {
+		return fmt.Errorf("%w: expected %s got %s", ErrRootMismatch, root, p.Root)
+	}
+	return nil
+}
+
+func verifyInfo(root ids.ID, space []byte, info []byte, p *chain.StateProof) error {
+	vh := chain.InfoValueHash(info, p.SpaceRoot)
+	return smt.Verify(common.Hash(root), chain.InfoPath(space), &vh, p.State)
+}
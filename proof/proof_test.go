@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proof
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+func TestProofs(t *testing.T) {
+	t.Parallel()
+
+	db := chain.NewStateDB(memdb.New())
+	spc, k, v := "foo", "bar", []byte("baz")
+	info := &chain.SpaceInfo{Owner: common.Address{0x1}, Expiry: 100, RawSpace: ids.ShortID{0x1}}
+	if err := chain.PutSpaceInfo(db, []byte(spc), info, 0); err != nil {
+		t.Fatal(err)
+	}
+	vmeta := &chain.ValueMeta{Size: uint64(len(v)), TxID: ids.ID{0x2}, ValueHash: crypto.Keccak256Hash(v)}
+	if err := chain.PutSpaceKey(db, []byte(spc), []byte(k), vmeta); err != nil {
+		t.Fatal(err)
+	}
+	expired := &chain.ValueMeta{TxID: ids.ID{0x3}, Expiry: 10}
+	if err := chain.PutSpaceKey(db, []byte(spc), []byte("old"), expired); err != nil {
+		t.Fatal(err)
+	}
+	addr := common.Address{0x1}
+	if err := chain.SetBalance(db, addr, 10); err != nil {
+		t.Fatal(err)
+	}
+	root, err := chain.CommitState(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Balances
+	p, err := chain.ProveBalance(db, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Balance(root, addr, 10, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := Balance(root, addr, 11, p); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := Balance(ids.ID{0x1}, addr, 10, p); !errors.Is(err, ErrRootMismatch) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	p, err = chain.ProveBalance(db, common.Address{0x2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Balance(root, common.Address{0x2}, 0, p); err != nil {
+		t.Fatal(err)
+	}
+
+	// Space info
+	p, err = chain.ProveSpace(db, []byte(spc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Info(root, spc, info, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := Info(root, spc, nil, p); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	modified := *info
+	modified.Owner = common.Address{0x2}
+	if err := Info(root, spc, &modified, p); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	p, err = chain.ProveSpace(db, []byte("missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Info(root, "missing", nil, p); err != nil {
+		t.Fatal(err)
+	}
+
+	// Values
+	tests := []struct {
+		path   string
+		exists bool
+		value  []byte
+		vmeta  *chain.ValueMeta
+		err    error
+	}{
+		{path: "foo/bar", exists: true, value: v, vmeta: vmeta},
+		{path: "foo/bar", exists: true, value: []byte("other"), vmeta: vmeta, err: ErrInvalidProof},
+		{path: "foo/bar", err: ErrInvalidProof},
+		{path: "foo/old", vmeta: expired},
+		{path: "foo/old", err: ErrInvalidProof},
+		{path: "foo/missing"},
+		{path: "foo/missing", exists: true, value: v, vmeta: vmeta, err: ErrInvalidProof},
+		{path: "missing/bar"},
+		{path: "missing/bar", exists: true, value: v, vmeta: vmeta, err: ErrInvalidProof},
+	}
+	for i, tv := range tests {
+		space, key, err := parser.ResolvePath(tv.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := chain.ProveValue(db, []byte(space), []byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Resolve(root, tv.path, tv.exists, tv.value, tv.vmeta, p, 20)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: unexpected error %v, expected %v", i, err, tv.err)
+		}
+	}
+}
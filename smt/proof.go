@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package smt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidProof = errors.New("invalid proof")
+
+// Leaf is a leaf of the tree.
+type Leaf struct {
+	Path      common.Hash `json:"path"`
+	ValueHash common.Hash `json:"valueHash"`
+}
+
+// Proof proves that a leaf is (or is not) in the tree.
+//
+// The path to a leaf ends at the leaf itself, at an empty subtree, or at a
+// different leaf (which must then be the only leaf in the subtree the path
+// would be in).
+type Proof struct {
+	// Siblings are the hashes of the siblings of the nodes on the path, from
+	// the root down.
+	Siblings []common.Hash `json:"siblings"`
+
+	// Leaf is the leaf the path ends at (if any).
+	Leaf *Leaf `json:"leaf,omitempty"`
+}
+
+// Prove returns a proof for the leaf at [path] in the tree stored under
+// [prefix] in [db].
+func Prove(db database.KeyValueReader, prefix []byte, path common.Hash) (*Proof, error) {
+	p := &Proof{Siblings: []common.Hash{}}
+	for depth := 0; depth <= Depth; depth++ {
+		n, err := getNode(db, prefix, depth, path)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case n == nil:
+			return p, nil
+		case n.typ == leafNode:
+			p.Leaf = &Leaf{Path: n.path, ValueHash: n.valueHash}
+			return p, nil
+		case Bit(path, depth) == 0:
+			p.Siblings = append(p.Siblings, n.right)
+		default:
+			p.Siblings = append(p.Siblings, n.left)
+		}
+	}
+	return nil, fmt.Errorf("%w: path exceeds depth", ErrInvalidNode)
+}
+
+// Prove returns a proof for the leaf at [path].
+func (t *Tree) Prove(path common.Hash) (*Proof, error) {
+	return Prove(t.db, t.prefix, path)
+}
+
+// Verify checks that [p] proves that the tree with [root] has a leaf with
+// [valueHash] at [path] or, if [valueHash] is nil, that it has no leaf at
+// [path].
+func Verify(root common.Hash, path common.Hash, valueHash *common.Hash, p *Proof) error {
+	if p == nil || len(p.Siblings) > Depth {
+		return ErrInvalidProof
+	}
+	depth := len(p.Siblings)
+
+	h := EmptyRoot
+	switch {
+	case valueHash != nil:
+		if p.Leaf == nil || p.Leaf.Path != path || p.Leaf.ValueHash != *valueHash {
+			return fmt.Errorf("%w: leaf not included", ErrInvalidProof)
+		}
+		h = LeafHash(path, *valueHash)
+	case p.Leaf != nil:
+		// The path must end at a different leaf in the same subtree
+		if p.Leaf.Path == path {
+			return fmt.Errorf("%w: leaf included", ErrInvalidProof)
+		}
+		for i := 0; i < depth; i++ {
+			if Bit(p.Leaf.Path, i) != Bit(path, i) {
+				return fmt.Errorf("%w: leaf not on path", ErrInvalidProof)
+			}
+		}
+		h = LeafHash(p.Leaf.Path, p.Leaf.ValueHash)
+	}
+	for i := depth - 1; i >= 0; i-- {
+		if Bit(path, i) == 0 {
+			h = InternalHash(h, p.Siblings[i])
+		} else {
+			h = InternalHash(p.Siblings[i], h)
+		}
+	}
+	if h != root {
+		return fmt.Errorf("%w: expected root %s, computed %s", ErrInvalidProof, root, h)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package smt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestProof(t *testing.T) {
+	t.Parallel()
+
+	tr := New(memdb.New(), []byte{0x1})
+
+	// Empty tree
+	missing := crypto.Keccak256Hash([]byte("missing"))
+	p, err := tr.Prove(missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(EmptyRoot, missing, nil, p); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, values := testLeaves(32)
+	for i := range paths {
+		if err := tr.Update(paths[i], values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := tr.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Inclusion
+	for i := range paths {
+		p, err := tr.Prove(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(root, paths[i], &values[i], p); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if err := Verify(root, paths[i], &common.Hash{}, p); !errors.Is(err, ErrInvalidProof) {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		if err := Verify(root, paths[i], nil, p); !errors.Is(err, ErrInvalidProof) {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+	}
+
+	// Exclusion (both at empty subtrees and at other leaves)
+	for i := 0; i < 32; i++ {
+		path := crypto.Keccak256Hash([]byte{byte(i), 0x1})
+		p, err := tr.Prove(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(root, path, nil, p); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if err := Verify(root, path, &values[0], p); !errors.Is(err, ErrInvalidProof) {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+	}
+
+	// A proof for one path can't be used for another
+	p, err = tr.Prove(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(root, paths[len(paths)-1], nil, p); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
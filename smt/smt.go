@@ -0,0 +1,243 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package smt implements a database-backed sparse Merkle tree.
+//
+// Leaves are addressed by a 256-bit path. A subtree that contains a single
+// leaf is represented by that leaf, so a leaf is stored at the shallowest
+// depth at which its path is unique. This keeps the tree small while making
+// its shape (and root) depend only on its contents, not on the order of
+// updates.
+package smt
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// Depth is the number of bits in a path.
+	Depth = common.HashLength * 8
+
+	leafNode     byte = 0x0
+	internalNode byte = 0x1
+
+	nodeLen = 1 + 2*common.HashLength
+)
+
+var ErrInvalidNode = errors.New("invalid node")
+
+// EmptyRoot is the root of a tree without any leaves. It is also used as the
+// hash of any empty subtree.
+var EmptyRoot = common.Hash{}
+
+type node struct {
+	typ byte
+
+	// leaf
+	path      common.Hash
+	valueHash common.Hash
+
+	// internal
+	left  common.Hash
+	right common.Hash
+}
+
+func (n *node) bytes() []byte {
+	b := make([]byte, nodeLen)
+	b[0] = n.typ
+	if n.typ == leafNode {
+		copy(b[1:], n.path[:])
+		copy(b[1+common.HashLength:], n.valueHash[:])
+	} else {
+		copy(b[1:], n.left[:])
+		copy(b[1+common.HashLength:], n.right[:])
+	}
+	return b
+}
+
+func (n *node) hash() common.Hash {
+	if n == nil {
+		return EmptyRoot
+	}
+	return crypto.Keccak256Hash(n.bytes())
+}
+
+func parseNode(b []byte) (*node, error) {
+	if len(b) != nodeLen {
+		return nil, ErrInvalidNode
+	}
+	n := &node{typ: b[0]}
+	switch n.typ {
+	case leafNode:
+		copy(n.path[:], b[1:])
+		copy(n.valueHash[:], b[1+common.HashLength:])
+	case internalNode:
+		copy(n.left[:], b[1:])
+		copy(n.right[:], b[1+common.HashLength:])
+	default:
+		return nil, ErrInvalidNode
+	}
+	return n, nil
+}
+
+// LeafHash is the hash of the leaf storing [valueHash] at [path].
+func LeafHash(path common.Hash, valueHash common.Hash) common.Hash {
+	return (&node{typ: leafNode, path: path, valueHash: valueHash}).hash()
+}
+
+// InternalHash is the hash of an internal node with the given children.
+func InternalHash(left common.Hash, right common.Hash) common.Hash {
+	return (&node{typ: internalNode, left: left, right: right}).hash()
+}
+
+// Bit returns the [i]th most significant bit of [path].
+func Bit(path common.Hash, i int) byte {
+	return (path[i/8] >> (7 - uint(i%8))) & 1
+}
+
+// Tree is a sparse Merkle tree whose nodes are stored in a database under a
+// fixed prefix. Nodes are keyed by their position, so updates overwrite
+// nodes in place and no stale nodes are left behind.
+type Tree struct {
+	db     database.KeyValueReaderWriterDeleter
+	prefix []byte
+}
+
+// New returns the tree stored under [prefix] in [db].
+func New(db database.KeyValueReaderWriterDeleter, prefix []byte) *Tree {
+	return &Tree{db: db, prefix: prefix}
+}
+
+// [prefix] + [depth] + [first [depth] bits of path]
+func nodeKey(prefix []byte, depth int, path common.Hash) []byte {
+	k := make([]byte, len(prefix)+2+common.HashLength)
+	copy(k, prefix)
+	binary.BigEndian.PutUint16(k[len(prefix):], uint16(depth))
+	masked := k[len(prefix)+2:]
+	copy(masked, path[:depth/8])
+	if r := depth % 8; r > 0 {
+		masked[depth/8] = path[depth/8] & ^byte(0xff>>uint(r))
+	}
+	return k
+}
+
+func getNode(db database.KeyValueReader, prefix []byte, depth int, path common.Hash) (*node, error) {
+	b, err := db.Get(nodeKey(prefix, depth, path))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseNode(b)
+}
+
+func (t *Tree) get(depth int, path common.Hash) (*node, error) {
+	return getNode(t.db, t.prefix, depth, path)
+}
+
+func (t *Tree) put(depth int, path common.Hash, n *node) error {
+	k := nodeKey(t.prefix, depth, path)
+	if n == nil {
+		return t.db.Delete(k)
+	}
+	return t.db.Put(k, n.bytes())
+}
+
+// Root returns the root hash of the tree stored under [prefix] in [db].
+func Root(db database.KeyValueReader, prefix []byte) (common.Hash, error) {
+	n, err := getNode(db, prefix, 0, common.Hash{})
+	if err != nil {
+		return EmptyRoot, err
+	}
+	return n.hash(), nil
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() (common.Hash, error) {
+	return Root(t.db, t.prefix)
+}
+
+// Update sets the value hash of the leaf at [path].
+func (t *Tree) Update(path common.Hash, valueHash common.Hash) error {
+	_, err := t.update(0, path, &node{typ: leafNode, path: path, valueHash: valueHash})
+	return err
+}
+
+// Remove deletes the leaf at [path], if it exists.
+func (t *Tree) Remove(path common.Hash) error {
+	_, err := t.update(0, path, nil)
+	return err
+}
+
+// update sets (or removes if [leaf] is nil) the leaf at [path] in the subtree
+// at [depth] and returns the node that is now at the root of the subtree.
+func (t *Tree) update(depth int, path common.Hash, leaf *node) (*node, error) {
+	n, err := t.get(depth, path)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case n == nil:
+		if leaf == nil {
+			return nil, nil
+		}
+		return leaf, t.put(depth, path, leaf)
+	case n.typ == leafNode && n.path == path:
+		return leaf, t.put(depth, path, leaf)
+	case n.typ == leafNode:
+		if leaf == nil {
+			return n, nil
+		}
+		// Push the existing leaf down so it can share the subtree with [leaf]
+		if err := t.put(depth+1, n.path, n); err != nil {
+			return nil, err
+		}
+	}
+
+	child, err := t.update(depth+1, path, leaf)
+	if err != nil {
+		return nil, err
+	}
+	var sibPath common.Hash
+	copy(sibPath[:], path[:])
+	i := depth / 8
+	sibPath[i] ^= 1 << (7 - uint(depth%8))
+	sibling, err := t.get(depth+1, sibPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collapse subtrees that only contain a single leaf
+	var single *node
+	switch {
+	case child == nil && sibling == nil:
+		return nil, t.put(depth, path, nil)
+	case child == nil && sibling.typ == leafNode:
+		single = sibling
+		if err := t.put(depth+1, sibPath, nil); err != nil {
+			return nil, err
+		}
+	case sibling == nil && child.typ == leafNode:
+		single = child
+		if err := t.put(depth+1, path, nil); err != nil {
+			return nil, err
+		}
+	}
+	if single != nil {
+		return single, t.put(depth, path, single)
+	}
+
+	in := &node{typ: internalNode}
+	if Bit(path, depth) == 0 {
+		in.left, in.right = child.hash(), sibling.hash()
+	} else {
+		in.left, in.right = sibling.hash(), child.hash()
+	}
+	return in, t.put(depth, path, in)
+}
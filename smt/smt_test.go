@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package smt
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func testLeaves(n int) (paths []common.Hash, values []common.Hash) {
+	for i := 0; i < n; i++ {
+		paths = append(paths, crypto.Keccak256Hash([]byte{byte(i), byte(i >> 8)}))
+		values = append(values, crypto.Keccak256Hash([]byte{byte(i), 0xff}))
+	}
+	// Force paths that share a long prefix
+	p := paths[0]
+	p[common.HashLength-1] ^= 1
+	paths = append(paths, p)
+	values = append(values, crypto.Keccak256Hash(p[:]))
+	return paths, values
+}
+
+func TestTreeOrderIndependent(t *testing.T) {
+	t.Parallel()
+
+	paths, values := testLeaves(64)
+	var expected common.Hash
+	for i := 0; i < 5; i++ {
+		db := memdb.New()
+		tr := New(db, []byte{0x1})
+		for _, j := range rand.Perm(len(paths)) {
+			if err := tr.Update(paths[j], values[j]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		root, err := tr.Root()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			expected = root
+			continue
+		}
+		if root != expected {
+			t.Fatalf("#%d: root expected %s, got %s", i, expected, root)
+		}
+	}
+}
+
+func TestTreeRemove(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	tr := New(db, []byte{0x1})
+	if root, err := tr.Root(); err != nil || root != EmptyRoot {
+		t.Fatalf("unexpected root %s, err %v", root, err)
+	}
+
+	paths, values := testLeaves(32)
+	roots := make([]common.Hash, len(paths))
+	for i := range paths {
+		if err := tr.Update(paths[i], values[i]); err != nil {
+			t.Fatal(err)
+		}
+		root, err := tr.Root()
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots[i] = root
+	}
+
+	// Overwriting a leaf changes the root
+	if err := tr.Update(paths[0], common.Hash{0x1}); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := tr.Root(); err != nil || root == roots[len(roots)-1] {
+		t.Fatalf("unexpected root %s, err %v", root, err)
+	}
+	if err := tr.Update(paths[0], values[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a missing leaf is a no-op
+	if err := tr.Remove(common.Hash{0x2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing leaves in reverse restores every intermediate root
+	for i := len(paths) - 1; i > 0; i-- {
+		if err := tr.Remove(paths[i]); err != nil {
+			t.Fatal(err)
+		}
+		root, err := tr.Root()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != roots[i-1] {
+			t.Fatalf("#%d: root expected %s, got %s", i, roots[i-1], root)
+		}
+	}
+	if err := tr.Remove(paths[0]); err != nil {
+		t.Fatal(err)
+	}
+	if root, err := tr.Root(); err != nil || root != EmptyRoot {
+		t.Fatalf("unexpected root %s, err %v", root, err)
+	}
+
+	// No nodes should be left behind
+	iter := db.NewIterator()
+	defer iter.Release()
+	if iter.Next() {
+		t.Fatalf("unexpected node %x", iter.Key())
+	}
+}
+
+func TestTreeSingleLeafRoot(t *testing.T) {
+	t.Parallel()
+
+	tr := New(memdb.New(), nil)
+	path, value := common.Hash{0x3}, common.Hash{0x4}
+	if err := tr.Update(path, value); err != nil {
+		t.Fatal(err)
+	}
+	root, err := tr.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := LeafHash(path, value); root != expected {
+		t.Fatalf("root expected %s, got %s", expected, root)
+	}
+}
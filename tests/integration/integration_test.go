@@ -38,6 +38,7 @@ import (
 	"github.com/ava-labs/spacesvm/chain"
 	"github.com/ava-labs/spacesvm/client"
 	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/proof"
 	"github.com/ava-labs/spacesvm/tdata"
 	"github.com/ava-labs/spacesvm/tree"
 	"github.com/ava-labs/spacesvm/vm"
@@ -87,6 +88,10 @@ var (
 	instances    []instance
 
 	genesis *chain.Genesis
+
+	subnetID    ids.ID
+	chainID     ids.ID
+	airdropData []byte
 )
 
 type instance struct {
@@ -129,15 +134,15 @@ var _ = ginkgo.BeforeSuite(func() {
 			Balance: 10000000,
 		},
 	}
-	airdropData := []byte(fmt.Sprintf(`[{"address":"%s"}]`, sender2))
+	airdropData = []byte(fmt.Sprintf(`[{"address":"%s"}]`, sender2))
 	genesis.AirdropHash = ecommon.BytesToHash(crypto.Keccak256(airdropData)).Hex()
 	genesis.AirdropUnits = 1000000000
 	genesisBytes, err = json.Marshal(genesis)
 	gomega.Ω(err).Should(gomega.BeNil())
 
 	networkID := uint32(1)
-	subnetID := ids.GenerateTestID()
-	chainID := ids.GenerateTestID()
+	subnetID = ids.GenerateTestID()
+	chainID = ids.GenerateTestID()
 
 	app := &appSender{}
 	for i := range instances {
@@ -158,7 +163,8 @@ var _ = ginkgo.BeforeSuite(func() {
 			db,
 			genesisBytes,
 			nil,
-			nil,
+			// Serve the state at every block
+			[]byte(`{"stateSyncInterval":1}`),
 			toEngine,
 			nil,
 			app,
@@ -426,6 +432,25 @@ var _ = ginkgo.Describe("Tx Types", func() {
 			gomega.Ω(valueMeta.Size).To(gomega.Equal(uint64(5)))
 		})
 
+		ginkgo.By("read back from VM with verified proofs", func() {
+			pcli := proof.NewClient(instances[0].cli)
+			exists, value, _, err := pcli.Resolve(context.Background(), space+"/"+k)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(exists).To(gomega.BeTrue())
+			gomega.Ω(value).To(gomega.Equal(v))
+
+			exists, _, _, err = pcli.Resolve(context.Background(), space+"/missing")
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(exists).To(gomega.BeFalse())
+
+			info, _, err := pcli.Info(context.Background(), space)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(info.Owner).To(gomega.Equal(sender))
+
+			_, err = pcli.Balance(context.Background(), sender)
+			gomega.Ω(err).To(gomega.BeNil())
+		})
+
 		ginkgo.By("transfer funds to other sender", func() {
 			transferTx := &chain.TransferTx{
 				BaseTx: &chain.BaseTx{},
@@ -666,6 +691,114 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		}
 	})
 
+	ginkgo.It("state sync a new node", func() {
+		ctx := &snow.Context{
+			NetworkID: 1,
+			SubnetID:  subnetID,
+			ChainID:   chainID,
+			NodeID:    ids.GenerateTestShortID(),
+		}
+		toEngine := make(chan common.Message, 1)
+		synced := &vm.VM{AirdropData: airdropData}
+		// Blocks are summarized at every height, so the last accepted block is
+		// the state summary
+		summaryID, err := instances[0].vm.LastAccepted()
+		gomega.Ω(err).Should(gomega.BeNil())
+		summary, err := instances[0].vm.GetBlock(summaryID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		config := fmt.Sprintf(
+			`{"stateSyncServers":[%q],"stateSyncBlockId":%q,"stateSyncHeight":%d,"stateSyncChunkSize":16}`,
+			instances[0].httpServer.URL, summaryID, summary.Height(),
+		)
+
+		ginkgo.By("initialize from the state summary of another node", func() {
+			err := synced.Initialize(
+				ctx,
+				manager.NewMemDB(avago_version.CurrentDatabase),
+				genesisBytes,
+				nil,
+				[]byte(config),
+				toEngine,
+				nil,
+				&appSender{instances: instances},
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+
+			expected, err := instances[0].vm.LastAccepted()
+			gomega.Ω(err).Should(gomega.BeNil())
+			lastAccepted, err := synced.LastAccepted()
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(lastAccepted).Should(gomega.Equal(expected))
+		})
+
+		hd, err := synced.CreateHandlers()
+		gomega.Ω(err).Should(gomega.BeNil())
+		httpServer := httptest.NewServer(hd[vm.PublicEndpoint].Handler)
+		defer httpServer.Close()
+		cli := client.New(httpServer.URL, requestTimeout)
+
+		ginkgo.By("read back the same state", func() {
+			for _, addr := range []ecommon.Address{sender, sender2} {
+				bal, err := instances[0].cli.Balance(context.Background(), addr)
+				gomega.Ω(err).Should(gomega.BeNil())
+				bal2, err := cli.Balance(context.Background(), addr)
+				gomega.Ω(err).Should(gomega.BeNil())
+				gomega.Ω(bal2).Should(gomega.Equal(bal))
+			}
+
+			owned, err := instances[0].cli.Owned(context.Background(), sender)
+			gomega.Ω(err).Should(gomega.BeNil())
+			owned2, err := cli.Owned(context.Background(), sender)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(owned2).Should(gomega.Equal(owned))
+
+			values := 0
+			for _, space := range owned {
+				info, kvs, err := instances[0].cli.Info(context.Background(), space)
+				gomega.Ω(err).Should(gomega.BeNil())
+				info2, kvs2, err := cli.Info(context.Background(), space)
+				gomega.Ω(err).Should(gomega.BeNil())
+				gomega.Ω(info2).Should(gomega.Equal(info))
+				gomega.Ω(kvs2).Should(gomega.Equal(kvs))
+
+				for _, kv := range kvs {
+					path := space + "/" + kv.Key
+					_, v, _, err := instances[0].cli.Resolve(context.Background(), path)
+					gomega.Ω(err).Should(gomega.BeNil())
+					_, v2, _, err := proof.NewClient(cli).Resolve(context.Background(), path)
+					gomega.Ω(err).Should(gomega.BeNil())
+					gomega.Ω(v2).Should(gomega.Equal(v))
+					values++
+				}
+			}
+			gomega.Ω(values).Should(gomega.BeNumerically(">", 0))
+		})
+
+		ginkgo.By("verify and accept the next block", func() {
+			createIssueRawTx(instances[0], &chain.ClaimTx{
+				BaseTx: &chain.BaseTx{},
+				Space:  RandStringRunes(64),
+			}, priv)
+			expectBlkAccept(instances[0])
+
+			blkID, err := instances[0].vm.LastAccepted()
+			gomega.Ω(err).Should(gomega.BeNil())
+			blk, err := instances[0].vm.GetBlock(blkID)
+			gomega.Ω(err).Should(gomega.BeNil())
+
+			blk2, err := synced.ParseBlock(blk.Bytes())
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(blk2.Verify()).Should(gomega.BeNil())
+			gomega.Ω(blk2.Accept()).Should(gomega.BeNil())
+
+			lastAccepted, err := synced.LastAccepted()
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(lastAccepted).Should(gomega.Equal(blkID))
+		})
+
+		gomega.Ω(synced.Shutdown()).Should(gomega.BeNil())
+	})
+
 	// TODO: full replicate blocks between nodes
 })
 
@@ -0,0 +1,192 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// AdminService is served at [AdminEndpoint] if [Config.AdminAPIEnabled] is
+// set. It must not be exposed to untrusted clients.
+type AdminService struct {
+	vm *VM
+}
+
+type PeerInfo struct {
+	NodeID ids.ShortID `serialize:"true" json:"nodeId"`
+	PeerStats
+}
+
+type PeersReply struct {
+	Peers []*PeerInfo `serialize:"true" json:"peers"`
+}
+
+// Peers returns the gossip counters of all tracked peers, from the peer that
+// sent the most bytes to the one that sent the least.
+func (svc *AdminService) Peers(_ *http.Request, _ *struct{}, reply *PeersReply) error {
+	stats := svc.vm.peers.Stats()
+	reply.Peers = make([]*PeerInfo, 0, len(stats))
+	for nodeID, s := range stats {
+		reply.Peers = append(reply.Peers, &PeerInfo{NodeID: nodeID, PeerStats: s})
+	}
+	sort.Slice(reply.Peers, func(i, j int) bool {
+		if reply.Peers[i].Bytes != reply.Peers[j].Bytes {
+			return reply.Peers[i].Bytes > reply.Peers[j].Bytes
+		}
+		return reply.Peers[i].NodeID.String() < reply.Peers[j].NodeID.String()
+	})
+	return nil
+}
+
+type MempoolTx struct {
+	TxID   ids.ID             `serialize:"true" json:"txId"`
+	Sender common.Address     `serialize:"true" json:"sender"`
+	Price  uint64             `serialize:"true" json:"price"`
+	Units  uint64             `serialize:"true" json:"units"`
+	Tx     *chain.Transaction `serialize:"true" json:"tx"`
+
+	// MaxPrice and PriorityTip are only set if the fee market is enabled.
+	MaxPrice    uint64 `serialize:"true" json:"maxPrice,omitempty"`
+	PriorityTip uint64 `serialize:"true" json:"priorityTip,omitempty"`
+}
+
+type MempoolReply struct {
+	Txs []*MempoolTx `serialize:"true" json:"txs"`
+}
+
+// Mempool returns the transactions in the mempool, from the highest to the
+// lowest priority.
+func (svc *AdminService) Mempool(_ *http.Request, _ *struct{}, reply *MempoolReply) error {
+	txs := svc.vm.mempool.Txs()
+	reply.Txs = make([]*MempoolTx, len(txs))
+	for i, tx := range txs {
+		reply.Txs[i] = &MempoolTx{
+			TxID:   tx.ID(),
+			Sender: tx.Sender(),
+			Price:  tx.GetPrice(),
+			Units:  tx.LoadUnits(svc.vm.genesis),
+			Tx:     tx,
+
+			MaxPrice:    tx.GetMaxPrice(),
+			PriorityTip: tx.GetPriorityTip(),
+		}
+	}
+	return nil
+}
+
+type EvictTxArgs struct {
+	TxID ids.ID `serialize:"true" json:"txId"`
+}
+
+func (svc *AdminService) EvictTx(_ *http.Request, args *EvictTxArgs, reply *PingReply) error {
+	if svc.vm.mempool.Remove(args.TxID) == nil {
+		return fmt.Errorf("%w: %s not in mempool", ErrTxNotFound, args.TxID)
+	}
+	log.Info("evicted tx", "txId", args.TxID)
+	reply.Success = true
+	return nil
+}
+
+type SetLogLevelArgs struct {
+	// Level is one of "crit", "error", "warn", "info", "debug"
+	Level string `serialize:"true" json:"level"`
+}
+
+// logLevel is the maximum level logged by the handlers returned by
+// [LevelHandler].
+var logLevel = int32(log.LvlDebug)
+
+// LevelHandler only passes the records at or below the level set by
+// [AdminService.SetLogLevel] (initially [lvl]) to [h]. It should be the only
+// level filter of the root handler.
+func LevelHandler(lvl log.Lvl, h log.Handler) log.Handler {
+	atomic.StoreInt32(&logLevel, int32(lvl))
+	return log.FuncHandler(func(r *log.Record) error {
+		if r.Lvl > log.Lvl(atomic.LoadInt32(&logLevel)) {
+			return nil
+		}
+		return h.Log(r)
+	})
+}
+
+// SetLogLevel sets the level of [LevelHandler]. Because all chains in this
+// process log through the same root handler, the level applies to all of them.
+func (svc *AdminService) SetLogLevel(_ *http.Request, args *SetLogLevelArgs, reply *PingReply) error {
+	lvl, err := log.LvlFromString(args.Level)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&logLevel, int32(lvl))
+	log.Info("set log level", "level", lvl)
+	reply.Success = true
+	return nil
+}
+
+type PruneReply struct {
+	Removals int `serialize:"true" json:"removals"`
+}
+
+// Prune removes all expired spaces instead of waiting for the pruner.
+func (svc *AdminService) Prune(_ *http.Request, _ *struct{}, reply *PruneReply) error {
+	for {
+		removals, err := svc.vm.pruneNext()
+		if err != nil {
+			return err
+		}
+		reply.Removals += removals
+		if removals < svc.vm.config.PruneLimit {
+			return nil
+		}
+	}
+}
+
+type CompactReply struct {
+	Ranges int `serialize:"true" json:"ranges"`
+}
+
+// Compact compacts all ranges instead of waiting for the compactor.
+func (svc *AdminService) Compact(_ *http.Request, _ *struct{}, reply *CompactReply) error {
+	for _, r := range chain.CompactRanges {
+		if err := svc.vm.compactRange(r); err != nil {
+			return err
+		}
+		reply.Ranges++
+	}
+	return nil
+}
+
+type SetBlockBuilderArgs struct {
+	// Manual stops building blocks and gossiping transactions on timers
+	// (using a [ManualBuilder] instead of a [TimeBuilder]).
+	Manual bool `serialize:"true" json:"manual"`
+}
+
+func (svc *AdminService) SetBlockBuilder(_ *http.Request, args *SetBlockBuilderArgs, reply *PingReply) error {
+	if args.Manual {
+		svc.vm.SetBlockBuilder(func() BlockBuilder { return svc.vm.NewManualBuilder() })
+	} else {
+		svc.vm.SetBlockBuilder(func() BlockBuilder { return svc.vm.NewTimeBuilder() })
+	}
+	log.Info("set block builder", "manual", args.Manual)
+	reply.Success = true
+	return nil
+}
+
+type ConfigReply struct {
+	Config Config `serialize:"true" json:"config"`
+}
+
+func (svc *AdminService) Config(_ *http.Request, _ *struct{}, reply *ConfigReply) error {
+	reply.Config = svc.vm.config
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestAdminService(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: crypto.PubkeyToAddress(priv.PublicKey), Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+
+	// Only served if enabled
+	handlers, err := vm.CreateHandlers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := handlers[AdminEndpoint]; ok {
+		t.Fatal("admin API served by default")
+	}
+	vm.config.AdminAPIEnabled = true
+	handlers, err = vm.CreateHandlers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := handlers[AdminEndpoint]; !ok {
+		t.Fatal("admin API not served")
+	}
+	svc := &AdminService{vm: vm}
+
+	// Mempool
+	low, high := newClaimTx(t, vm, priv, "low", 1), newClaimTx(t, vm, priv, "high", 2)
+	if errs := vm.Submit(low, high); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	mempool := new(MempoolReply)
+	if err := svc.Mempool(nil, nil, mempool); err != nil {
+		t.Fatal(err)
+	}
+	if len(mempool.Txs) != 2 || mempool.Txs[0].TxID != high.ID() || mempool.Txs[1].Sender != low.Sender() {
+		t.Fatalf("unexpected mempool %+v", mempool.Txs)
+	}
+	if err := svc.EvictTx(nil, &EvictTxArgs{TxID: high.ID()}, &PingReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if vm.mempool.Has(high.ID()) || vm.mempool.Len() != 1 {
+		t.Fatal("tx not evicted")
+	}
+	if err := svc.EvictTx(nil, &EvictTxArgs{TxID: high.ID()}, &PingReply{}); !errors.Is(err, ErrTxNotFound) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Log level
+	logged := 0
+	h := LevelHandler(log.LvlDebug, log.FuncHandler(func(*log.Record) error {
+		logged++
+		return nil
+	}))
+	if err := svc.SetLogLevel(nil, &SetLogLevelArgs{Level: "warn"}, &PingReply{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, lvl := range []log.Lvl{log.LvlError, log.LvlWarn, log.LvlInfo} {
+		if err := h.Log(&log.Record{Lvl: lvl}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if logged != 2 {
+		t.Fatalf("expected 2 records to be logged, found %d", logged)
+	}
+	if err := svc.SetLogLevel(nil, &SetLogLevelArgs{Level: "loud"}, &PingReply{}); err == nil {
+		t.Fatal("invalid log level set")
+	}
+	if err := svc.SetLogLevel(nil, &SetLogLevelArgs{Level: "debug"}, &PingReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pruning and compaction
+	vm.config.PruneLimit = 1
+	for _, spc := range []string{"a", "b", "c"} {
+		if err := chain.PutSpaceInfo(vm.db, []byte(spc), &chain.SpaceInfo{Owner: common.Address{0x1}, Expiry: 10}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := chain.ExpireNext(vm.genesis, vm.db, 0, 20, true); err != nil {
+		t.Fatal(err)
+	}
+	prune := new(PruneReply)
+	if err := svc.Prune(nil, nil, prune); err != nil {
+		t.Fatal(err)
+	}
+	if backlog, err := chain.PruningBacklog(vm.db, 10); prune.Removals != 3 || backlog != 0 || err != nil {
+		t.Fatalf("unexpected removals %d (backlog %d, %v)", prune.Removals, backlog, err)
+	}
+	compact := new(CompactReply)
+	if err := svc.Compact(nil, nil, compact); err != nil {
+		t.Fatal(err)
+	}
+	if compact.Ranges != len(chain.CompactRanges) {
+		t.Fatalf("unexpected ranges %d", compact.Ranges)
+	}
+
+	// Block builder
+	if err := svc.SetBlockBuilder(nil, &SetBlockBuilderArgs{}, &PingReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := vm.builder.(*TimeBuilder); !ok {
+		t.Fatalf("unexpected builder %T", vm.builder)
+	}
+	if err := svc.SetBlockBuilder(nil, &SetBlockBuilderArgs{Manual: true}, &PingReply{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := vm.builder.(*ManualBuilder); !ok {
+		t.Fatalf("unexpected builder %T", vm.builder)
+	}
+
+	// Config
+	config := new(ConfigReply)
+	if err := svc.Config(nil, nil, config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Config.PruneLimit != 1 || !config.Config.AdminAPIEnabled {
+		t.Fatalf("unexpected config %+v", config.Config)
+	}
+}
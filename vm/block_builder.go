@@ -190,6 +190,9 @@ func (b *TimeBuilder) Gossip() {
 	rg := time.NewTicker(b.vm.config.RegossipInterval)
 	defer rg.Stop()
 
+	pg := time.NewTicker(b.vm.config.PullGossipInterval)
+	defer pg.Stop()
+
 	for {
 		select {
 		case <-g.C:
@@ -197,6 +200,8 @@ func (b *TimeBuilder) Gossip() {
 			_ = b.vm.network.GossipNewTxs(newTxs) // handles case where there are none
 		case <-rg.C:
 			_ = b.vm.network.RegossipTxs()
+		case <-pg.C:
+			_ = b.vm.pull.PullTxs()
 		case <-b.builderStop:
 			return
 		case <-b.stop:
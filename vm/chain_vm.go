@@ -27,6 +27,10 @@ func (vm *VM) Mempool() chain.Mempool {
 	return vm.mempool
 }
 
+func (vm *VM) Metrics() *chain.Metrics {
+	return vm.chainMetrics
+}
+
 func (vm *VM) Verified(b *chain.StatelessBlock) {
 	vm.verifiedBlocks[b.ID()] = b
 	for _, tx := range b.Txs {
@@ -49,33 +53,36 @@ func (vm *VM) Accepted(b *chain.StatelessBlock) {
 	vm.lastAccepted = b
 	log.Debug("accepted block", "blkID", b.ID())
 
+	if i := vm.config.StateSyncInterval; i > 0 && b.Hght%i == 0 {
+		if err := chain.PutStateSummary(vm.db, b.Hght, b.ID()); err != nil {
+			log.Error("unable to record state summary", "blkID", b.ID(), "error", err)
+		}
+	}
+
+	vm.stream.Publish(b)
+
+	// The activity was already indexed by [chain.SetLastAccepted]
 	if vm.config.ActivityCacheSize == 0 {
 		return
 	}
 	cs := uint64(vm.config.ActivityCacheSize)
-	for _, tx := range b.Txs {
-		activity := tx.Activity()
-		activity.Tmstmp = b.Tmstmp
-		vm.activityCache[vm.activityCacheCursor%cs] = activity
+	for _, a := range chain.BlockActivity(b) {
+		vm.activityCache[vm.activityCacheCursor%cs] = a
 		vm.activityCacheCursor++
-		if reward, ok := b.Winners[tx.ID()]; ok {
-			vm.activityCache[vm.activityCacheCursor%cs] = reward
-			vm.activityCacheCursor++
-		}
 	}
 }
 
 func (vm *VM) ExecutionContext(currTime int64, lastBlock *chain.StatelessBlock) (*chain.Context, error) {
 	g := vm.genesis
 	recentBlockIDs := ids.Set{}
-	recentTxIDs := ids.Set{}
+	recentReplayIDs := ids.Set{}
 	recentUnits := uint64(0)
 	prices := []uint64{}
 	costs := []uint64{}
 	err := vm.lookback(currTime, lastBlock.ID(), func(b *chain.StatelessBlock) (bool, error) {
 		recentBlockIDs.Add(b.ID())
 		for _, tx := range b.StatefulBlock.Txs {
-			recentTxIDs.Add(tx.ID())
+			recentReplayIDs.Add(tx.ReplayID())
 			recentUnits += tx.LoadUnits(g)
 		}
 		prices = append(prices, b.Price)
@@ -105,7 +112,11 @@ func (vm *VM) ExecutionContext(currTime int64, lastBlock *chain.StatelessBlock)
 
 	// compute new min price
 	nextPrice := lastBlock.Price
-	if recentUnits > vm.targetRangeUnits {
+	if g.FeeMarketEnabled {
+		// Transactions tip block producers directly, so there is no block cost
+		nextPrice = chain.NextBasePrice(g, lastBlock.Price, recentUnits, vm.targetRangeUnits)
+		nextCost = chain.MinBlockCost
+	} else if recentUnits > vm.targetRangeUnits {
 		nextPrice++
 	} else if recentUnits < vm.targetRangeUnits {
 		elapsedWindows := uint64(secondsSinceLast/g.LookbackWindow) + 1 // account for current window being less
@@ -116,9 +127,11 @@ func (vm *VM) ExecutionContext(currTime int64, lastBlock *chain.StatelessBlock)
 		}
 	}
 
+	vm.metrics.nextPrice.Set(float64(nextPrice))
+	vm.metrics.nextCost.Set(float64(nextCost))
 	return &chain.Context{
 		RecentBlockIDs:  recentBlockIDs,
-		RecentTxIDs:     recentTxIDs,
+		RecentReplayIDs: recentReplayIDs,
 		RecentLoadUnits: recentUnits,
 
 		Prices: prices,
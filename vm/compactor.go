@@ -4,6 +4,7 @@
 package vm
 
 import (
+	"fmt"
 	"time"
 
 	log "github.com/inconshreveable/log15"
@@ -16,18 +17,26 @@ func (vm *VM) compactCall(r *chain.CompactRange) {
 	vm.ctx.Lock.Lock()
 	defer vm.ctx.Lock.Unlock()
 
+	if err := vm.compactRange(r); err != nil {
+		log.Error("unable to compact range", "start", r.Start, "stop", r.Limit, "error", err)
+	}
+}
+
+// compactRange compacts [r]. The caller must hold the context lock.
+func (vm *VM) compactRange(r *chain.CompactRange) error {
 	start := time.Now()
 	if err := vm.db.Compact(r.Start, r.Limit); err != nil {
-		log.Error("unable to compact range", "start", r.Start, "stop", r.Limit)
-		return
+		return err
 	}
-	log.Debug("compacted range", "start", r.Start, "stop", r.Limit, "t", time.Since(start))
+	elapsed := time.Since(start)
+	vm.metrics.compactionDuration.Observe(elapsed.Seconds())
+	log.Debug("compacted range", "start", r.Start, "stop", r.Limit, "t", elapsed)
 
 	// Make sure to update children or else won't be persisted
 	if err := vm.lastAccepted.SetChildrenDB(vm.db); err != nil {
-		log.Error("unable to update child databases of last accepted block", "error", err)
-		return
+		return fmt.Errorf("unable to update child databases of last accepted block: %w", err)
 	}
+	return nil
 }
 
 func (vm *VM) compact() {
@@ -5,6 +5,9 @@ package vm
 
 import (
 	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 type Config struct {
@@ -12,14 +15,63 @@ type Config struct {
 	GossipInterval   time.Duration `serialize:"true" json:"gossipInterval"`
 	RegossipInterval time.Duration `serialize:"true" json:"regossipInterval"`
 
+	// PullGossipInterval is how often the txs missing from the mempool are
+	// requested from a random peer (see [PullNetwork]).
+	PullGossipInterval    time.Duration `serialize:"true" json:"pullGossipInterval"`
+	PullGossipTimeout     time.Duration `serialize:"true" json:"pullGossipTimeout"`
+	PullGossipMaxInflight int           `serialize:"true" json:"pullGossipMaxInflight"`
+
+	// Messages from a peer beyond these rates are dropped and a peer that
+	// sends more than [GossipMaxInvalidTxs] invalid txs in a minute is
+	// ignored for [GossipIgnoreDuration] (see [PeerTracker]).
+	GossipMaxBytesPerSecond int           `serialize:"true" json:"gossipMaxBytesPerSecond"`
+	GossipMaxTxsPerSecond   int           `serialize:"true" json:"gossipMaxTxsPerSecond"`
+	GossipMaxInvalidTxs     int           `serialize:"true" json:"gossipMaxInvalidTxs"`
+	GossipIgnoreDuration    time.Duration `serialize:"true" json:"gossipIgnoreDuration"`
+
 	PruneLimit        int           `serialize:"true" json:"pruneLimit"`
 	PruneInterval     time.Duration `serialize:"true" json:"pruneInterval"`
 	FullPruneInterval time.Duration `serialize:"true" json:"fullPruneInterval"`
 
 	CompactInterval time.Duration `serialize:"true" json:"compactInterval"`
 
-	MempoolSize       int `serialize:"true" json:"mempoolSize"`
-	ActivityCacheSize int `serialize:"true" json:"activityCacheSize"`
+	// StreamMaxSubscribers bounds the number of clients connected to
+	// [StreamEndpoint] at once (see [StreamServer]).
+	StreamMaxSubscribers int `serialize:"true" json:"streamMaxSubscribers"`
+
+	// AdminAPIEnabled serves [AdminService] at [AdminEndpoint].
+	AdminAPIEnabled bool `serialize:"true" json:"adminAPIEnabled"`
+
+	MempoolSize int `serialize:"true" json:"mempoolSize"`
+	// MempoolSenderQuota is the number of txs a sender can have in the mempool
+	// (see [mempool.New]).
+	MempoolSenderQuota int `serialize:"true" json:"mempoolSenderQuota"`
+	// MempoolFlushInterval is how often the mempool is persisted to be
+	// restored after a restart (0 disables persisting the mempool).
+	MempoolFlushInterval time.Duration `serialize:"true" json:"mempoolFlushInterval"`
+	ActivityCacheSize    int           `serialize:"true" json:"activityCacheSize"`
+
+	// The node reports itself as unhealthy once no block has been accepted
+	// for [HealthMaxMissedBlocks] times the target block rate while there are
+	// pending txs, once the mempool is fuller than
+	// [HealthMaxMempoolSaturation], or once more than
+	// [HealthMaxPruningBacklog] expired spaces are waiting to be pruned.
+	HealthMaxMissedBlocks      int64   `serialize:"true" json:"healthMaxMissedBlocks"`
+	HealthMaxMempoolSaturation float64 `serialize:"true" json:"healthMaxMempoolSaturation"`
+	HealthMaxPruningBacklog    int     `serialize:"true" json:"healthMaxPruningBacklog"`
+
+	// StateSyncInterval is the number of blocks between the state summaries
+	// served to syncing nodes (0 disables serving state sync).
+	StateSyncInterval uint64 `serialize:"true" json:"stateSyncInterval"`
+	// StateSyncServers are the URIs of the nodes a new node fetches the state
+	// at the state summary with [StateSyncBlockID] and [StateSyncHeight] from
+	// instead of executing all blocks since genesis. The summary must be
+	// pinned by the operator because the servers are not trusted to serve a
+	// block that was accepted by the network.
+	StateSyncServers   []string `serialize:"true" json:"stateSyncServers"`
+	StateSyncBlockID   ids.ID   `serialize:"true" json:"stateSyncBlockId"`
+	StateSyncHeight    uint64   `serialize:"true" json:"stateSyncHeight"`
+	StateSyncChunkSize int      `serialize:"true" json:"stateSyncChunkSize"`
 }
 
 func (c *Config) SetDefaults() {
@@ -27,12 +79,32 @@ func (c *Config) SetDefaults() {
 	c.GossipInterval = 1 * time.Second
 	c.RegossipInterval = 30 * time.Second
 
+	c.PullGossipInterval = 2 * time.Second
+	c.PullGossipTimeout = 10 * time.Second
+	c.PullGossipMaxInflight = 4
+
+	c.GossipMaxBytesPerSecond = 4 * units.MiB
+	c.GossipMaxTxsPerSecond = 512
+	c.GossipMaxInvalidTxs = 64
+	c.GossipIgnoreDuration = 5 * time.Minute
+
 	c.PruneLimit = 128
 	c.PruneInterval = time.Minute
 	c.FullPruneInterval = time.Second
 
 	c.CompactInterval = 1 * time.Minute
 
+	c.StreamMaxSubscribers = 256
+
 	c.MempoolSize = 1024
+	c.MempoolSenderQuota = 128
+	c.MempoolFlushInterval = 5 * time.Second
 	c.ActivityCacheSize = 128
+
+	c.HealthMaxMissedBlocks = 60
+	c.HealthMaxMempoolSaturation = 0.95
+	c.HealthMaxPruningBacklog = 10_000
+
+	c.StateSyncInterval = 4096
+	c.StateSyncChunkSize = maxPageLimit
 }
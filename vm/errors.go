@@ -13,4 +13,14 @@ var (
 	ErrInputIsNil     = errors.New("input is nil")
 	ErrInvalidEmptyTx = errors.New("invalid empty transaction")
 	ErrCorruption     = errors.New("corruption detected")
+	ErrBlockNotFound  = errors.New("block not found")
+	ErrTxNotFound     = errors.New("transaction not found")
+	ErrInvalidLimit   = errors.New("invalid limit")
+	ErrInvalidUrgency = errors.New("invalid urgency")
+
+	ErrNoStateSummary    = errors.New("no state summary")
+	ErrStaleStateSummary = errors.New("state summary is no longer served")
+	ErrUnpinnedSummary   = errors.New("state sync requires a pinned state summary")
+
+	ErrUnhealthy = errors.New("unhealthy")
 )
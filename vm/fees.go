@@ -0,0 +1,169 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// Urgency is how soon a transaction should be included in a block.
+type Urgency string
+
+const (
+	UrgencySlow   Urgency = "slow"
+	UrgencyNormal Urgency = "normal"
+	UrgencyFast   Urgency = "fast"
+)
+
+// horizon is the number of blocks a transaction with urgency [u] should be
+// included within.
+func (u Urgency) horizon() (int, error) {
+	switch u {
+	case UrgencySlow:
+		return 10, nil
+	case UrgencyNormal:
+		return 3, nil
+	case UrgencyFast:
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidUrgency, u)
+	}
+}
+
+// BlockFee summarizes the fees and usage of an accepted block.
+type BlockFee struct {
+	Height    uint64 `serialize:"true" json:"height"`
+	BlockID   ids.ID `serialize:"true" json:"blockId"`
+	Timestamp int64  `serialize:"true" json:"timestamp"`
+	Price     uint64 `serialize:"true" json:"price"`
+	Cost      uint64 `serialize:"true" json:"cost"`
+	Txs       int    `serialize:"true" json:"txs"`
+	LoadUnits uint64 `serialize:"true" json:"loadUnits"`
+	// FillRatio is [LoadUnits] / [Genesis.MaxBlockSize].
+	FillRatio float64 `serialize:"true" json:"fillRatio"`
+}
+
+func (vm *VM) blockFee(b *chain.StatelessBlock) *BlockFee {
+	units := uint64(0)
+	for _, tx := range b.Txs {
+		units += tx.LoadUnits(vm.genesis)
+	}
+	return &BlockFee{
+		Height:    b.Hght,
+		BlockID:   b.ID(),
+		Timestamp: b.Tmstmp,
+		Price:     b.Price,
+		Cost:      b.Cost,
+		Txs:       len(b.Txs),
+		LoadUnits: units,
+		FillRatio: float64(units) / float64(vm.genesis.MaxBlockSize),
+	}
+}
+
+// FeeHistory returns the fees of up to [blocks] accepted blocks, from the
+// oldest to the last accepted block.
+func (vm *VM) FeeHistory(blocks int) ([]*BlockFee, error) {
+	history := make([]*BlockFee, 0, blocks)
+	curr := vm.lastAccepted
+	for len(history) < blocks {
+		history = append(history, vm.blockFee(curr))
+		if curr.Hght == 0 /* genesis */ {
+			break
+		}
+		prnt, err := vm.GetStatelessBlock(curr.Prnt)
+		if err != nil {
+			return nil, err
+		}
+		curr = prnt
+	}
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
+
+// FeeEstimate is the fee a transaction should pay to be included with some
+// [Urgency].
+type FeeEstimate struct {
+	// Price and Cost are the block price and block cost to pay for (see
+	// [chain.SetFee]).
+	Price uint64 `serialize:"true" json:"price"`
+	Cost  uint64 `serialize:"true" json:"cost"`
+	// PriorityTip is only set if the fee market is enabled.
+	PriorityTip uint64 `serialize:"true" json:"priorityTip"`
+}
+
+// SuggestedUrgentFee is a variant of [SuggestedFee] that estimates the fee
+// required for a transaction to be included within the number of blocks
+// implied by [u].
+//
+// If recent blocks were fuller than targeted, the price is raised to remain
+// above the block price as it increases over those blocks. If the mempool holds
+// more than those blocks can fit, the fee is raised to outbid the transactions
+// that would otherwise be included first.
+func (vm *VM) SuggestedUrgentFee(u Urgency) (*FeeEstimate, error) {
+	horizon, err := u.horizon()
+	if err != nil {
+		return nil, err
+	}
+	_, cost, err := vm.SuggestedFee()
+	if err != nil {
+		return nil, err
+	}
+	parent, err := vm.GetStatelessBlock(vm.preferred)
+	if err != nil {
+		return nil, err
+	}
+	g := vm.genesis
+	ctx, err := vm.ExecutionContext(time.Now().Unix(), parent)
+	if err != nil {
+		return nil, err
+	}
+	estimate := &FeeEstimate{Price: ctx.NextPrice, Cost: cost}
+	if g.FeeMarketEnabled {
+		estimate.Cost = chain.MinBlockCost
+		estimate.PriorityTip = chain.DefaultPriorityTip
+	} else if u == UrgencyFast && ctx.NextCost > cost {
+		// Pay for the entire block cost to not wait on other transactions
+		estimate.Cost = ctx.NextCost
+	}
+
+	// Project the block price at the end of the horizon
+	if ctx.RecentLoadUnits > vm.targetRangeUnits {
+		for i := 1; i < horizon; i++ {
+			if g.FeeMarketEnabled {
+				estimate.Price = chain.NextBasePrice(g, estimate.Price, ctx.RecentLoadUnits, vm.targetRangeUnits)
+			} else {
+				estimate.Price++
+			}
+		}
+	}
+
+	// Outbid the mempool transactions that would be included first. [Txs] is
+	// ordered by priority, so the first ones that fit in the horizon are the
+	// transactions to compete with.
+	capacity := uint64(horizon) * g.MaxBlockSize
+	units := uint64(0)
+	for _, tx := range vm.mempool.Txs() {
+		units += tx.LoadUnits(g)
+		if units <= capacity {
+			continue
+		}
+		priority := chain.Priority(tx.UnsignedTransaction, ctx.NextPrice)
+		switch {
+		case g.FeeMarketEnabled && priority > estimate.PriorityTip:
+			// Priority is the effective tip + 1
+			estimate.PriorityTip = priority
+		case !g.FeeMarketEnabled && priority >= estimate.Price:
+			estimate.Price = priority + 1
+		}
+		break
+	}
+	return estimate, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func (vm *VM) flushCall() {
+	// Lock to prevent concurrent modification of the database
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if err := vm.flushMempool(); err != nil {
+		log.Warn("unable to flush mempool", "error", err)
+	}
+}
+
+// flushMempool persists the txs in the mempool so that they can be restored
+// after a restart (see [restoreMempool]).
+func (vm *VM) flushMempool() error {
+	txs := vm.mempool.Txs()
+	if err := chain.SetPendingTxs(vm.db, txs); err != nil {
+		return err
+	}
+	log.Debug("flushed mempool", "txs", len(txs))
+	return nil
+}
+
+// restoreMempool resubmits the txs persisted by [flushMempool], dropping those
+// whose block ID has left the lookback window or that are no longer valid.
+func (vm *VM) restoreMempool() error {
+	txs, err := chain.GetPendingTxs(vm.db)
+	if err != nil {
+		return err
+	}
+	if vm.config.MempoolFlushInterval == 0 {
+		// Drop any txs persisted before flushing was disabled
+		return chain.SetPendingTxs(vm.db, nil)
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+
+	ctx, err := vm.ExecutionContext(time.Now().Unix(), vm.lastAccepted)
+	if err != nil {
+		return err
+	}
+	recent := make([]*chain.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if !ctx.RecentBlockIDs.Contains(tx.GetBlockID()) {
+			continue
+		}
+		recent = append(recent, tx)
+	}
+
+	// Submit the highest paying txs first in case the mempool can't hold all of
+	// them
+	sort.SliceStable(recent, func(i, j int) bool {
+		return chain.Priority(recent[i].UnsignedTransaction, ctx.NextPrice) >
+			chain.Priority(recent[j].UnsignedTransaction, ctx.NextPrice)
+	})
+	errs := vm.Submit(recent...)
+	log.Info("restored mempool",
+		"txs", vm.mempool.Len(),
+		"expired", len(txs)-len(recent),
+		"invalid", len(errs),
+	)
+	return nil
+}
+
+func (vm *VM) flush() {
+	log.Debug("starting flush loops")
+	defer close(vm.doneFlush)
+
+	if vm.config.MempoolFlushInterval == 0 {
+		log.Debug("exiting flusher because mempool persistence is disabled")
+		return
+	}
+
+	t := time.NewTicker(vm.config.MempoolFlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-vm.stop:
+			return
+		}
+		vm.flushCall()
+	}
+}
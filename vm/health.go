@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// Health is the structured health reported by [VM.HealthCheck].
+type Health struct {
+	Bootstrapped bool `serialize:"true" json:"bootstrapped"`
+
+	LastAccepted          ids.ID        `serialize:"true" json:"lastAccepted"`
+	LastAcceptedHeight    uint64        `serialize:"true" json:"lastAcceptedHeight"`
+	TimeSinceLastAccepted time.Duration `serialize:"true" json:"timeSinceLastAccepted"`
+
+	MempoolSize       int     `serialize:"true" json:"mempoolSize"`
+	MempoolSaturation float64 `serialize:"true" json:"mempoolSaturation"`
+
+	// PruningBacklog is counted up to one more than
+	// [Config.HealthMaxPruningBacklog].
+	PruningBacklog int `serialize:"true" json:"pruningBacklog"`
+
+	DatabaseError string `serialize:"true" json:"databaseError,omitempty"`
+
+	// Failures are the reasons the node is unhealthy (if any).
+	Failures []string `serialize:"true" json:"failures,omitempty"`
+}
+
+// implements "snowmanblock.ChainVM.commom.VM.health.Checkable"
+func (vm *VM) HealthCheck() (interface{}, error) {
+	h := &Health{
+		Bootstrapped:       vm.bootstrapped.GetValue(),
+		LastAccepted:       vm.lastAccepted.ID(),
+		LastAcceptedHeight: vm.lastAccepted.Hght,
+		MempoolSize:        vm.mempool.Len(),
+	}
+	if !h.Bootstrapped {
+		h.Failures = append(h.Failures, "not bootstrapped")
+	}
+
+	// Blocks are only produced when there are txs to include
+	h.TimeSinceLastAccepted = time.Since(vm.lastAccepted.Timestamp())
+	maxDelay := time.Duration(vm.config.HealthMaxMissedBlocks*vm.genesis.TargetBlockRate) * time.Second
+	if h.Bootstrapped && h.MempoolSize > 0 && h.TimeSinceLastAccepted > maxDelay {
+		h.Failures = append(h.Failures, fmt.Sprintf("no block accepted in %s with pending txs", h.TimeSinceLastAccepted.Truncate(time.Second)))
+	}
+
+	h.MempoolSaturation = float64(h.MempoolSize) / float64(vm.config.MempoolSize)
+	if h.MempoolSaturation > vm.config.HealthMaxMempoolSaturation {
+		h.Failures = append(h.Failures, fmt.Sprintf("mempool %.0f%% full", h.MempoolSaturation*100))
+	}
+
+	backlog, err := chain.PruningBacklog(vm.db, vm.config.HealthMaxPruningBacklog+1)
+	if err == nil {
+		_, err = chain.HasLastAccepted(vm.db)
+	}
+	h.PruningBacklog = backlog
+	if err != nil {
+		h.DatabaseError = err.Error()
+		h.Failures = append(h.Failures, "database error")
+	}
+	if h.PruningBacklog > vm.config.HealthMaxPruningBacklog {
+		h.Failures = append(h.Failures, fmt.Sprintf("more than %d spaces waiting to be pruned", vm.config.HealthMaxPruningBacklog))
+	}
+
+	if len(h.Failures) > 0 {
+		return h, fmt.Errorf("%w: %s", ErrUnhealthy, strings.Join(h.Failures, "; "))
+	}
+	return h, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestHealthCheck(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: crypto.PubkeyToAddress(priv.PublicKey), Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+
+	check := func(failures int) *Health {
+		t.Helper()
+		intf, err := vm.HealthCheck()
+		h := intf.(*Health)
+		if len(h.Failures) != failures {
+			t.Fatalf("unexpected failures %v", h.Failures)
+		}
+		if failures > 0 && !errors.Is(err, ErrUnhealthy) {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if failures == 0 && err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	// Unhealthy until bootstrapped
+	check(1)
+	if err := vm.onNormalOperationsStarted(); err != nil {
+		t.Fatal(err)
+	}
+	h := check(0)
+	if h.LastAccepted != vm.lastAccepted.ID() || h.MempoolSize != 0 {
+		t.Fatalf("unexpected health %+v", h)
+	}
+
+	// No block was accepted since genesis although there are pending txs
+	tx := newClaimTx(t, vm, priv, "foo", 1)
+	if errs := vm.Submit(tx); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	check(1)
+
+	// Thresholds are configurable
+	vm.config.HealthMaxMissedBlocks = 1 << 32
+	check(0)
+	vm.config.HealthMaxMempoolSaturation = 0
+	h = check(1)
+	if h.MempoolSaturation != 1/float64(vm.config.MempoolSize) {
+		t.Fatalf("unexpected saturation %f", h.MempoolSaturation)
+	}
+	vm.config.HealthMaxMempoolSaturation = 1
+
+	// Expired spaces that haven't been pruned yet
+	if err := chain.PutSpaceInfo(vm.db, []byte("bar"), &chain.SpaceInfo{Owner: common.Address{0x1}, Expiry: 10}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.ExpireNext(vm.genesis, vm.db, 0, 20, true); err != nil {
+		t.Fatal(err)
+	}
+	h = check(0)
+	if h.PruningBacklog != 1 {
+		t.Fatalf("unexpected pruning backlog %d", h.PruningBacklog)
+	}
+	vm.config.HealthMaxPruningBacklog = 0
+	check(1)
+}
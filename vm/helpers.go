@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ava-labs/spacesvm/chain"
 )
@@ -17,6 +18,19 @@ const (
 	feePercentile = 60
 )
 
+// balance returns the accepted balance of [addr], which the mempool checks the
+// pending fees of [addr] against.
+func (vm *VM) balance(addr common.Address) (uint64, error) {
+	return chain.GetBalance(vm.db, addr)
+}
+
+// acceptedTime returns the timestamp of the last accepted block. Queries
+// check expiry against it instead of the local clock so that they agree with
+// the accepted state on every node.
+func (vm *VM) acceptedTime() uint64 {
+	return uint64(vm.lastAccepted.Tmstmp)
+}
+
 // TODO: add caching + test
 func (vm *VM) lookback(currTime int64, lastID ids.ID, f func(b *chain.StatelessBlock) (bool, error)) error {
 	curr, err := vm.GetStatelessBlock(lastID)
@@ -66,6 +80,10 @@ func (vm *VM) SuggestedFee() (uint64, uint64, error) {
 	if err != nil {
 		return 0, 0, err
 	}
+	if vm.genesis.FeeMarketEnabled {
+		// The price of the next block is known in advance
+		return ctx.NextPrice, chain.MinBlockCost, nil
+	}
 
 	// Sort useful costs/prices
 	sort.Slice(ctx.Prices, func(i, j int) bool { return ctx.Prices[i] < ctx.Prices[j] })
@@ -80,7 +98,7 @@ func (vm *VM) SuggestedFee() (uint64, uint64, error) {
 	}
 
 	// Adjust cost estimate based on recent txs
-	recentTxs := ctx.RecentTxIDs.Len()
+	recentTxs := ctx.RecentReplayIDs.Len()
 	if recentTxs == 0 {
 		return pPrice, pCost, nil
 	}
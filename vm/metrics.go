@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	pruneRemovals      prometheus.Counter
+	compactionDuration prometheus.Histogram
+
+	gossipSentTxs       prometheus.Counter
+	gossipSentBytes     prometheus.Counter
+	gossipReceivedTxs   prometheus.Counter
+	gossipReceivedBytes prometheus.Counter
+
+	nextPrice prometheus.Gauge
+	nextCost  prometheus.Gauge
+}
+
+func newMetrics(reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		pruneRemovals: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "prune",
+			Name:      "removals",
+			Help:      "number of expired entries removed by pruning",
+		}),
+		compactionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "compaction",
+			Name:      "seconds",
+			Help:      "time spent compacting a range of the database",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+		}),
+		gossipSentTxs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gossip",
+			Name:      "sent_txs",
+			Help:      "number of transactions pushed to peers",
+		}),
+		gossipSentBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gossip",
+			Name:      "sent_bytes",
+			Help:      "size of the gossip messages pushed to peers",
+		}),
+		gossipReceivedTxs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gossip",
+			Name:      "received_txs",
+			Help:      "number of transactions pushed by peers",
+		}),
+		gossipReceivedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gossip",
+			Name:      "received_bytes",
+			Help:      "size of the gossip messages pushed by peers",
+		}),
+		nextPrice: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "chain",
+			Name:      "next_price",
+			Help:      "minimum price of the next block",
+		}),
+		nextCost: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "chain",
+			Name:      "next_cost",
+			Help:      "minimum cost of the next block",
+		}),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.pruneRemovals),
+		reg.Register(m.compactionDuration),
+		reg.Register(m.gossipSentTxs),
+		reg.Register(m.gossipSentBytes),
+		reg.Register(m.gossipReceivedTxs),
+		reg.Register(m.gossipReceivedBytes),
+		reg.Register(m.nextPrice),
+		reg.Register(m.nextCost),
+	)
+	return m, errs.Err
+}
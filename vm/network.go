@@ -49,6 +49,8 @@ func (n *PushNetwork) sendTxs(txs []*chain.Transaction) error {
 		)
 		return err
 	}
+	n.vm.metrics.gossipSentTxs.Add(float64(len(txs)))
+	n.vm.metrics.gossipSentBytes.Add(float64(len(b)))
 
 	return nil
 }
@@ -112,6 +114,11 @@ func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
 		"bytes", len(msg),
 	)
 
+	if !vm.peers.Allow(nodeID, len(msg)) {
+		log.Debug("dropping AppGossip", "peerID", nodeID, "bytes", len(msg))
+		return nil
+	}
+
 	txs := make([]*chain.Transaction, 0)
 	if _, err := chain.Unmarshal(msg, &txs); err != nil {
 		log.Debug(
@@ -119,20 +126,14 @@ func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
 			"peerID", nodeID,
 			"err", err,
 		)
+		vm.peers.InvalidMessage(nodeID)
 		return nil
 	}
+	vm.metrics.gossipReceivedTxs.Add(float64(len(txs)))
+	vm.metrics.gossipReceivedBytes.Add(float64(len(msg)))
 
 	// submit incoming gossip
-	log.Debug("AppGossip transactions are being submitted", "txs", len(txs))
-	if errs := vm.Submit(txs...); len(errs) > 0 {
-		for _, err := range errs {
-			log.Debug(
-				"AppGossip failed to submit txs",
-				"peerID", nodeID,
-				"err", err,
-			)
-		}
-	}
+	vm.submitRemote(nodeID, txs)
 
 	// only trace error to prevent VM's being shutdown
 	// from "AppGossip" returning an error
@@ -140,6 +141,42 @@ func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
 	return nil
 }
 
+// submitRemote submits the [txs] received from [nodeID] to the mempool.
+//
+// Only malformed txs (that can't be encoded, have an invalid signature, or fail
+// [chain.UnsignedTransaction.ExecuteBase]) count against the reputation of
+// [nodeID]. Other failures depend on our state and mempool (e.g. the block of a
+// tx left the lookback window, the price moved, or the tx was accepted while
+// it was gossiped), so honest peers cause them too.
+func (vm *VM) submitRemote(nodeID ids.ShortID, txs []*chain.Transaction) {
+	if !vm.peers.AllowTxs(nodeID, len(txs)) {
+		log.Debug("dropping remote transactions", "peerID", nodeID, "txs", len(txs))
+		return
+	}
+	log.Debug("remote transactions are being submitted", "peerID", nodeID, "txs", len(txs))
+	wellFormed := make([]*chain.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if err := tx.Init(vm.genesis); err != nil {
+			log.Debug("remote tx can't be initialized", "peerID", nodeID, "err", err)
+			continue
+		}
+		if err := tx.ExecuteBase(vm.genesis); err != nil {
+			log.Debug("remote tx is invalid", "peerID", nodeID, "txID", tx.ID(), "err", err)
+			continue
+		}
+		wellFormed = append(wellFormed, tx)
+	}
+	vm.peers.InvalidTxs(nodeID, len(txs)-len(wellFormed))
+
+	for _, err := range vm.Submit(wellFormed...) {
+		log.Debug(
+			"failed to submit remote txs",
+			"peerID", nodeID,
+			"err", err,
+		)
+	}
+}
+
 // used for testing VM
 func (vm *VM) Network() *PushNetwork {
 	return vm.network
@@ -0,0 +1,192 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	log "github.com/inconshreveable/log15"
+)
+
+// invalidTxsWindow is the period over which the invalid txs of a peer are
+// counted against [Config.GossipMaxInvalidTxs].
+const invalidTxsWindow = time.Minute
+
+// PeerStats are the counters of the messages received from a peer.
+type PeerStats struct {
+	Messages        uint64 `serialize:"true" json:"messages"`
+	Bytes           uint64 `serialize:"true" json:"bytes"`
+	Txs             uint64 `serialize:"true" json:"txs"`
+	InvalidMessages uint64 `serialize:"true" json:"invalidMessages"`
+	InvalidTxs      uint64 `serialize:"true" json:"invalidTxs"`
+
+	// Throttled is the number of messages dropped because the peer exceeded
+	// the byte or tx rate limits.
+	Throttled uint64 `serialize:"true" json:"throttled"`
+	// Ignored is the number of messages dropped because the peer was ignored.
+	Ignored uint64 `serialize:"true" json:"ignored"`
+
+	// IgnoredUntil is set while the peer is ignored for sending too many
+	// invalid txs.
+	IgnoredUntil *time.Time `serialize:"true" json:"ignoredUntil,omitempty"`
+}
+
+type peer struct {
+	stats PeerStats
+
+	// token buckets refilled at the configured rate per second up to one
+	// second worth of tokens
+	bytes      float64
+	txs        float64
+	lastRefill time.Time
+
+	recentInvalid uint64
+	windowStart   time.Time
+}
+
+// PeerTracker rate limits the messages received from each peer and ignores
+// peers that send too many invalid txs for [Config.GossipIgnoreDuration].
+type PeerTracker struct {
+	config *Config
+
+	// [l] must be held when accessing [peers]
+	l     sync.Mutex
+	peers map[ids.ShortID]*peer
+}
+
+func NewPeerTracker(config *Config) *PeerTracker {
+	return &PeerTracker{
+		config: config,
+		peers:  map[ids.ShortID]*peer{},
+	}
+}
+
+func (t *PeerTracker) get(nodeID ids.ShortID, now time.Time) *peer {
+	p, ok := t.peers[nodeID]
+	if !ok {
+		p = &peer{
+			bytes:       float64(t.config.GossipMaxBytesPerSecond),
+			txs:         float64(t.config.GossipMaxTxsPerSecond),
+			lastRefill:  now,
+			windowStart: now,
+		}
+		t.peers[nodeID] = p
+	}
+	if elapsed := now.Sub(p.lastRefill).Seconds(); elapsed > 0 {
+		p.bytes = math.Min(p.bytes+elapsed*float64(t.config.GossipMaxBytesPerSecond), float64(t.config.GossipMaxBytesPerSecond))
+		p.txs = math.Min(p.txs+elapsed*float64(t.config.GossipMaxTxsPerSecond), float64(t.config.GossipMaxTxsPerSecond))
+		p.lastRefill = now
+	}
+	if p.stats.IgnoredUntil != nil && !now.Before(*p.stats.IgnoredUntil) {
+		p.stats.IgnoredUntil = nil
+	}
+	return p
+}
+
+// Allow returns true if a message of [size] bytes from [nodeID] should be
+// handled.
+func (t *PeerTracker) Allow(nodeID ids.ShortID, size int) bool {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	p := t.get(nodeID, time.Now())
+	p.stats.Messages++
+	p.stats.Bytes += uint64(size)
+	if p.stats.IgnoredUntil != nil {
+		p.stats.Ignored++
+		return false
+	}
+	if float64(size) > p.bytes {
+		p.stats.Throttled++
+		return false
+	}
+	p.bytes -= float64(size)
+	return true
+}
+
+// AllowTxs returns true if [count] txs from [nodeID] should be submitted.
+func (t *PeerTracker) AllowTxs(nodeID ids.ShortID, count int) bool {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	p := t.get(nodeID, time.Now())
+	p.stats.Txs += uint64(count)
+	if float64(count) > p.txs {
+		p.stats.Throttled++
+		return false
+	}
+	p.txs -= float64(count)
+	return true
+}
+
+// InvalidMessage records a message from [nodeID] that couldn't be parsed.
+func (t *PeerTracker) InvalidMessage(nodeID ids.ShortID) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	now := time.Now()
+	p := t.get(nodeID, now)
+	p.stats.InvalidMessages++
+	t.invalid(nodeID, p, 1, now)
+}
+
+// InvalidTxs records [count] invalid txs from [nodeID].
+func (t *PeerTracker) InvalidTxs(nodeID ids.ShortID, count int) {
+	if count == 0 {
+		return
+	}
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	now := time.Now()
+	p := t.get(nodeID, now)
+	p.stats.InvalidTxs += uint64(count)
+	t.invalid(nodeID, p, uint64(count), now)
+}
+
+func (t *PeerTracker) invalid(nodeID ids.ShortID, p *peer, count uint64, now time.Time) {
+	if now.Sub(p.windowStart) > invalidTxsWindow {
+		p.recentInvalid = 0
+		p.windowStart = now
+	}
+	p.recentInvalid += count
+	if p.recentInvalid <= uint64(t.config.GossipMaxInvalidTxs) || p.stats.IgnoredUntil != nil {
+		return
+	}
+	until := now.Add(t.config.GossipIgnoreDuration)
+	p.stats.IgnoredUntil = &until
+	p.recentInvalid = 0
+	log.Warn("ignoring peer", "peerID", nodeID, "until", until, "invalidTxs", p.stats.InvalidTxs)
+}
+
+// Disconnected stops tracking [nodeID] unless it is ignored (so that it can't
+// reset its reputation by reconnecting).
+func (t *PeerTracker) Disconnected(nodeID ids.ShortID) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	p, ok := t.peers[nodeID]
+	if !ok {
+		return
+	}
+	if p.stats.IgnoredUntil == nil || !time.Now().Before(*p.stats.IgnoredUntil) {
+		delete(t.peers, nodeID)
+	}
+}
+
+// Stats returns a copy of the counters of all tracked peers.
+func (t *PeerTracker) Stats() map[ids.ShortID]PeerStats {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	now := time.Now()
+	stats := make(map[ids.ShortID]PeerStats, len(t.peers))
+	for nodeID := range t.peers {
+		stats[nodeID] = t.get(nodeID, now).stats
+	}
+	return stats
+}
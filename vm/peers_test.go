@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestPeerTracker(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		GossipMaxBytesPerSecond: 100,
+		GossipMaxTxsPerSecond:   10,
+		GossipMaxInvalidTxs:     5,
+		GossipIgnoreDuration:    time.Hour,
+	}
+	tracker := NewPeerTracker(config)
+	noisy, quiet := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+
+	// Byte and tx rates are limited per peer
+	if !tracker.Allow(noisy, 100) {
+		t.Fatal("message within limit dropped")
+	}
+	if tracker.Allow(noisy, 50) {
+		t.Fatal("message over limit allowed")
+	}
+	if !tracker.Allow(quiet, 50) {
+		t.Fatal("message from other peer dropped")
+	}
+	if !tracker.AllowTxs(noisy, 10) {
+		t.Fatal("txs within limit dropped")
+	}
+	if tracker.AllowTxs(noisy, 5) {
+		t.Fatal("txs over limit allowed")
+	}
+
+	// Peers sending too many invalid txs are ignored
+	tracker.InvalidTxs(noisy, 3)
+	tracker.InvalidMessage(noisy)
+	if !tracker.Allow(noisy, 0) {
+		t.Fatal("peer ignored below invalid limit")
+	}
+	tracker.InvalidTxs(noisy, 2)
+	if tracker.Allow(noisy, 0) {
+		t.Fatal("peer not ignored")
+	}
+
+	// Ignored peers are remembered after disconnecting
+	tracker.Disconnected(noisy)
+	tracker.Disconnected(quiet)
+	stats := tracker.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("unexpected peers %v", stats)
+	}
+	s := stats[noisy]
+	if s.Messages != 4 || s.Bytes != 150 || s.Txs != 15 || s.InvalidTxs != 5 || s.InvalidMessages != 1 ||
+		s.Throttled != 2 || s.Ignored != 1 || s.IgnoredUntil == nil {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	// Peers are no longer ignored after the ignore duration
+	config.GossipIgnoreDuration = 0
+	tracker.InvalidTxs(quiet, 6)
+	if !tracker.Allow(quiet, 0) {
+		t.Fatal("peer still ignored")
+	}
+}
@@ -4,6 +4,7 @@
 package vm
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database/versiondb"
@@ -17,21 +18,31 @@ func (vm *VM) pruneCall() bool {
 	vm.ctx.Lock.Lock()
 	defer vm.ctx.Lock.Unlock()
 
+	removals, err := vm.pruneNext()
+	if err != nil {
+		log.Warn("unable to prune next range", "error", err)
+		return false
+	}
+	return removals == vm.config.PruneLimit
+}
+
+// pruneNext removes up to [Config.PruneLimit] expired spaces. The caller must
+// hold the context lock.
+func (vm *VM) pruneNext() (int, error) {
 	vdb := versiondb.New(vm.db)
 	defer vdb.Abort()
 	removals, err := chain.PruneNext(vdb, vm.config.PruneLimit)
 	if err != nil {
-		log.Warn("unable to prune next range", "error", err)
-		return false
+		return 0, err
 	}
 	if err := vdb.Commit(); err != nil {
-		log.Warn("unable to commit pruning work", "error", err)
-		return false
+		return 0, fmt.Errorf("unable to commit pruning work: %w", err)
 	}
+	vm.metrics.pruneRemovals.Add(float64(removals))
 	if err := vm.lastAccepted.SetChildrenDB(vm.db); err != nil {
 		log.Error("unable to update child databases of last accepted block", "error", err)
 	}
-	return removals == vm.config.PruneLimit
+	return removals, nil
 }
 
 func (vm *VM) prune() {
@@ -4,10 +4,14 @@
 package vm
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	log "github.com/inconshreveable/log15"
@@ -86,6 +90,10 @@ func (svc *PublicService) IssueRawTx(_ *http.Request, args *IssueRawTxArgs, repl
 type IssueTxArgs struct {
 	TypedData *tdata.TypedData `serialize:"true" json:"typedData"`
 	Signature hexutil.Bytes    `serialize:"true" json:"signature"`
+
+	// Signatures are optional co-signatures over the same typed data, required
+	// to modify spaces controlled by an owner set.
+	Signatures []hexutil.Bytes `serialize:"true" json:"signatures,omitempty"`
 }
 
 type IssueTxReply struct {
@@ -100,7 +108,11 @@ func (svc *PublicService) IssueTx(_ *http.Request, args *IssueTxArgs, reply *Iss
 	if err != nil {
 		return err
 	}
-	tx := chain.NewTx(utx, args.Signature[:])
+	sigs := make([][]byte, len(args.Signatures))
+	for i, sig := range args.Signatures {
+		sigs[i] = sig[:]
+	}
+	tx := chain.NewMultiSigTx(utx, args.Signature[:], sigs)
 
 	// otherwise, unexported tx.id field is empty
 	if err := tx.Init(svc.vm.genesis); err != nil {
@@ -147,13 +159,217 @@ func (svc *PublicService) LastAccepted(_ *http.Request, _ *struct{}, reply *Last
 	return nil
 }
 
+type GetBlockByIDArgs struct {
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+}
+
+type GetBlockByHeightArgs struct {
+	Height uint64 `serialize:"true" json:"height"`
+}
+
+type GetBlockReply struct {
+	BlockID ids.ID               `serialize:"true" json:"blockId"`
+	Block   *chain.StatefulBlock `serialize:"true" json:"block"`
+	// Bytes is the encoded block with all values restored, it can be decoded
+	// with [chain.Unmarshal].
+	Bytes []byte `serialize:"true" json:"bytes"`
+}
+
+// getAcceptedBlock reads [blkID] from disk, so that blocks that are verified
+// but not yet accepted (and may still be rejected) are never returned.
+func (svc *PublicService) getAcceptedBlock(blkID ids.ID) (*chain.StatelessBlock, error) {
+	stBlk, err := chain.GetBlock(svc.vm.db, blkID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrBlockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return chain.ParseStatefulBlock(stBlk, nil, choices.Accepted, svc.vm)
+}
+
+func (svc *PublicService) GetBlockByID(_ *http.Request, args *GetBlockByIDArgs, reply *GetBlockReply) error {
+	blk, err := svc.getAcceptedBlock(args.BlockID)
+	if err != nil {
+		return err
+	}
+	reply.BlockID = blk.ID()
+	reply.Block = blk.StatefulBlock
+	reply.Bytes = blk.Bytes()
+	return nil
+}
+
+func (svc *PublicService) GetBlockByHeight(
+	_ *http.Request,
+	args *GetBlockByHeightArgs,
+	reply *GetBlockReply,
+) error {
+	bid, exists, err := chain.GetBlockIDAtHeight(svc.vm.db, args.Height)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrBlockNotFound
+	}
+	return svc.GetBlockByID(nil, &GetBlockByIDArgs{BlockID: bid}, reply)
+}
+
+// maxStateChunkSize bounds the size of a [StateChunkReply] (unless a single
+// entry is larger).
+const maxStateChunkSize = 2 * units.MiB
+
+// StateSummaryReply is the last state summary. [Summaries] are all the
+// summaries whose state is served, starting with the last one.
+type StateSummaryReply struct {
+	Height    uint64                `serialize:"true" json:"height"`
+	BlockID   ids.ID                `serialize:"true" json:"blockId"`
+	Summaries []*chain.StateSummary `serialize:"true" json:"summaries"`
+}
+
+func (svc *PublicService) StateSummary(_ *http.Request, _ *struct{}, reply *StateSummaryReply) error {
+	summaries, err := chain.GetStateSummaries(svc.vm.db)
+	if err != nil {
+		return err
+	}
+	if len(summaries) == 0 {
+		return ErrNoStateSummary
+	}
+	reply.Height = summaries[0].Height
+	reply.BlockID = summaries[0].BlockID
+	reply.Summaries = summaries
+	return nil
+}
+
+// StateChunkArgs selects the entries of the range with [Prefix] (one of
+// [chain.SyncedPrefixes]) at the state summary at [Height]. [Start] is the
+// [StateChunkReply.Next] value of the previous chunk.
+type StateChunkArgs struct {
+	Height uint64        `serialize:"true" json:"height"`
+	Prefix byte          `serialize:"true" json:"prefix"`
+	Start  hexutil.Bytes `serialize:"true" json:"start"`
+	Limit  int           `serialize:"true" json:"limit"`
+}
+
+type StateChunkReply struct {
+	KeyValues []*chain.KeyValue `serialize:"true" json:"keyValues"`
+	// Next is empty if there are no more entries in the range
+	Next hexutil.Bytes `serialize:"true" json:"next,omitempty"`
+}
+
+func (svc *PublicService) StateChunk(_ *http.Request, args *StateChunkArgs, reply *StateChunkReply) error {
+	limit, err := pageLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+	reply.KeyValues, reply.Next, err = chain.GetStateChunk(
+		svc.vm.db, args.Height, args.Prefix, args.Start, limit, maxStateChunkSize,
+	)
+	if errors.Is(err, chain.ErrStateSummaryMissing) {
+		return ErrStaleStateSummary
+	}
+	return err
+}
+
+type GetTxArgs struct {
+	TxID ids.ID `serialize:"true" json:"txId"`
+}
+
+type GetTxReply struct {
+	TxID      ids.ID             `serialize:"true" json:"txId"`
+	BlockID   ids.ID             `serialize:"true" json:"blockId"`
+	Height    uint64             `serialize:"true" json:"height"`
+	Timestamp int64              `serialize:"true" json:"timestamp"`
+	Sender    common.Address     `serialize:"true" json:"sender"`
+	Tx        *chain.Transaction `serialize:"true" json:"tx"`
+	// Bytes is the encoded transaction with all values restored, it can be
+	// decoded with [chain.Unmarshal].
+	Bytes []byte `serialize:"true" json:"bytes"`
+}
+
+func (svc *PublicService) GetTx(_ *http.Request, args *GetTxArgs, reply *GetTxReply) error {
+	bid, exists, err := chain.GetTransactionBlockID(svc.vm.db, args.TxID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTxNotFound
+	}
+	blk, err := svc.getAcceptedBlock(bid)
+	if err != nil {
+		return err
+	}
+	for _, tx := range blk.Txs {
+		if tx.ID() != args.TxID {
+			continue
+		}
+		reply.TxID = tx.ID()
+		reply.BlockID = bid
+		reply.Height = blk.Hght
+		reply.Timestamp = blk.Tmstmp
+		reply.Sender = tx.Sender()
+		reply.Tx = tx
+		reply.Bytes = tx.Bytes()
+		return nil
+	}
+	return fmt.Errorf("%w: %s not in block %s", ErrTxNotFound, args.TxID, bid)
+}
+
+type PendingTxArgs struct {
+	TxID ids.ID `serialize:"true" json:"txId"`
+}
+
+type PendingTxReply struct {
+	Sender common.Address     `serialize:"true" json:"sender"`
+	Tx     *chain.Transaction `serialize:"true" json:"tx"`
+	// Bytes is the encoded transaction, it can be decoded with
+	// [chain.Unmarshal].
+	Bytes []byte `serialize:"true" json:"bytes"`
+}
+
+// PendingTx returns a transaction in the mempool, which can be replaced by a
+// transaction from the same sender performing the same operation that pays at
+// least [mempool.ReplacementBumpPercent] more.
+func (svc *PublicService) PendingTx(_ *http.Request, args *PendingTxArgs, reply *PendingTxReply) error {
+	tx, ok := svc.vm.mempool.Get(args.TxID)
+	if !ok {
+		return fmt.Errorf("%w: %s not in mempool", ErrTxNotFound, args.TxID)
+	}
+	reply.Sender = tx.Sender()
+	reply.Tx = tx
+	reply.Bytes = tx.Bytes()
+	return nil
+}
+
+type ReceiptArgs struct {
+	TxID ids.ID `serialize:"true" json:"txId"`
+}
+
+type ReceiptReply struct {
+	Accepted bool           `serialize:"true" json:"accepted"`
+	Receipt  *chain.Receipt `serialize:"true" json:"receipt,omitempty"`
+}
+
+func (svc *PublicService) Receipt(_ *http.Request, args *ReceiptArgs, reply *ReceiptReply) error {
+	r, exists, err := chain.GetReceipt(svc.vm.db, args.TxID)
+	if err != nil {
+		return err
+	}
+	reply.Accepted = exists
+	reply.Receipt = r
+	return nil
+}
+
 type SuggestedFeeArgs struct {
 	Input *chain.Input `serialize:"true" json:"input"`
+	// Urgency is optional. If set, the fee is estimated for the tx to be
+	// included within a number of blocks (see [VM.SuggestedUrgentFee]).
+	Urgency Urgency `serialize:"true" json:"urgency,omitempty"`
 }
 
 type SuggestedFeeReply struct {
 	TypedData *tdata.TypedData `serialize:"true" json:"typedData"`
-	TotalCost uint64           `serialize:"true" json:"totalCost"`
+	// TotalCost is the most the tx can cost the sender.
+	TotalCost uint64 `serialize:"true" json:"totalCost"`
 }
 
 func (svc *PublicService) SuggestedFee(
@@ -164,27 +380,101 @@ func (svc *PublicService) SuggestedFee(
 	if args.Input == nil {
 		return ErrInputIsNil
 	}
-	utx, err := args.Input.Decode()
+	utx, err := svc.decodeInput(args.Input, args.Urgency)
 	if err != nil {
 		return err
 	}
+	reply.TypedData = utx.TypedData()
+	reply.TotalCost = chain.MaxFee(utx, utx.FeeUnits(svc.vm.genesis))
+	return nil
+}
+
+// decodeInput returns the tx described by [input] at the suggested fee (for
+// [urgency], if not empty).
+func (svc *PublicService) decodeInput(input *chain.Input, urgency Urgency) (chain.UnsignedTransaction, error) {
+	utx, err := input.Decode()
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine suggested fee
-	price, cost, err := svc.vm.SuggestedFee()
+	estimate := &FeeEstimate{PriorityTip: chain.DefaultPriorityTip}
+	if len(urgency) > 0 {
+		estimate, err = svc.vm.SuggestedUrgentFee(urgency)
+	} else {
+		estimate.Price, estimate.Cost, err = svc.vm.SuggestedFee()
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 	g := svc.vm.genesis
-	fu := utx.FeeUnits(g)
-	price += cost / fu
 
 	// Update meta
 	utx.SetBlockID(svc.vm.lastAccepted.ID())
 	utx.SetMagic(g.Magic)
-	utx.SetPrice(price)
+	chain.SetFee(g, utx, estimate.Price, estimate.Cost, estimate.PriorityTip)
+	return utx, nil
+}
+
+type SimulateArgs struct {
+	// Either TypedData (as returned by "suggestedFee") or Input (which is
+	// simulated at the suggested fee) must be set.
+	TypedData *tdata.TypedData `serialize:"true" json:"typedData,omitempty"`
+	Input     *chain.Input     `serialize:"true" json:"input,omitempty"`
+
+	// Sender pays the fee and Cosigners are the other signers of a tx on a
+	// space controlled by an owner set. No signature is required.
+	Sender    common.Address   `serialize:"true" json:"sender"`
+	Cosigners []common.Address `serialize:"true" json:"cosigners,omitempty"`
+}
+
+type SimulateReply struct {
+	// Error is set if the tx would fail, in which case [Changes] is empty.
+	Error     string               `serialize:"true" json:"error,omitempty"`
+	FeeUnits  uint64               `serialize:"true" json:"feeUnits"`
+	Price     uint64               `serialize:"true" json:"price"`
+	TotalCost uint64               `serialize:"true" json:"totalCost"`
+	Changes   []*chain.StateChange `serialize:"true" json:"changes"`
+}
+
+// Simulate executes a tx against the preferred state without signing or
+// submitting it.
+func (svc *PublicService) Simulate(_ *http.Request, args *SimulateArgs, reply *SimulateReply) error {
+	var (
+		utx chain.UnsignedTransaction
+		err error
+	)
+	switch {
+	case args.TypedData != nil:
+		utx, err = chain.ParseTypedData(args.TypedData)
+	case args.Input != nil:
+		utx, err = svc.decodeInput(args.Input, "")
+	default:
+		return ErrInputIsNil
+	}
+	if err != nil {
+		return err
+	}
 
-	reply.TypedData = utx.TypedData()
-	reply.TotalCost = fu * price
+	g := svc.vm.genesis
+	tx := chain.NewTx(utx, nil)
+	if err := tx.InitUnsigned(g, args.Sender, args.Cosigners); err != nil {
+		return err
+	}
+	price, _, err := svc.vm.SuggestedFee()
+	if err != nil {
+		return err
+	}
+	reply.FeeUnits = tx.FeeUnits(g)
+	reply.Price = chain.EffectivePrice(utx, price)
+	reply.TotalCost = reply.FeeUnits * reply.Price
+	changes, err := svc.vm.Simulate(tx)
+	if err != nil {
+		reply.Error = err.Error()
+		reply.Changes = []*chain.StateChange{}
+		return nil
+	}
+	reply.Changes = changes
 	return nil
 }
 
@@ -207,6 +497,26 @@ func (svc *PublicService) SuggestedRawFee(
 	return nil
 }
 
+type FeeHistoryArgs struct {
+	// Blocks is the number of blocks to return (100 by default, at most
+	// 1024).
+	Blocks int `serialize:"true" json:"blocks"`
+}
+
+type FeeHistoryReply struct {
+	// Blocks is ordered from the oldest to the last accepted block.
+	Blocks []*BlockFee `serialize:"true" json:"blocks"`
+}
+
+func (svc *PublicService) FeeHistory(_ *http.Request, args *FeeHistoryArgs, reply *FeeHistoryReply) error {
+	blocks, err := pageLimit(args.Blocks)
+	if err != nil {
+		return err
+	}
+	reply.Blocks, err = svc.vm.FeeHistory(blocks)
+	return err
+}
+
 type ClaimedArgs struct {
 	Space string `serialize:"true" json:"space"`
 }
@@ -229,11 +539,20 @@ func (svc *PublicService) Claimed(_ *http.Request, args *ClaimedArgs, reply *Cla
 
 type InfoArgs struct {
 	Space string `serialize:"true" json:"space"`
+	// Proof requests a proof of [InfoReply.Info] against the last accepted
+	// state root
+	Proof bool `serialize:"true" json:"proof"`
 }
 
 type InfoReply struct {
-	Info   *chain.SpaceInfo      `serialize:"true" json:"info"`
+	Info *chain.SpaceInfo `serialize:"true" json:"info"`
+	// Values is limited to the first [maxPageLimit] keys, use [ListKeys] to
+	// page through the rest.
 	Values []*chain.KeyValueMeta `serialize:"true" json:"values"`
+	// Next is the last key in [Values] if there are more keys in the space
+	Next string `serialize:"true" json:"next,omitempty"`
+
+	Proof *chain.StateProof `serialize:"true" json:"proof,omitempty"`
 }
 
 func (svc *PublicService) Info(_ *http.Request, args *InfoArgs, reply *InfoReply) error {
@@ -249,23 +568,72 @@ func (svc *PublicService) Info(_ *http.Request, args *InfoArgs, reply *InfoReply
 		return chain.ErrSpaceMissing
 	}
 
-	kvs, err := chain.GetAllValueMetas(svc.vm.db, i.RawSpace)
+	kvs, next, err := chain.GetValueMetas(svc.vm.db, i.RawSpace, nil, nil, svc.vm.acceptedTime(), maxPageLimit)
 	if err != nil {
 		return err
 	}
 	reply.Info = i
 	reply.Values = kvs
-	return nil
+	reply.Next = next
+	if args.Proof {
+		reply.Proof, err = svc.prove(chain.ProveSpace(svc.vm.db, []byte(args.Space)))
+	}
+	return err
+}
+
+type ListKeysArgs struct {
+	Space string `serialize:"true" json:"space"`
+	// Prefix only includes keys that start with it
+	Prefix string `serialize:"true" json:"prefix"`
+	// StartAfter is the [ListKeysReply.Next] value of the previous page
+	StartAfter string `serialize:"true" json:"startAfter"`
+	Limit      int    `serialize:"true" json:"limit"`
+}
+
+type ListKeysReply struct {
+	// Keys are sorted lexicographically
+	Keys []*chain.KeyValueMeta `serialize:"true" json:"keys"`
+	// Next is empty if there are no more keys
+	Next string `serialize:"true" json:"next,omitempty"`
+}
+
+func (svc *PublicService) ListKeys(_ *http.Request, args *ListKeysArgs, reply *ListKeysReply) error {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+	limit, err := pageLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+
+	i, exists, err := chain.GetSpaceInfo(svc.vm.db, []byte(args.Space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return chain.ErrSpaceMissing
+	}
+
+	reply.Keys, reply.Next, err = chain.GetValueMetas(
+		svc.vm.db, i.RawSpace, []byte(args.Prefix), []byte(args.StartAfter), svc.vm.acceptedTime(), limit,
+	)
+	return err
 }
 
 type ResolveArgs struct {
 	Path string `serialize:"true" json:"path"`
+	// Proof requests a proof of the value (or its absence) against the last
+	// accepted state root
+	Proof bool `serialize:"true" json:"proof"`
 }
 
 type ResolveReply struct {
-	Exists    bool             `serialize:"true" json:"exists"`
-	Value     []byte           `serialize:"true" json:"value"`
+	Exists bool   `serialize:"true" json:"exists"`
+	Value  []byte `serialize:"true" json:"value"`
+	// ValueMeta is also set for expired values if a proof was requested
 	ValueMeta *chain.ValueMeta `serialize:"true" json:"valueMeta"`
+
+	Proof *chain.StateProof `serialize:"true" json:"proof,omitempty"`
 }
 
 func (svc *PublicService) Resolve(_ *http.Request, args *ResolveArgs, reply *ResolveReply) error {
@@ -274,12 +642,26 @@ func (svc *PublicService) Resolve(_ *http.Request, args *ResolveArgs, reply *Res
 		return err
 	}
 
+	if args.Proof {
+		reply.Proof, err = svc.prove(chain.ProveValue(svc.vm.db, []byte(space), []byte(key)))
+		if err != nil {
+			return err
+		}
+	}
+
 	vmeta, exists, err := chain.GetValueMeta(svc.vm.db, []byte(space), []byte(key))
 	if err != nil {
 		return err
 	}
 	if !exists {
-		// Avoid value lookup if doesn't exist
+		return nil
+	}
+	if vmeta.Expired(svc.vm.acceptedTime()) {
+		// Avoid value lookup if has expired but not yet been removed (the
+		// proof still includes it)
+		if args.Proof {
+			reply.ValueMeta = vmeta
+		}
 		return nil
 	}
 	v, exists, err := chain.GetValue(svc.vm.db, []byte(space), []byte(key))
@@ -299,10 +681,14 @@ func (svc *PublicService) Resolve(_ *http.Request, args *ResolveArgs, reply *Res
 
 type BalanceArgs struct {
 	Address common.Address `serialize:"true" json:"address"`
+	// Proof requests a proof of the balance against the last accepted state
+	// root
+	Proof bool `serialize:"true" json:"proof"`
 }
 
 type BalanceReply struct {
-	Balance uint64 `serialize:"true" json:"balance"`
+	Balance uint64            `serialize:"true" json:"balance"`
+	Proof   *chain.StateProof `serialize:"true" json:"proof,omitempty"`
 }
 
 func (svc *PublicService) Balance(_ *http.Request, args *BalanceArgs, reply *BalanceReply) error {
@@ -311,9 +697,25 @@ func (svc *PublicService) Balance(_ *http.Request, args *BalanceArgs, reply *Bal
 		return err
 	}
 	reply.Balance = bal
+	if args.Proof {
+		reply.Proof, err = svc.prove(chain.ProveBalance(svc.vm.db, args.Address))
+	}
 	return err
 }
 
+// prove ties [p] to the last accepted block.
+func (svc *PublicService) prove(p *chain.StateProof, err error) (*chain.StateProof, error) {
+	if err != nil {
+		return nil, err
+	}
+	la := svc.vm.lastAccepted
+	if p.Root != la.StateRoot {
+		return nil, fmt.Errorf("%w: state root %s does not match last accepted %s", ErrCorruption, p.Root, la.StateRoot)
+	}
+	p.BlockID = la.ID()
+	return p, nil
+}
+
 type RecentActivityReply struct {
 	Activity []*chain.Activity `serialize:"true" json:"activity"`
 }
@@ -340,6 +742,85 @@ func (svc *PublicService) RecentActivity(_ *http.Request, _ *struct{}, reply *Re
 	return nil
 }
 
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1024
+)
+
+// pageLimit returns the number of results to return for a requested [limit]
+// (0 for the default).
+func pageLimit(limit int) (int, error) {
+	switch {
+	case limit < 0 || limit > maxPageLimit:
+		return 0, fmt.Errorf("%w: limit must be in [0, %d]", ErrInvalidLimit, maxPageLimit)
+	case limit == 0:
+		return defaultPageLimit, nil
+	default:
+		return limit, nil
+	}
+}
+
+// ActivityPage selects activity in [Since, Until] (all activity after [Since]
+// if [Until] is 0). [Cursor] is the [ActivityReply.Next] value of the previous
+// page.
+type ActivityPage struct {
+	Since  uint64        `serialize:"true" json:"since"`
+	Until  uint64        `serialize:"true" json:"until"`
+	Cursor hexutil.Bytes `serialize:"true" json:"cursor"`
+	Limit  int           `serialize:"true" json:"limit"`
+}
+
+type ActivityReply struct {
+	// Activity is sorted from oldest to most recent
+	Activity []*chain.Activity `serialize:"true" json:"activity"`
+	// Next is empty if there are no more results
+	Next hexutil.Bytes `serialize:"true" json:"next,omitempty"`
+}
+
+func (svc *PublicService) ActivityRange(_ *http.Request, args *ActivityPage, reply *ActivityReply) error {
+	limit, err := pageLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+	reply.Activity, reply.Next, err = chain.GetActivityRange(svc.vm.db, args.Since, args.Until, args.Cursor, limit)
+	return err
+}
+
+type AddressActivityArgs struct {
+	Address      common.Address `serialize:"true" json:"address"`
+	ActivityPage `serialize:"true"`
+}
+
+func (svc *PublicService) AddressActivity(_ *http.Request, args *AddressActivityArgs, reply *ActivityReply) error {
+	limit, err := pageLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+	reply.Activity, reply.Next, err = chain.GetAddressActivity(
+		svc.vm.db, args.Address, args.Since, args.Until, args.Cursor, limit,
+	)
+	return err
+}
+
+type SpaceActivityArgs struct {
+	Space        string `serialize:"true" json:"space"`
+	ActivityPage `serialize:"true"`
+}
+
+func (svc *PublicService) SpaceActivity(_ *http.Request, args *SpaceActivityArgs, reply *ActivityReply) error {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+	limit, err := pageLimit(args.Limit)
+	if err != nil {
+		return err
+	}
+	reply.Activity, reply.Next, err = chain.GetSpaceActivity(
+		svc.vm.db, []byte(args.Space), args.Since, args.Until, args.Cursor, limit,
+	)
+	return err
+}
+
 type OwnedArgs struct {
 	Address common.Address `serialize:"true" json:"address"`
 }
@@ -356,3 +837,76 @@ func (svc *PublicService) Owned(_ *http.Request, args *OwnedArgs, reply *OwnedRe
 	reply.Spaces = spaces
 	return nil
 }
+
+type PermissionsArgs struct {
+	Space string `serialize:"true" json:"space"`
+}
+
+type PermissionsReply struct {
+	Permissions []*chain.Permission `serialize:"true" json:"permissions"`
+}
+
+func (svc *PublicService) Permissions(_ *http.Request, args *PermissionsArgs, reply *PermissionsReply) error {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+
+	i, exists, err := chain.GetSpaceInfo(svc.vm.db, []byte(args.Space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return chain.ErrSpaceMissing
+	}
+
+	perms, err := chain.GetAllPermissions(svc.vm.db, i.RawSpace)
+	if err != nil {
+		return err
+	}
+	reply.Permissions = perms
+	return nil
+}
+
+type AuctionArgs struct {
+	Space string `serialize:"true" json:"space"`
+}
+
+type AuctionReply struct {
+	Auction *chain.Auction    `serialize:"true" json:"auction"`
+	Bids    []*chain.SpaceBid `serialize:"true" json:"bids"`
+}
+
+func (svc *PublicService) Auction(_ *http.Request, args *AuctionArgs, reply *AuctionReply) error {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+
+	a, exists, err := chain.GetAuction(svc.vm.db, []byte(args.Space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return chain.ErrAuctionMissing
+	}
+
+	bids, err := chain.GetAllBids(svc.vm.db, []byte(args.Space))
+	if err != nil {
+		return err
+	}
+	reply.Auction = a
+	reply.Bids = bids
+	return nil
+}
+
+type AuctionsReply struct {
+	Auctions []*chain.Auction `serialize:"true" json:"auctions"`
+}
+
+func (svc *PublicService) Auctions(_ *http.Request, _ *struct{}, reply *AuctionsReply) error {
+	auctions, err := chain.GetOpenAuctions(svc.vm.db)
+	if err != nil {
+		return err
+	}
+	reply.Auctions = auctions
+	return nil
+}
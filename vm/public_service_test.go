@@ -0,0 +1,261 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/mempool"
+)
+
+func TestSimulate(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: sender, Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+	svc := &PublicService{vm: vm}
+
+	if err := svc.Simulate(nil, &SimulateArgs{Sender: sender}, new(SimulateReply)); !errors.Is(err, ErrInputIsNil) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Claims are simulated from an input at the suggested fee
+	reply := new(SimulateReply)
+	if err := svc.Simulate(nil, &SimulateArgs{
+		Input:  &chain.Input{Typ: chain.Claim, Space: "hello"},
+		Sender: sender,
+	}, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Error) > 0 || reply.FeeUnits == 0 || reply.TotalCost != reply.FeeUnits*reply.Price {
+		t.Fatalf("unexpected reply %+v", reply)
+	}
+	changes := map[string]*chain.StateChange{}
+	for _, c := range reply.Changes {
+		changes[c.Type] = c
+	}
+	if c := changes["info"]; c == nil || c.Before != nil || c.After == nil {
+		t.Fatalf("space info not created %+v", c)
+	}
+	if c := changes["balance"]; c == nil || c.Before == nil || c.After == nil {
+		t.Fatalf("fee not charged %+v", c)
+	}
+
+	// Nothing is submitted or written
+	if vm.mempool.Len() != 0 {
+		t.Fatal("simulated tx added to mempool")
+	}
+	if bal, err := chain.GetBalance(vm.db, sender); err != nil || bal != 10000000 {
+		t.Fatalf("unexpected balance %d (%v)", bal, err)
+	}
+
+	// Failures are reported in the reply
+	reply = new(SimulateReply)
+	if err := svc.Simulate(nil, &SimulateArgs{
+		Input:  &chain.Input{Typ: chain.Claim, Space: "hello"},
+		Sender: common.Address{0x1},
+	}, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Error) == 0 || len(reply.Changes) != 0 {
+		t.Fatalf("unexpected reply %+v", reply)
+	}
+
+	// Typed data (as signed by the sender) is simulated as is
+	fee := new(SuggestedFeeReply)
+	if err := svc.SuggestedFee(nil, &SuggestedFeeArgs{
+		Input: &chain.Input{Typ: chain.Transfer, To: common.Address{0x1}, Units: 10},
+	}, fee); err != nil {
+		t.Fatal(err)
+	}
+	reply = new(SimulateReply)
+	if err := svc.Simulate(nil, &SimulateArgs{TypedData: fee.TypedData, Sender: sender}, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Error) > 0 || reply.TotalCost != fee.TotalCost || len(reply.Changes) != 2 {
+		t.Fatalf("unexpected reply %+v", reply)
+	}
+}
+
+func TestFeeHistory(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: sender, Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+	svc := &PublicService{vm: vm}
+
+	if err := svc.FeeHistory(nil, &FeeHistoryArgs{Blocks: -1}, new(FeeHistoryReply)); !errors.Is(err, ErrInvalidLimit) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	tx := newClaimTx(t, vm, priv, "hello", genesis.MinPrice)
+	if errs := vm.Submit(tx); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	reply := new(FeeHistoryReply)
+	if err := svc.FeeHistory(nil, &FeeHistoryArgs{Blocks: 5}, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(reply.Blocks))
+	}
+	if b := reply.Blocks[0]; b.Height != 0 || b.Txs != 0 || b.LoadUnits != 0 {
+		t.Fatalf("unexpected genesis fees %+v", b)
+	}
+	units := tx.LoadUnits(genesis)
+	b := reply.Blocks[1]
+	if b.Height != 1 || b.BlockID != blk.ID() || b.Txs != 1 || b.LoadUnits != units ||
+		b.FillRatio != float64(units)/float64(genesis.MaxBlockSize) {
+		t.Fatalf("unexpected block fees %+v", b)
+	}
+
+	// Only the last accepted blocks are returned
+	reply = new(FeeHistoryReply)
+	if err := svc.FeeHistory(nil, &FeeHistoryArgs{Blocks: 1}, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Blocks) != 1 || reply.Blocks[0].Height != 1 {
+		t.Fatalf("unexpected blocks %+v", reply.Blocks)
+	}
+}
+
+func TestSuggestedUrgentFee(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: crypto.PubkeyToAddress(priv.PublicKey), Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+
+	if _, err := vm.SuggestedUrgentFee("asap"); !errors.Is(err, ErrInvalidUrgency) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Without any activity, all txs can be included at the min price
+	for _, u := range []Urgency{UrgencySlow, UrgencyNormal, UrgencyFast} {
+		estimate, err := vm.SuggestedUrgentFee(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if estimate.Price != genesis.MinPrice || estimate.PriorityTip != 0 {
+			t.Fatalf("%s: unexpected estimate %+v", u, estimate)
+		}
+	}
+
+	// Fill the mempool with more than a block of txs
+	units := uint64(0)
+	for i := 0; units <= genesis.MaxBlockSize; i++ {
+		tx := newClaimTx(t, vm, priv, fmt.Sprintf("space%d", i), 10)
+		vm.mempool.Add(tx)
+		units += tx.LoadUnits(genesis)
+	}
+	for u, price := range map[Urgency]uint64{
+		UrgencySlow:   genesis.MinPrice,
+		UrgencyNormal: genesis.MinPrice,
+		UrgencyFast:   11,
+	} {
+		estimate, err := vm.SuggestedUrgentFee(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if estimate.Price != price {
+			t.Fatalf("%s: price expected %d, got %d", u, price, estimate.Price)
+		}
+	}
+}
+
+func TestPendingTx(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: sender, Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+	svc := &PublicService{vm: vm}
+
+	orig := newClaimTx(t, vm, priv, "hello", 20)
+	if errs := vm.Submit(orig); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	reply := new(PendingTxReply)
+	if err := svc.PendingTx(nil, &PendingTxArgs{TxID: orig.ID()}, reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Sender != sender || reply.Tx.ID() != orig.ID() {
+		t.Fatalf("unexpected reply %+v", reply)
+	}
+
+	// Claiming the same space must pay at least 10% more
+	errs := vm.Submit(newClaimTx(t, vm, priv, "hello", 21))
+	if len(errs) != 1 || !errors.Is(errs[0], mempool.ErrReplacementUnderpriced) {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	replacement := newClaimTx(t, vm, priv, "hello", mempool.Bump(20))
+	if errs := vm.Submit(replacement); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if err := svc.PendingTx(nil, &PendingTxArgs{TxID: orig.ID()}, new(PendingTxReply)); !errors.Is(err, ErrTxNotFound) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := svc.PendingTx(nil, &PendingTxArgs{TxID: replacement.ID()}, new(PendingTxReply)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// maxKnownTxs bounds the number of transaction IDs in a [TxsRequest].
+const maxKnownTxs = 4096
+
+// TxsRequest is sent in an "AppRequest" to pull the transactions that a peer
+// has and the requester does not.
+type TxsRequest struct {
+	// Known are the IDs of the transactions in the mempool of the requester.
+	Known []ids.ID `serialize:"true" json:"known"`
+}
+
+// PullNetwork periodically requests the transactions in the mempool of a
+// random peer that are missing from ours, so that a node that missed a push
+// doesn't have to wait for the next regossip.
+//
+// Peers respond with the transactions (up to the target units of a block)
+// that are not in the request, from the highest to the lowest price.
+type PullNetwork struct {
+	vm *VM
+
+	// [l] must be held when accessing the fields below
+	l         sync.Mutex
+	peers     ids.ShortSet
+	requestID uint32
+	inflight  map[uint32]*pullRequest
+}
+
+type pullRequest struct {
+	nodeID ids.ShortID
+	expiry time.Time
+}
+
+func (vm *VM) NewPullNetwork() *PullNetwork {
+	return &PullNetwork{
+		vm:       vm,
+		peers:    ids.ShortSet{},
+		inflight: map[uint32]*pullRequest{},
+	}
+}
+
+func (n *PullNetwork) Connected(nodeID ids.ShortID) {
+	n.l.Lock()
+	defer n.l.Unlock()
+	n.peers.Add(nodeID)
+}
+
+func (n *PullNetwork) Disconnected(nodeID ids.ShortID) {
+	n.l.Lock()
+	defer n.l.Unlock()
+	n.peers.Remove(nodeID)
+}
+
+// Inflight returns the number of requests awaiting a response.
+func (n *PullNetwork) Inflight() int {
+	n.l.Lock()
+	defer n.l.Unlock()
+	return len(n.inflight)
+}
+
+// PullTxs requests the transactions missing from our mempool from a random
+// peer without a request in flight (if there are fewer than
+// [Config.PullGossipMaxInflight] requests in flight).
+func (n *PullNetwork) PullTxs() error {
+	if n.vm.appSender == nil {
+		return nil
+	}
+	nodeID, requestID, ok := n.reserve(time.Now())
+	if !ok {
+		return nil
+	}
+
+	txs := n.vm.mempool.Txs()
+	if len(txs) > maxKnownTxs {
+		txs = txs[:maxKnownTxs]
+	}
+	req := &TxsRequest{Known: make([]ids.ID, len(txs))}
+	for i, tx := range txs {
+		req.Known[i] = tx.ID()
+	}
+	b, err := chain.Marshal(req)
+	if err != nil {
+		n.release(requestID)
+		return err
+	}
+
+	log.Debug("sending AppRequest", "peerID", nodeID, "requestID", requestID, "known", len(req.Known))
+	if err := n.vm.appSender.SendAppRequest(ids.ShortSet{nodeID: struct{}{}}, requestID, b); err != nil {
+		log.Warn("PullTxs failed", "error", err)
+		n.release(requestID)
+		return err
+	}
+	return nil
+}
+
+// reserve selects the peer to send the next request to and records the
+// request as in flight.
+func (n *PullNetwork) reserve(now time.Time) (ids.ShortID, uint32, bool) {
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	// The engine should always notify us of failed requests, but we don't
+	// want to stop pulling if it doesn't.
+	busy := ids.ShortSet{}
+	for requestID, req := range n.inflight {
+		if now.After(req.expiry) {
+			delete(n.inflight, requestID)
+			continue
+		}
+		busy.Add(req.nodeID)
+	}
+	if len(n.inflight) >= n.vm.config.PullGossipMaxInflight {
+		return ids.ShortID{}, 0, false
+	}
+
+	candidates := make([]ids.ShortID, 0, n.peers.Len())
+	for nodeID := range n.peers {
+		if !busy.Contains(nodeID) {
+			candidates = append(candidates, nodeID)
+		}
+	}
+	if len(candidates) == 0 {
+		return ids.ShortID{}, 0, false
+	}
+	nodeID := candidates[rand.Intn(len(candidates))] // #nosec G404
+
+	n.requestID++
+	n.inflight[n.requestID] = &pullRequest{
+		nodeID: nodeID,
+		expiry: now.Add(n.vm.config.PullGossipTimeout),
+	}
+	return nodeID, n.requestID, true
+}
+
+// release removes the request with [requestID] from the requests in flight.
+func (n *PullNetwork) release(requestID uint32) {
+	n.l.Lock()
+	defer n.l.Unlock()
+	delete(n.inflight, requestID)
+}
+
+// complete removes the request with [requestID] from the requests in flight
+// if it was sent to [nodeID].
+func (n *PullNetwork) complete(nodeID ids.ShortID, requestID uint32) bool {
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	req, ok := n.inflight[requestID]
+	if !ok || req.nodeID != nodeID {
+		return false
+	}
+	delete(n.inflight, requestID)
+	return true
+}
+
+// HandleRequest responds to a [TxsRequest] from [nodeID] (unless
+// [deadline] has already passed).
+func (n *PullNetwork) HandleRequest(nodeID ids.ShortID, requestID uint32, deadline time.Time, msg []byte) error {
+	if time.Now().After(deadline) {
+		log.Debug("dropping expired AppRequest", "peerID", nodeID, "requestID", requestID)
+		return nil
+	}
+	if !n.vm.peers.Allow(nodeID, len(msg)) {
+		log.Debug("dropping AppRequest", "peerID", nodeID, "requestID", requestID, "bytes", len(msg))
+		return nil
+	}
+	req := new(TxsRequest)
+	if _, err := chain.Unmarshal(msg, req); err != nil {
+		log.Debug("AppRequest provided invalid request", "peerID", nodeID, "err", err)
+		n.vm.peers.InvalidMessage(nodeID)
+		return nil
+	}
+	if len(req.Known) > maxKnownTxs {
+		log.Debug("AppRequest provided too many known txs", "peerID", nodeID, "known", len(req.Known))
+		n.vm.peers.InvalidMessage(nodeID)
+		return nil
+	}
+
+	known := ids.NewSet(len(req.Known))
+	known.Add(req.Known...)
+	txs := []*chain.Transaction{}
+	units := uint64(0)
+	for _, tx := range n.vm.mempool.Txs() {
+		if known.Contains(tx.ID()) {
+			continue
+		}
+		txUnits := tx.LoadUnits(n.vm.genesis)
+		if units+txUnits > n.vm.genesis.TargetBlockSize {
+			break
+		}
+		units += txUnits
+		txs = append(txs, tx)
+	}
+
+	// Respond even if there are no txs so that the requester can send its
+	// next request
+	b, err := chain.Marshal(txs)
+	if err != nil {
+		log.Warn("failed to marshal txs", "error", err)
+		return nil
+	}
+	log.Debug("sending AppResponse", "peerID", nodeID, "requestID", requestID, "txs", len(txs))
+	if err := n.vm.appSender.SendAppResponse(nodeID, requestID, b); err != nil {
+		log.Warn("AppResponse failed", "error", err)
+	}
+	return nil
+}
+
+// HandleResponse submits the transactions in the response to the request
+// with [requestID] (if it was sent to [nodeID]).
+func (n *PullNetwork) HandleResponse(nodeID ids.ShortID, requestID uint32, msg []byte) error {
+	if !n.complete(nodeID, requestID) {
+		log.Debug("dropping unexpected AppResponse", "peerID", nodeID, "requestID", requestID)
+		return nil
+	}
+
+	if !n.vm.peers.Allow(nodeID, len(msg)) {
+		log.Debug("dropping AppResponse", "peerID", nodeID, "requestID", requestID, "bytes", len(msg))
+		return nil
+	}
+
+	txs := make([]*chain.Transaction, 0)
+	if _, err := chain.Unmarshal(msg, &txs); err != nil {
+		log.Debug("AppResponse provided invalid txs", "peerID", nodeID, "err", err)
+		n.vm.peers.InvalidMessage(nodeID)
+		return nil
+	}
+	n.vm.submitRemote(nodeID, txs)
+	return nil
+}
+
+// HandleFailed frees the slot of the request with [requestID].
+func (n *PullNetwork) HandleFailed(nodeID ids.ShortID, requestID uint32) error {
+	n.complete(nodeID, requestID)
+	return nil
+}
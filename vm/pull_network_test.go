@@ -0,0 +1,290 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	avagoversion "github.com/ava-labs/avalanchego/version"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+var _ common.AppSender = &fakeAppSender{}
+
+type appMsg struct {
+	nodeIDs   ids.ShortSet
+	requestID uint32
+	bytes     []byte
+}
+
+// fakeAppSender records the messages sent by a VM so that tests can deliver
+// them (or not) to other VMs.
+type fakeAppSender struct {
+	l         sync.Mutex
+	requests  []*appMsg
+	responses []*appMsg
+}
+
+func (s *fakeAppSender) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, b []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.requests = append(s.requests, &appMsg{nodeIDs: nodeIDs, requestID: requestID, bytes: b})
+	return nil
+}
+
+func (s *fakeAppSender) SendAppResponse(nodeID ids.ShortID, requestID uint32, b []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.responses = append(s.responses, &appMsg{nodeIDs: ids.ShortSet{nodeID: struct{}{}}, requestID: requestID, bytes: b})
+	return nil
+}
+
+func (s *fakeAppSender) SendAppGossip([]byte) error                       { return nil }
+func (s *fakeAppSender) SendAppGossipSpecific(ids.ShortSet, []byte) error { return nil }
+
+// next pops the oldest recorded message of [msgs].
+func (s *fakeAppSender) next(t *testing.T, msgs *[]*appMsg) *appMsg {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if len(*msgs) == 0 {
+		t.Fatal("no message sent")
+	}
+	msg := (*msgs)[0]
+	*msgs = (*msgs)[1:]
+	return msg
+}
+
+func (s *fakeAppSender) sent() (int, int) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return len(s.requests), len(s.responses)
+}
+
+func newTestVM(t *testing.T, genesisBytes []byte, sender common.AppSender) *VM {
+	ctx := &snow.Context{
+		NetworkID: 1,
+		SubnetID:  ids.Empty,
+		ChainID:   ids.Empty,
+		NodeID:    ids.GenerateTestShortID(),
+	}
+	vm := &VM{}
+	if err := vm.Initialize(
+		ctx,
+		manager.NewMemDB(avagoversion.CurrentDatabase),
+		genesisBytes,
+		nil,
+		[]byte(`{"stateSyncInterval":0}`),
+		make(chan common.Message, 1),
+		nil,
+		sender,
+	); err != nil {
+		t.Fatal(err)
+	}
+	vm.SetBlockBuilder(func() BlockBuilder { return vm.NewManualBuilder() })
+	t.Cleanup(func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Error(err)
+		}
+	})
+	return vm
+}
+
+func newClaimTx(t *testing.T, vm *VM, priv *ecdsa.PrivateKey, space string, price uint64) *chain.Transaction {
+	utx := &chain.ClaimTx{
+		BaseTx: &chain.BaseTx{
+			BlockID: vm.preferred,
+			Magic:   vm.genesis.Magic,
+			Price:   price,
+		},
+		Space: space,
+	}
+	dh, err := chain.DigestHash(utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.Sign(dh, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := chain.NewTx(utx, sig)
+	if err := tx.Init(vm.genesis); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestPullNetwork(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: crypto.PubkeyToAddress(priv.PublicKey), Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender0, sender1 := &fakeAppSender{}, &fakeAppSender{}
+	vm0, vm1 := newTestVM(t, genesisBytes, sender0), newTestVM(t, genesisBytes, sender1)
+	node0, node1 := vm0.ctx.NodeID, vm1.ctx.NodeID
+	version := avagoversion.NewDefaultApplication("spacesvm", 1, 0, 0)
+	if err := vm0.Connected(node1, version); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm1.Connected(node0, version); err != nil {
+		t.Fatal(err)
+	}
+
+	// vm1 missed one of the txs in the mempool of vm0
+	known := newClaimTx(t, vm0, priv, "known", 1)
+	missed := newClaimTx(t, vm0, priv, "missed", 2)
+	if errs := vm0.Submit(known, missed); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if errs := vm1.Submit(known); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	// vm1 pulls only the missing tx from vm0
+	if err := vm1.pull.PullTxs(); err != nil {
+		t.Fatal(err)
+	}
+	req := sender1.next(t, &sender1.requests)
+	if !req.nodeIDs.Contains(node0) || req.nodeIDs.Len() != 1 {
+		t.Fatalf("unexpected request recipients %v", req.nodeIDs)
+	}
+	if err := vm0.AppRequest(node1, req.requestID, time.Now().Add(time.Second), req.bytes); err != nil {
+		t.Fatal(err)
+	}
+	resp := sender0.next(t, &sender0.responses)
+	txs := []*chain.Transaction{}
+	if _, err := chain.Unmarshal(resp.bytes, &txs); err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("unexpected txs %v", txs)
+	}
+	if err := txs[0].Init(vm1.genesis); err != nil {
+		t.Fatal(err)
+	}
+	if txs[0].ID() != missed.ID() {
+		t.Fatalf("unexpected txs %v", txs)
+	}
+	if err := vm1.AppResponse(node0, resp.requestID, resp.bytes); err != nil {
+		t.Fatal(err)
+	}
+	if vm1.mempool.Len() != 2 || !vm1.mempool.Has(missed.ID()) {
+		t.Fatal("missed tx not added to mempool")
+	}
+	if vm1.pull.Inflight() != 0 {
+		t.Fatal("request still in flight")
+	}
+
+	// An up-to-date node still receives a response
+	if err := vm1.pull.PullTxs(); err != nil {
+		t.Fatal(err)
+	}
+	req = sender1.next(t, &sender1.requests)
+	if err := vm0.AppRequest(node1, req.requestID, time.Now().Add(time.Second), req.bytes); err != nil {
+		t.Fatal(err)
+	}
+	resp = sender0.next(t, &sender0.responses)
+	if _, err := chain.Unmarshal(resp.bytes, &txs); err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 0 {
+		t.Fatalf("unexpected txs %v", txs)
+	}
+
+	// Responses from other nodes (or to unknown requests) are ignored
+	if err := vm1.AppResponse(node1, resp.requestID, resp.bytes); err != nil {
+		t.Fatal(err)
+	}
+	if vm1.pull.Inflight() != 1 {
+		t.Fatal("request completed by the wrong node")
+	}
+	if err := vm1.AppResponse(node0, resp.requestID+1, resp.bytes); err != nil {
+		t.Fatal(err)
+	}
+	if vm1.pull.Inflight() != 1 {
+		t.Fatal("unknown request completed")
+	}
+
+	// Only one request is in flight per peer
+	if err := vm1.pull.PullTxs(); err != nil {
+		t.Fatal(err)
+	}
+	if requests, _ := sender1.sent(); requests != 0 {
+		t.Fatal("sent a second request to the same peer")
+	}
+
+	// A failed request frees its slot
+	if err := vm1.AppRequestFailed(node0, resp.requestID); err != nil {
+		t.Fatal(err)
+	}
+	if vm1.pull.Inflight() != 0 {
+		t.Fatal("failed request still in flight")
+	}
+
+	// Requests past their deadline are dropped
+	if err := vm1.pull.PullTxs(); err != nil {
+		t.Fatal(err)
+	}
+	req = sender1.next(t, &sender1.requests)
+	if err := vm0.AppRequest(node1, req.requestID, time.Now().Add(-time.Second), req.bytes); err != nil {
+		t.Fatal(err)
+	}
+	if _, responses := sender0.sent(); responses != 0 {
+		t.Fatal("responded to an expired request")
+	}
+
+	// The number of requests in flight is limited across peers
+	vm1.config.PullGossipMaxInflight = 2
+	for i := 0; i < 3; i++ {
+		if err := vm1.Connected(ids.GenerateTestShortID(), version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if err := vm1.pull.PullTxs(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests, _ := sender1.sent(); requests != 1 || vm1.pull.Inflight() != 2 {
+		t.Fatalf("unexpected number of requests in flight %d", vm1.pull.Inflight())
+	}
+
+	// Requests that never complete eventually expire
+	vm1.config.PullGossipTimeout = 0
+	for i := 0; i < 2; i++ {
+		if _, _, ok := vm1.pull.reserve(time.Now().Add(time.Minute)); !ok {
+			t.Fatal("unable to reserve request")
+		}
+	}
+
+	// Disconnected peers are not requested from
+	for nodeID := range vm1.pull.peers {
+		if err := vm1.Disconnected(nodeID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vm1.config.PullGossipMaxInflight = 10
+	if _, _, ok := vm1.pull.reserve(time.Now().Add(time.Hour)); ok {
+		t.Fatal("reserved request without peers")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+const (
+	// stateSyncAttempts is the number of times each server is tried.
+	stateSyncAttempts       = 3
+	stateSyncRetryDelay     = 5 * time.Second
+	stateSyncRequestTimeout = 30 * time.Second
+)
+
+// stateSync fetches the state at the state summary pinned by
+// [Config.StateSyncBlockID] and [Config.StateSyncHeight] from one of
+// [Config.StateSyncServers] and stores the summary block as the last accepted
+// block.
+//
+// Each attempt starts over from an empty state with the next server, so a
+// server that doesn't serve the pinned summary (or stops serving it halfway
+// through, which only happens once it has recorded two more summaries) is
+// skipped until it is retried after [stateSyncRetryDelay].
+//
+// Servers are reached through their public API because the engine drops app
+// responses until it has finished bootstrapping, which only starts after
+// [Initialize] returns the last accepted block. As the node can't ask its
+// peers which blocks were accepted before then, the summary block is pinned
+// by the operator: the servers are only trusted to be available, as the
+// pinned block is identified by its hash and commits to the state root the
+// fetched state is verified against. Syncing from peers over app messages is
+// not supported.
+func (vm *VM) stateSync() (err error) {
+	servers := vm.config.StateSyncServers
+	for i := 0; i < stateSyncAttempts*len(servers); i++ {
+		if i > 0 && i%len(servers) == 0 {
+			time.Sleep(stateSyncRetryDelay)
+		}
+		uri := servers[i%len(servers)]
+		if err = vm.syncFrom(uri); err == nil {
+			return nil
+		}
+		log.Warn("unable to sync state", "server", uri, "attempt", i/len(servers)+1, "error", err)
+	}
+	return err
+}
+
+func (vm *VM) syncFrom(uri string) error {
+	req := rpc.NewEndpointRequester(uri, PublicEndpoint, Name)
+	summary := new(StateSummaryReply)
+	if err := sendRequest(req, "stateSummary", struct{}{}, summary); err != nil {
+		return err
+	}
+	blkID, height := vm.config.StateSyncBlockID, vm.config.StateSyncHeight
+	if !servesStateSummary(summary, blkID, height) {
+		return fmt.Errorf(
+			"%w: server serves %s at height %d, expected %s at height %d",
+			ErrStaleStateSummary, summary.BlockID, summary.Height, blkID, height,
+		)
+	}
+	blk, err := vm.fetchBlock(req, blkID)
+	if err != nil {
+		return err
+	}
+	if blk.Hght != height {
+		return fmt.Errorf("%w: summary block at height %d, expected %d", ErrCorruption, blk.Hght, height)
+	}
+	log.Info("syncing state", "server", uri, "height", blk.Hght, "block", blk.ID())
+
+	vdb := versiondb.New(vm.db)
+	defer vdb.Abort()
+	syncer := chain.NewStateSyncer(vdb)
+	for _, prefix := range chain.SyncedPrefixes {
+		args := &StateChunkArgs{
+			Height: height,
+			Prefix: prefix,
+			Limit:  vm.config.StateSyncChunkSize,
+		}
+		for {
+			chunk := new(StateChunkReply)
+			if err := sendRequest(req, "stateChunk", args, chunk); err != nil {
+				return err
+			}
+			if err := syncer.Put(prefix, chunk.KeyValues); err != nil {
+				return err
+			}
+			if len(chunk.Next) == 0 {
+				break
+			}
+			args.Start = chunk.Next
+		}
+	}
+	if err := syncer.Verify(blk.StateRoot); err != nil {
+		return err
+	}
+
+	// Store the blocks in the lookback window of the summary block (and the
+	// first block outside of it), which are needed to verify its children
+	// (see [vm.lookback]).
+	curr := blk
+	for curr.Hght > 0 && blk.Tmstmp-curr.Tmstmp <= vm.genesis.LookbackWindow {
+		prnt, err := vm.fetchBlock(req, curr.Prnt)
+		if err != nil {
+			return err
+		}
+		if prnt.Hght != curr.Hght-1 {
+			return fmt.Errorf("%w: parent at height %d, expected %d", ErrCorruption, prnt.Hght, curr.Hght-1)
+		}
+		if err := chain.PutSyncedBlock(vdb, prnt, false); err != nil {
+			return err
+		}
+		curr = prnt
+	}
+	if err := chain.PutSyncedBlock(vdb, blk, true); err != nil {
+		return err
+	}
+	if err := vdb.Commit(); err != nil {
+		return err
+	}
+	log.Info("synced state", "height", blk.Hght, "block", blk.ID(), "oldest block", curr.Hght)
+	return nil
+}
+
+// servesStateSummary returns true if [summary] includes the summary at
+// [height] with [blkID].
+func servesStateSummary(summary *StateSummaryReply, blkID ids.ID, height uint64) bool {
+	if summary.BlockID == blkID && summary.Height == height {
+		return true
+	}
+	for _, s := range summary.Summaries {
+		if s.BlockID == blkID && s.Height == height {
+			return true
+		}
+	}
+	return false
+}
+
+// blockBytesReply is a [GetBlockReply] that is only decoded to its bytes.
+type blockBytesReply struct {
+	Bytes []byte `json:"bytes"`
+}
+
+// fetchBlock fetches the block with [blkID], which is identified by its hash
+// so doesn't need to be trusted.
+func (vm *VM) fetchBlock(req rpc.EndpointRequester, blkID ids.ID) (*chain.StatelessBlock, error) {
+	reply := new(blockBytesReply)
+	if err := sendRequest(req, "getBlockByID", &GetBlockByIDArgs{BlockID: blkID}, reply); err != nil {
+		return nil, err
+	}
+	blk, err := chain.ParseBlock(reply.Bytes, choices.Accepted, vm)
+	if err != nil {
+		return nil, err
+	}
+	if blk.ID() != blkID {
+		return nil, fmt.Errorf("%w: received block %s, expected %s", ErrCorruption, blk.ID(), blkID)
+	}
+	return blk, nil
+}
+
+func sendRequest(req rpc.EndpointRequester, method string, args interface{}, reply interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), stateSyncRequestTimeout)
+	defer cancel()
+	return req.SendRequest(ctx, method, args, reply)
+}
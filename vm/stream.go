@@ -0,0 +1,410 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+const (
+	// streamBufferSize is the number of events queued for a subscriber
+	// before it is considered too slow and disconnected.
+	streamBufferSize   = 1024
+	streamWriteTimeout = 10 * time.Second
+
+	maxSubscriptionSize    = 64 * 1024
+	maxSubscriptionFilters = 256
+)
+
+// Event types
+const (
+	BlockEvent   = "block"
+	TxEvent      = "tx"
+	SpaceEvent   = "space"
+	BalanceEvent = "balance"
+	ExpiryEvent  = "expiry"
+	ErrorEvent   = "error"
+)
+
+var ErrTooManyFilters = fmt.Errorf("subscription has more than %d filters", maxSubscriptionFilters)
+
+// Subscription selects the events pushed to a client of [StreamEndpoint]. It
+// is sent as a JSON message once connected and is replaced by any
+// subscription sent afterwards.
+type Subscription struct {
+	// Blocks sends an event for every accepted block.
+	Blocks bool `serialize:"true" json:"blocks,omitempty"`
+	// TxIDs sends an event once each of the txs is accepted.
+	TxIDs []ids.ID `serialize:"true" json:"txIds,omitempty"`
+	// Paths sends an event for every accepted tx that modifies a path of the
+	// form "space" or "space/keyprefix", and once a space expires.
+	Paths []string `serialize:"true" json:"paths,omitempty"`
+	// Addresses sends an event whenever the balance of the addresses changes.
+	Addresses []common.Address `serialize:"true" json:"addresses,omitempty"`
+	// Expirations sends an event whenever any space expires.
+	Expirations bool `serialize:"true" json:"expirations,omitempty"`
+}
+
+type pathFilter struct {
+	space     string
+	keyPrefix string
+}
+
+func (f *pathFilter) match(space string, key string, keyless bool) bool {
+	if f.space != space {
+		return false
+	}
+	if len(f.keyPrefix) == 0 {
+		return true
+	}
+	return !keyless && strings.HasPrefix(key, f.keyPrefix)
+}
+
+func (s *Subscription) pathFilters() ([]*pathFilter, error) {
+	if len(s.TxIDs)+len(s.Paths)+len(s.Addresses) > maxSubscriptionFilters {
+		return nil, ErrTooManyFilters
+	}
+	filters := make([]*pathFilter, len(s.Paths))
+	for i, path := range s.Paths {
+		segments := strings.SplitN(path, parser.Delimiter, 2)
+		if err := parser.CheckContents(segments[0]); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, path)
+		}
+		f := &pathFilter{space: segments[0]}
+		if len(segments) == 2 && len(segments[1]) > 0 {
+			if err := parser.CheckContents(segments[1]); err != nil {
+				return nil, fmt.Errorf("%w: %q", err, path)
+			}
+			f.keyPrefix = segments[1]
+		}
+		filters[i] = f
+	}
+	return filters, nil
+}
+
+// Event is pushed to the clients of [StreamEndpoint] whose [Subscription]
+// selects it. Besides the block that caused the event, only the fields
+// relevant to [Type] are set.
+type Event struct {
+	Type      string `serialize:"true" json:"type"`
+	BlockID   ids.ID `serialize:"true" json:"blockId"`
+	Height    uint64 `serialize:"true" json:"height"`
+	Timestamp int64  `serialize:"true" json:"timestamp"`
+
+	// Txs is the number of txs in the block of a [BlockEvent].
+	Txs int `serialize:"true" json:"txs,omitempty"`
+	// Activity is the accepted tx of a [TxEvent] or [SpaceEvent].
+	Activity *chain.Activity `serialize:"true" json:"activity,omitempty"`
+	// Expired is the space removed in an [ExpiryEvent].
+	Expired *chain.ExpiredSpace `serialize:"true" json:"expired,omitempty"`
+	// Address and Balance are set in a [BalanceEvent].
+	Address *common.Address `serialize:"true" json:"address,omitempty"`
+	Balance *uint64         `serialize:"true" json:"balance,omitempty"`
+	// Error describes why a [Subscription] was rejected in an [ErrorEvent].
+	Error string `serialize:"true" json:"error,omitempty"`
+}
+
+// StreamServer serves [StreamEndpoint], pushing the events of each accepted
+// block to the websocket clients subscribed to them.
+//
+// Clients that don't read their events fast enough are disconnected.
+type StreamServer struct {
+	vm       *VM
+	upgrader websocket.Upgrader
+
+	// [l] must be held when accessing [subscribers]
+	l           sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	conn   *websocket.Conn
+	events chan *Event
+	done   chan struct{}
+	once   sync.Once
+
+	// [l] must be held when accessing the fields below
+	l        sync.Mutex
+	sub      *Subscription
+	paths    []*pathFilter
+	txIDs    ids.Set
+	balances map[common.Address]uint64
+}
+
+func (vm *VM) NewStreamServer() *StreamServer {
+	return &StreamServer{
+		vm: vm,
+		upgrader: websocket.Upgrader{
+			// The stream is as public as [PublicEndpoint], so browsers may
+			// connect from any origin.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		subscribers: map[*subscriber]struct{}{},
+	}
+}
+
+// Subscribers returns the number of connected clients.
+func (s *StreamServer) Subscribers() int {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return len(s.subscribers)
+}
+
+func (s *StreamServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Subscribers() >= s.vm.config.StreamMaxSubscribers {
+		http.Error(w, "too many subscribers", http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("unable to upgrade stream connection", "error", err)
+		return
+	}
+	conn.SetReadLimit(maxSubscriptionSize)
+	sub := &subscriber{
+		conn:   conn,
+		events: make(chan *Event, streamBufferSize),
+		done:   make(chan struct{}),
+		sub:    &Subscription{},
+		txIDs:  ids.Set{},
+	}
+	s.l.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.l.Unlock()
+
+	go sub.write()
+	s.read(sub)
+
+	s.l.Lock()
+	delete(s.subscribers, sub)
+	s.l.Unlock()
+	sub.close()
+}
+
+// read applies the subscriptions sent by [sub] until it disconnects.
+func (s *StreamServer) read(sub *subscriber) {
+	for {
+		subscription := new(Subscription)
+		if err := sub.conn.ReadJSON(subscription); err != nil {
+			var closeErr *websocket.CloseError
+			select {
+			case <-sub.done:
+			default:
+				if !errors.As(err, &closeErr) {
+					log.Debug("closing stream connection", "error", err)
+				}
+			}
+			return
+		}
+		if err := s.subscribe(sub, subscription); err != nil {
+			sub.send(&Event{Type: ErrorEvent, Error: err.Error()})
+		}
+	}
+}
+
+func (s *StreamServer) subscribe(sub *subscriber, subscription *Subscription) error {
+	paths, err := subscription.pathFilters()
+	if err != nil {
+		return err
+	}
+
+	// Streams are served without the context lock, so take it to read the
+	// balances and to set the subscription before the next block is
+	// published (see [Publish])
+	s.vm.ctx.Lock.RLock()
+	defer s.vm.ctx.Lock.RUnlock()
+	balances := make(map[common.Address]uint64, len(subscription.Addresses))
+	for _, addr := range subscription.Addresses {
+		// Only changes from the balance at the time of subscribing are sent
+		bal, err := chain.GetBalance(s.vm.db, addr)
+		if err != nil {
+			return err
+		}
+		balances[addr] = bal
+	}
+
+	sub.l.Lock()
+	defer sub.l.Unlock()
+	sub.sub = subscription
+	sub.paths = paths
+	sub.txIDs = ids.NewSet(len(subscription.TxIDs))
+	sub.txIDs.Add(subscription.TxIDs...)
+	sub.balances = balances
+	return nil
+}
+
+// write sends the queued events of [sub] until it is closed.
+func (sub *subscriber) write() {
+	for {
+		select {
+		case ev := <-sub.events:
+			if err := sub.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+				sub.close()
+				return
+			}
+			if err := sub.conn.WriteJSON(ev); err != nil {
+				log.Debug("unable to write stream event", "error", err)
+				sub.close()
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// send queues [ev] for [sub], disconnecting it if its queue is full.
+func (sub *subscriber) send(ev *Event) {
+	select {
+	case sub.events <- ev:
+	default:
+		log.Debug("disconnecting slow stream subscriber", "remote", sub.conn.RemoteAddr())
+		sub.close()
+	}
+}
+
+// close disconnects [sub], which causes [StreamServer.read] to return.
+func (sub *subscriber) close() {
+	sub.once.Do(func() {
+		close(sub.done)
+		_ = sub.conn.Close()
+	})
+}
+
+// Close disconnects all subscribers.
+func (s *StreamServer) Close() {
+	s.l.Lock()
+	defer s.l.Unlock()
+	for sub := range s.subscribers {
+		sub.close()
+	}
+}
+
+// Publish sends the events of the accepted block [b] to its subscribers. The
+// caller must hold the context lock.
+func (s *StreamServer) Publish(b *chain.StatelessBlock) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if len(s.subscribers) == 0 {
+		return
+	}
+
+	base := Event{BlockID: b.ID(), Height: b.Hght, Timestamp: b.Tmstmp}
+	activity := make([]*chain.Activity, len(b.Txs))
+	for i, tx := range b.Txs {
+		a := tx.Activity()
+		a.Tmstmp = b.Tmstmp
+		activity[i] = a
+	}
+	balances := map[common.Address]uint64{}
+	for sub := range s.subscribers {
+		sub.l.Lock()
+		sub.publish(s.vm, b, &base, activity, balances)
+		sub.l.Unlock()
+	}
+}
+
+// publish assumes [sub.l] is held. [balances] caches the balances read across
+// subscribers.
+func (sub *subscriber) publish(
+	vm *VM,
+	b *chain.StatelessBlock,
+	base *Event,
+	activity []*chain.Activity,
+	balances map[common.Address]uint64,
+) {
+	newEvent := func(typ string) *Event {
+		ev := *base
+		ev.Type = typ
+		return &ev
+	}
+
+	if sub.sub.Blocks {
+		ev := newEvent(BlockEvent)
+		ev.Txs = len(b.Txs)
+		sub.send(ev)
+	}
+	for i, tx := range b.Txs {
+		a := activity[i]
+		if sub.txIDs.Contains(a.TxID) {
+			sub.txIDs.Remove(a.TxID)
+			ev := newEvent(TxEvent)
+			ev.Activity = a
+			sub.send(ev)
+		}
+		if sub.matchTx(tx, a) {
+			ev := newEvent(SpaceEvent)
+			ev.Activity = a
+			sub.send(ev)
+		}
+	}
+	for _, expired := range b.Expired {
+		if !sub.sub.Expirations && !sub.matchPath(expired.Space, "", true) {
+			continue
+		}
+		ev := newEvent(ExpiryEvent)
+		ev.Expired = expired
+		sub.send(ev)
+	}
+	for addr, last := range sub.balances {
+		bal, ok := balances[addr]
+		if !ok {
+			var err error
+			bal, err = chain.GetBalance(vm.db, addr)
+			if err != nil {
+				log.Warn("unable to read balance for stream", "address", addr, "error", err)
+				continue
+			}
+			balances[addr] = bal
+		}
+		if bal == last {
+			continue
+		}
+		sub.balances[addr] = bal
+		ev := newEvent(BalanceEvent)
+		addr := addr
+		ev.Address = &addr
+		ev.Balance = &bal
+		sub.send(ev)
+	}
+}
+
+// matchTx returns true if [tx] (with activity [a]) modifies a path [sub] is
+// subscribed to.
+func (sub *subscriber) matchTx(tx *chain.Transaction, a *chain.Activity) bool {
+	if len(sub.paths) == 0 {
+		return false
+	}
+	if batch, ok := tx.UnsignedTransaction.(*chain.BatchTx); ok {
+		for _, op := range batch.Ops {
+			if sub.matchPath(op.Space, op.Key, op.Typ == chain.Lifeline) {
+				return true
+			}
+		}
+		return false
+	}
+	return len(a.Space) > 0 && sub.matchPath(a.Space, a.Key, len(a.Key) == 0)
+}
+
+func (sub *subscriber) matchPath(space string, key string, keyless bool) bool {
+	for _, f := range sub.paths {
+		if f.match(space, key, keyless) {
+			return true
+		}
+	}
+	return false
+}
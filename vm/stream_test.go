@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestStream(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: sender, Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+	server := httptest.NewServer(vm.stream)
+	t.Cleanup(server.Close)
+
+	dial := func(s *Subscription) *websocket.Conn {
+		t.Helper()
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		if err := conn.WriteJSON(s); err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}
+	next := func(conn *websocket.Conn) *Event {
+		t.Helper()
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		ev := new(Event)
+		if err := conn.ReadJSON(ev); err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	waitSubscribed := func(count int) {
+		t.Helper()
+		for i := 0; i < 100 && vm.stream.Subscribers() != count; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if vm.stream.Subscribers() != count {
+			t.Fatalf("unexpected subscribers %d", vm.stream.Subscribers())
+		}
+	}
+
+	// Invalid subscriptions are rejected
+	conn := dial(&Subscription{Paths: []string{"Hello"}})
+	if ev := next(conn); ev.Type != ErrorEvent {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	tx := newClaimTx(t, vm, priv, "hello", genesis.MinPrice)
+	conn = dial(&Subscription{
+		Blocks:    true,
+		TxIDs:     []ids.ID{tx.ID()},
+		Paths:     []string{"hello"},
+		Addresses: []common.Address{sender},
+	})
+	other := dial(&Subscription{Paths: []string{"hello/foo", "world"}})
+	waitSubscribed(3)
+	// Subscriptions are applied asynchronously
+	time.Sleep(100 * time.Millisecond)
+
+	if errs := vm.Submit(tx); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := next(conn)
+	if ev.Type != BlockEvent || ev.BlockID != blk.ID() || ev.Height != 1 || ev.Txs != 1 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	for _, typ := range []string{TxEvent, SpaceEvent} {
+		ev = next(conn)
+		if ev.Type != typ || ev.Activity.TxID != tx.ID() || ev.Activity.Space != "hello" {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	}
+	ev = next(conn)
+	bal, err := chain.GetBalance(vm.db, sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != BalanceEvent || *ev.Address != sender || *ev.Balance != bal {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	// [other] skipped the claim (which doesn't modify any key) but receives
+	// the expiry of a subscribed space
+	vm.stream.Publish(&chain.StatelessBlock{
+		StatefulBlock: &chain.StatefulBlock{Hght: 2},
+		Expired:       []*chain.ExpiredSpace{{Space: "world", Owner: sender}},
+	})
+	ev = next(other)
+	if ev.Type != ExpiryEvent || ev.Height != 2 || ev.Expired.Space != "world" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	// Subscribers are disconnected on shutdown
+	vm.stream.Close()
+	waitSubscribed(0)
+}
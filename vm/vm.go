@@ -7,7 +7,6 @@ package vm
 import (
 	ejson "encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -24,6 +23,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/gorilla/rpc/v2"
 	log "github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
 
 	avagoversion "github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/spacesvm/chain"
@@ -34,6 +34,8 @@ import (
 const (
 	Name           = "spacesvm"
 	PublicEndpoint = "/public"
+	AdminEndpoint  = "/admin"
+	StreamEndpoint = "/stream"
 )
 
 var (
@@ -53,6 +55,12 @@ type VM struct {
 	mempool   *mempool.Mempool
 	appSender common.AppSender
 	network   *PushNetwork
+	pull      *PullNetwork
+	peers     *PeerTracker
+	stream    *StreamServer
+
+	metrics      *metrics
+	chainMetrics *chain.Metrics
 
 	// cache block objects to optimize "GetBlockStateless"
 	// only put when a block is accepted
@@ -84,6 +92,7 @@ type VM struct {
 	doneGossip  chan struct{}
 	donePrune   chan struct{}
 	doneCompact chan struct{}
+	doneFlush   chan struct{}
 }
 
 const (
@@ -122,9 +131,13 @@ func (vm *VM) Initialize(
 	vm.doneGossip = make(chan struct{})
 	vm.donePrune = make(chan struct{})
 	vm.doneCompact = make(chan struct{})
+	vm.doneFlush = make(chan struct{})
 
 	vm.appSender = appSender
 	vm.network = vm.NewPushNetwork()
+	vm.pull = vm.NewPullNetwork()
+	vm.peers = NewPeerTracker(&vm.config)
+	vm.stream = vm.NewStreamServer()
 
 	vm.blocks = &cache.LRU{Size: blocksLRUSize}
 	vm.verifiedBlocks = make(map[ids.ID]*chain.StatelessBlock)
@@ -153,7 +166,37 @@ func (vm *VM) Initialize(
 	vm.targetRangeUnits = targetUnitsPerSecond * uint64(vm.genesis.LookbackWindow)
 	log.Debug("loaded genesis", "genesis", string(genesisBytes), "target range units", vm.targetRangeUnits)
 
-	vm.mempool = mempool.New(vm.genesis, vm.config.MempoolSize)
+	vm.mempool = mempool.New(vm.genesis, vm.config.MempoolSize, vm.config.MempoolSenderQuota, vm.balance)
+
+	// Register metrics with the gatherer of the chain (if any)
+	registry := prometheus.NewRegistry()
+	vm.metrics, err = newMetrics(registry)
+	if err != nil {
+		return err
+	}
+	vm.chainMetrics, err = chain.NewMetrics(registry)
+	if err != nil {
+		return err
+	}
+	if err := vm.mempool.RegisterMetrics(registry); err != nil {
+		return err
+	}
+	if vm.ctx.Metrics != nil {
+		if err := vm.ctx.Metrics.Register(registry); err != nil {
+			return err
+		}
+	}
+
+	if !has && len(vm.config.StateSyncServers) > 0 {
+		if vm.config.StateSyncBlockID == ids.Empty {
+			return ErrUnpinnedSummary
+		}
+		if err := vm.stateSync(); err != nil {
+			log.Warn("state sync failed, initializing from genesis", "error", err)
+		} else {
+			has = true
+		}
+	}
 
 	if has { //nolint:nestif
 		blkID, err := chain.GetLastAccepted(vm.db)
@@ -171,8 +214,22 @@ func (vm *VM) Initialize(
 		vm.preferred, vm.lastAccepted = blkID, blk
 		log.Info("initialized spacesvm from last accepted", "block", blkID)
 	} else {
+		// Set Balances
+		if err := vm.genesis.Load(vm.db, vm.AirdropData); err != nil {
+			log.Error("could not set genesis allocation", "err", err)
+			return err
+		}
+
+		// Commit to the genesis allocations in the genesis block
+		root, err := chain.GetStateRoot(vm.db)
+		if err != nil {
+			log.Error("could not get genesis state root", "err", err)
+			return err
+		}
+		genesis := vm.genesis.StatefulBlock()
+		genesis.StateRoot = root
 		genesisBlk, err := chain.ParseStatefulBlock(
-			vm.genesis.StatefulBlock(),
+			genesis,
 			nil,
 			choices.Accepted,
 			vm,
@@ -182,12 +239,6 @@ func (vm *VM) Initialize(
 			return err
 		}
 
-		// Set Balances
-		if err := vm.genesis.Load(vm.db, vm.AirdropData); err != nil {
-			log.Error("could not set genesis allocation", "err", err)
-			return err
-		}
-
 		if err := chain.SetLastAccepted(vm.db, genesisBlk); err != nil {
 			log.Error("could not set genesis as last accepted", "err", err)
 			return err
@@ -198,10 +249,26 @@ func (vm *VM) Initialize(
 	}
 	vm.AirdropData = nil
 
+	// Journal the state at the last state summary so that it can be served to
+	// syncing nodes while more blocks are accepted
+	if vm.config.StateSyncInterval > 0 {
+		vm.db = chain.NewJournalDB(vm.db)
+	} else if err := chain.DeleteStateSummary(vm.db); err != nil {
+		log.Error("could not delete state summary", "err", err)
+		return err
+	}
+
+	// Restore the txs that were pending before the last shutdown
+	if err := vm.restoreMempool(); err != nil {
+		log.Error("could not restore mempool", "err", err)
+		return err
+	}
+
 	go vm.builder.Build()
 	go vm.builder.Gossip()
 	go vm.prune()
 	go vm.compact()
+	go vm.flush()
 	return nil
 }
 
@@ -238,9 +305,16 @@ func (vm *VM) Shutdown() error {
 	<-vm.doneGossip
 	<-vm.donePrune
 	<-vm.doneCompact
+	<-vm.doneFlush
+	vm.stream.Close()
 	if vm.ctx == nil {
 		return nil
 	}
+	if vm.config.MempoolFlushInterval > 0 {
+		if err := vm.flushMempool(); err != nil {
+			log.Warn("unable to flush mempool", "error", err)
+		}
+	}
 	return vm.db.Close()
 }
 
@@ -278,6 +352,16 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 		return nil, err
 	}
 	apis[PublicEndpoint] = public
+	// Events are published under the context lock, so the stream must not
+	// hold it while clients are connected
+	apis[StreamEndpoint] = &common.HTTPHandler{LockOptions: common.NoLock, Handler: vm.stream}
+	if vm.config.AdminAPIEnabled {
+		admin, err := newHandler(Name, &AdminService{vm: vm})
+		if err != nil {
+			return nil, err
+		}
+		apis[AdminEndpoint] = admin
+	}
 	return apis, nil
 }
 
@@ -289,36 +373,29 @@ func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
 
 // implements "snowmanblock.ChainVM.commom.VM.AppHandler"
 func (vm *VM) AppRequest(nodeID ids.ShortID, requestID uint32, deadline time.Time, request []byte) error {
-	// (currently) no app-specific messages
-	return nil
+	return vm.pull.HandleRequest(nodeID, requestID, deadline, request)
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.AppHandler"
 func (vm *VM) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
-	// (currently) no app-specific messages
-	return nil
+	return vm.pull.HandleFailed(nodeID, requestID)
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.AppHandler"
 func (vm *VM) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
-	// (currently) no app-specific messages
-	return nil
-}
-
-// implements "snowmanblock.ChainVM.commom.VM.health.Checkable"
-func (vm *VM) HealthCheck() (interface{}, error) {
-	return http.StatusOK, nil
+	return vm.pull.HandleResponse(nodeID, requestID, response)
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.validators.Connector"
 func (vm *VM) Connected(id ids.ShortID, nodeVersion avagoversion.Application) error {
-	// no-op
+	vm.pull.Connected(id)
 	return nil
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.validators.Connector"
 func (vm *VM) Disconnected(id ids.ShortID) error {
-	// no-op
+	vm.pull.Disconnected(id)
+	vm.peers.Disconnected(id)
 	return nil
 }
 
@@ -401,36 +478,71 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 }
 
 func (vm *VM) Submit(txs ...*chain.Transaction) (errs []error) {
-	blk, err := vm.GetStatelessBlock(vm.preferred)
-	if err != nil {
-		return []error{err}
-	}
 	now := time.Now().Unix()
-	ctx, err := vm.ExecutionContext(now, blk)
+	vdb, ctx, err := vm.submitState(now)
 	if err != nil {
 		return []error{err}
 	}
-	vdb := versiondb.New(vm.db)
-
-	// Expire outdated spaces before checking submission validity
-	if err := chain.ExpireNext(vdb, blk.Tmstmp, now, true); err != nil {
-		return []error{err}
-	}
 
 	for _, tx := range txs {
-		if err := vm.submit(tx, vdb, now, ctx); err != nil {
+		// Each tx is checked against [vdb] on its own, so discard its changes
+		// (even if it failed halfway) without discarding those made by
+		// [submitState]
+		tvdb := versiondb.New(vdb)
+		err := vm.submit(tx, tvdb, now, ctx)
+		tvdb.Abort()
+		if err != nil {
 			log.Debug("failed to submit transaction",
 				"tx", tx.ID(),
 				"error", err,
 			)
 			errs = append(errs, err)
-			continue
 		}
-		vdb.Abort()
 	}
 	return errs
 }
 
+// submitState returns the state that txs submitted at [now] are checked
+// against and its execution context.
+func (vm *VM) submitState(now int64) (*versiondb.Database, *chain.Context, error) {
+	blk, err := vm.GetStatelessBlock(vm.preferred)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err := vm.ExecutionContext(now, blk)
+	if err != nil {
+		return nil, nil, err
+	}
+	vdb := versiondb.New(vm.db)
+
+	// Expire outdated spaces before checking submission validity
+	if err := chain.ExpireNext(vm.genesis, vdb, blk.Tmstmp, now, true); err != nil {
+		return nil, nil, err
+	}
+	if err := chain.SettleAuctions(vm.genesis, vdb, blk.Tmstmp, now); err != nil {
+		return nil, nil, err
+	}
+	return vdb, ctx, nil
+}
+
+// Simulate executes [tx] like [Submit] without adding it to the mempool and
+// returns the changes it would make to the state. [tx] must be initialized.
+func (vm *VM) Simulate(tx *chain.Transaction) ([]*chain.StateChange, error) {
+	now := time.Now().Unix()
+	vdb, ctx, err := vm.submitState(now)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.ExecuteBase(vm.genesis); err != nil {
+		return nil, err
+	}
+	sdb := chain.NewStateDB(versiondb.New(vdb))
+	if err := tx.Execute(vm.genesis, sdb, chain.DummyBlock(now, tx), ctx); err != nil {
+		return nil, err
+	}
+	return chain.Diff(sdb, vdb)
+}
+
 func (vm *VM) submit(tx *chain.Transaction, db database.Database, blkTime int64, ctx *chain.Context) error {
 	if err := tx.Init(vm.genesis); err != nil {
 		return err
@@ -442,6 +554,9 @@ func (vm *VM) submit(tx *chain.Transaction, db database.Database, blkTime int64,
 	if err := tx.Execute(vm.genesis, db, dummy, ctx); err != nil {
 		return err
 	}
+	if err := vm.mempool.Check(tx); err != nil {
+		return err
+	}
 	vm.mempool.Add(tx)
 	return nil
 }
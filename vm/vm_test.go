@@ -4,12 +4,18 @@
 package vm
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
 	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/mempool"
 )
 
 func TestBlockCache(t *testing.T) {
@@ -28,6 +34,7 @@ func TestBlockCache(t *testing.T) {
 		blocks:         &cache.LRU{Size: 3},
 		verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
 	}
+	vm.stream = vm.NewStreamServer()
 
 	// put the block into the cache "vm.blocks"
 	// and delete from "vm.verifiedBlocks"
@@ -44,3 +51,164 @@ func TestBlockCache(t *testing.T) {
 		t.Fatalf("block expected %+v, got %+v", blk, blk2)
 	}
 }
+
+func TestFeeMarket(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.FeeMarketEnabled = true
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: sender, Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+
+	// Txs using [Price] are rejected
+	if errs := vm.Submit(newClaimTx(t, vm, priv, "hello", genesis.MinPrice)); len(errs) != 1 ||
+		!errors.Is(errs[0], chain.ErrInvalidPrice) {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	utx := &chain.ClaimTx{
+		BaseTx: &chain.BaseTx{
+			BlockID:     vm.preferred,
+			Magic:       genesis.Magic,
+			MaxPrice:    10,
+			PriorityTip: 2,
+		},
+		Space: "hello",
+	}
+	dh, err := chain.DigestHash(utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := chain.Sign(dh, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := chain.NewTx(utx, sig)
+	if err := tx.Init(genesis); err != nil {
+		t.Fatal(err)
+	}
+	if errs := vm.Submit(tx); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The sender pays the base price plus the tip
+	r, ok, err := chain.GetReceipt(vm.db, tx.ID())
+	if err != nil || !ok {
+		t.Fatalf("receipt missing: %v", err)
+	}
+	if cost := blk.(*chain.StatelessBlock).Cost; cost != 0 {
+		t.Fatalf("block cost expected 0, got %d", cost)
+	}
+	if price := genesis.MinPrice + 2; r.Price != price || r.Fee != tx.FeeUnits(genesis)*price {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+}
+
+func TestMempoolPersistence(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: crypto.PubkeyToAddress(priv.PublicKey), Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+
+	pending := newClaimTx(t, vm, priv, "hello", 10)
+	if errs := vm.Submit(pending); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if err := vm.flushMempool(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Txs whose block ID is not in the lookback window are not restored
+	expired := newClaimTx(t, vm, priv, "world", 10)
+	expired.SetBlockID(ids.GenerateTestID())
+	if err := expired.Init(vm.genesis); err != nil {
+		t.Fatal(err)
+	}
+	txs, err := chain.GetPendingTxs(vm.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("pending txs expected 1, got %d", len(txs))
+	}
+	if err := txs[0].Init(vm.genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.SetPendingTxs(vm.db, append(txs, expired)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a restart
+	vm.mempool = mempool.New(vm.genesis, vm.config.MempoolSize, vm.config.MempoolSenderQuota, vm.balance)
+	if err := vm.restoreMempool(); err != nil {
+		t.Fatal(err)
+	}
+	if vm.mempool.Len() != 1 || !vm.mempool.Has(pending.ID()) {
+		t.Fatalf("unexpected mempool %v", vm.mempool.Txs())
+	}
+}
+
+func TestSubmitExpiredSpaces(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := chain.DefaultGenesis()
+	genesis.Magic = 5
+	genesis.CustomAllocation = []*chain.CustomAllocation{
+		{Address: crypto.PubkeyToAddress(priv.PublicKey), Balance: 10000000},
+	}
+	genesisBytes, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := newTestVM(t, genesisBytes, &fakeAppSender{})
+
+	// Both spaces expire after the preferred block, so every tx in the batch
+	// must be checked against the state where they are expired
+	for i, spc := range []string{"a", "b"} {
+		info := &chain.SpaceInfo{Owner: common.Address{0x1}, RawSpace: ids.ShortID{byte(i)}, Expiry: 10}
+		if err := chain.PutSpaceInfo(vm.db, []byte(spc), info, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if errs := vm.Submit(
+		newClaimTx(t, vm, priv, "a", 10),
+		newClaimTx(t, vm, priv, "b", 10),
+	); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if vm.mempool.Len() != 2 {
+		t.Fatalf("unexpected mempool %v", vm.mempool.Txs())
+	}
+}